@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	pgrepo "github.com/acme/outbound-call-campaign/internal/repository/postgres"
+	"github.com/acme/outbound-call-campaign/internal/service/phonebackfill"
+)
+
+// phonebackfill is a one-off job that rewrites phone numbers stored before E.164 normalisation.
+// It exits with status 1 when rows were left for manual review.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	configPath := flag.String("config", getEnv("CONFIG_FILE", "configs/config.yaml"), "path to configuration file")
+	batchSize := flag.Int("batch-size", 500, "rows read per query")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	container, err := app.Build(ctx, *configPath)
+	if err != nil {
+		log.Fatalf("failed to bootstrap application: %v", err)
+	}
+	defer container.Close(context.Background())
+
+	job := phonebackfill.New(pgrepo.NewPhoneNumberRepository(container.Postgres.DB()), container.Logger, *batchSize, *dryRun)
+	report, err := job.Run(ctx)
+	container.Logger.Info("phone backfill: finished",
+		zap.Bool("dry_run", *dryRun),
+		zap.Int("targets_scanned", report.TargetsScanned),
+		zap.Int("targets_normalized", report.TargetsNormalized),
+		zap.Int("targets_flagged", report.TargetsFlagged),
+		zap.Int("targets_unresolved", report.TargetsUnresolved),
+		zap.Int("suppressed_scanned", report.SuppressedScanned),
+		zap.Int("suppressed_normalized", report.SuppressedNormalized),
+		zap.Int("suppressed_unresolved", report.SuppressedUnresolved))
+	if err != nil {
+		log.Fatalf("phone backfill failed: %v", err)
+	}
+	if report.Unresolved() {
+		container.Close(context.Background())
+		os.Exit(1)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS completion_summary JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_status_updated ON campaigns (status, updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaigns_status_updated;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS completion_summary;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS start_at TIMESTAMPTZ;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS end_at TIMESTAMPTZ;
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS chk_campaigns_window;
+ALTER TABLE campaigns ADD CONSTRAINT chk_campaigns_window CHECK (start_at IS NULL OR end_at IS NULL OR end_at > start_at);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_start_at ON campaigns (start_at) WHERE status = 'pending' AND start_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_campaigns_end_at ON campaigns (end_at) WHERE end_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaigns_end_at;
+DROP INDEX IF EXISTS idx_campaigns_start_at;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS chk_campaigns_window;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS end_at;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS start_at;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE campaign_events ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT 'system';
+
+CREATE INDEX IF NOT EXISTS idx_campaign_events_campaign_id ON campaign_events (campaign_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_events_campaign_id;
+ALTER TABLE campaign_events DROP COLUMN IF EXISTS actor;
+-- +goose StatementEnd
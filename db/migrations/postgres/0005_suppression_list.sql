@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- campaign_id is NULL for numbers suppressed across every campaign.
+CREATE TABLE IF NOT EXISTS suppressed_numbers (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id UUID,
+    phone_number TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT 'system',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressed_numbers_global ON suppressed_numbers (phone_number) WHERE campaign_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressed_numbers_campaign ON suppressed_numbers (campaign_id, phone_number) WHERE campaign_id IS NOT NULL;
+
+ALTER TABLE campaign_targets ADD COLUMN IF NOT EXISTS state_reason TEXT;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'citus') THEN
+        PERFORM create_reference_table('suppressed_numbers');
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE campaign_targets DROP COLUMN IF EXISTS state_reason;
+DROP TABLE IF EXISTS suppressed_numbers;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ISO 3166 region used to read target numbers written without a country code.
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS default_country TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE campaigns DROP COLUMN IF EXISTS default_country;
+-- +goose StatementEnd
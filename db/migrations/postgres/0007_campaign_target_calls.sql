@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE campaign_targets ADD COLUMN IF NOT EXISTS call_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_call_id ON campaign_targets (campaign_id, call_id) WHERE call_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_call_id;
+ALTER TABLE campaign_targets DROP COLUMN IF EXISTS call_id;
+-- +goose StatementEnd
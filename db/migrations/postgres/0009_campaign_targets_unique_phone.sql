@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Numbers listed twice in the same campaign must be collapsed before enforcing uniqueness. The row
+-- with the most call history is kept (a linked call, then the most attempts, then the latest
+-- attempt, then the earliest row); the others are moved to campaign_target_duplicates so they can
+-- be reviewed and are restored by the down migration.
+CREATE TABLE IF NOT EXISTS campaign_target_duplicates (LIKE campaign_targets INCLUDING DEFAULTS);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'citus') THEN
+        PERFORM create_distributed_table('campaign_target_duplicates', 'campaign_id');
+    END IF;
+END $$;
+
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY campaign_id, phone_number
+        ORDER BY (call_id IS NOT NULL) DESC, attempt_count DESC, last_attempt_at DESC NULLS LAST, created_at, id
+    ) AS rank
+    FROM campaign_targets
+),
+moved AS (
+    DELETE FROM campaign_targets t
+    USING ranked
+    WHERE t.id = ranked.id AND ranked.rank > 1
+    RETURNING t.*
+)
+INSERT INTO campaign_target_duplicates SELECT * FROM moved;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_targets_campaign_phone ON campaign_targets (campaign_id, phone_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_campaign_phone;
+INSERT INTO campaign_targets SELECT * FROM campaign_target_duplicates ON CONFLICT (id) DO NOTHING;
+DROP TABLE IF EXISTS campaign_target_duplicates;
+-- +goose StatementEnd
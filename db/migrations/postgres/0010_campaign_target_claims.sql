@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A scheduler claims pending targets by moving them to queued with an owner and an expiry.
+-- Claims that expire before a call is attached are picked up again.
+ALTER TABLE campaign_targets ADD COLUMN IF NOT EXISTS claim_owner TEXT;
+ALTER TABLE campaign_targets ADD COLUMN IF NOT EXISTS claim_expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_claim_expiry ON campaign_targets (campaign_id, claim_expires_at)
+    WHERE state = 'queued' AND call_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_claim_expiry;
+ALTER TABLE campaign_targets DROP COLUMN IF EXISTS claim_expires_at;
+ALTER TABLE campaign_targets DROP COLUMN IF EXISTS claim_owner;
+-- +goose StatementEnd
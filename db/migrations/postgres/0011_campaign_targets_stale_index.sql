@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_in_flight_updated ON campaign_targets (state, updated_at)
+    WHERE state IN ('queued', 'dialing');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_in_flight_updated;
+-- +goose StatementEnd
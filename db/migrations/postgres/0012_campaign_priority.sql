@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- priority orders campaigns strictly; weight splits dispatch between campaigns of equal priority.
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS weight INT NOT NULL DEFAULT 1 CHECK (weight > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE campaigns DROP COLUMN IF EXISTS weight;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS priority;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS holiday_calendar_dates (
+    calendar_id UUID NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    holiday_date DATE NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (calendar_id, holiday_date)
+);
+
+CREATE TABLE IF NOT EXISTS campaign_holiday_calendars (
+    campaign_id UUID NOT NULL,
+    calendar_id UUID NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    PRIMARY KEY (campaign_id, calendar_id)
+);
+
+-- A row without minutes blacks out the whole date; rows with minutes replace the weekly
+-- business hours on that date.
+CREATE TABLE IF NOT EXISTS campaign_date_exceptions (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL,
+    exception_date DATE NOT NULL,
+    start_minute INTEGER,
+    end_minute INTEGER,
+    reason TEXT NOT NULL DEFAULT '',
+    CHECK ((start_minute IS NULL) = (end_minute IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_date_exceptions_campaign ON campaign_date_exceptions (campaign_id, exception_date);
+
+DROP TRIGGER IF EXISTS trg_holiday_calendars_updated ON holiday_calendars;
+CREATE TRIGGER trg_holiday_calendars_updated
+BEFORE UPDATE ON holiday_calendars
+FOR EACH ROW EXECUTE FUNCTION set_timestamp();
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'citus') THEN
+        PERFORM create_reference_table('holiday_calendars');
+        PERFORM create_reference_table('holiday_calendar_dates');
+        PERFORM create_reference_table('campaign_holiday_calendars');
+        PERFORM create_reference_table('campaign_date_exceptions');
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS campaign_date_exceptions;
+DROP TABLE IF EXISTS campaign_holiday_calendars;
+DROP TABLE IF EXISTS holiday_calendar_dates;
+DROP TRIGGER IF EXISTS trg_holiday_calendars_updated ON holiday_calendars;
+DROP TABLE IF EXISTS holiday_calendars;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- recipient_local_time reads the campaign's calling windows in each target's own time zone.
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS recipient_local_time BOOLEAN NOT NULL DEFAULT FALSE;
+-- An empty time_zone means the target's zone is unknown and the campaign's zone applies.
+ALTER TABLE campaign_targets ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_claimable_zone ON campaign_targets (campaign_id, time_zone)
+    WHERE state IN ('pending', 'staged', 'queued');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_claimable_zone;
+ALTER TABLE campaign_targets DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS recipient_local_time;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- config_version counts changes to the limits workers apply to in-flight calls.
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS config_version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE campaigns DROP COLUMN IF EXISTS config_version;
+-- +goose StatementEnd
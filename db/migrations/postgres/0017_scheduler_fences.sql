@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Fencing tokens of scheduler leader leases. A new leader raises its lease's token before it
+-- writes, and every write made on behalf of a leader first checks the token under a share lock,
+-- so a deposed leader that has not yet noticed cannot claim or move targets any more.
+CREATE TABLE IF NOT EXISTS scheduler_fences (
+    name TEXT PRIMARY KEY,
+    token BIGINT NOT NULL,
+    holder TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduler_fences;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A window whose end is before its start runs past midnight into the next day, as the schedule
+-- calculator already reads it; only an empty window is rejected.
+ALTER TABLE campaign_business_hours DROP CONSTRAINT IF EXISTS campaign_business_hours_end_minute_check;
+ALTER TABLE campaign_business_hours ADD CONSTRAINT campaign_business_hours_end_minute_check
+    CHECK (end_minute BETWEEN 0 AND 1439 AND end_minute <> start_minute);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Overnight windows stored meanwhile would fail the old check, so it is restored unvalidated.
+ALTER TABLE campaign_business_hours DROP CONSTRAINT IF EXISTS campaign_business_hours_end_minute_check;
+ALTER TABLE campaign_business_hours ADD CONSTRAINT campaign_business_hours_end_minute_check
+    CHECK (end_minute BETWEEN 0 AND 1439 AND end_minute > start_minute) NOT VALID;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deferred targets are claimed again once their time comes, so the zone index covers them too.
+DROP INDEX IF EXISTS idx_campaign_targets_claimable_zone;
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_claimable_zone ON campaign_targets (campaign_id, time_zone)
+    WHERE state IN ('pending', 'staged', 'queued', 'deferred');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_campaign_targets_claimable_zone;
+CREATE INDEX IF NOT EXISTS idx_campaign_targets_claimable_zone ON campaign_targets (campaign_id, time_zone)
+    WHERE state IN ('pending', 'staged', 'queued');
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Calendar links and date exceptions go with their campaign, as its business hours do. Rows left
+-- behind by campaigns deleted before the keys existed are dropped first.
+DELETE FROM campaign_holiday_calendars l WHERE NOT EXISTS (SELECT 1 FROM campaigns c WHERE c.id = l.campaign_id);
+DELETE FROM campaign_date_exceptions e WHERE NOT EXISTS (SELECT 1 FROM campaigns c WHERE c.id = e.campaign_id);
+
+ALTER TABLE campaign_holiday_calendars DROP CONSTRAINT IF EXISTS campaign_holiday_calendars_campaign_id_fkey;
+ALTER TABLE campaign_holiday_calendars ADD CONSTRAINT campaign_holiday_calendars_campaign_id_fkey
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE;
+ALTER TABLE campaign_date_exceptions DROP CONSTRAINT IF EXISTS campaign_date_exceptions_campaign_id_fkey;
+ALTER TABLE campaign_date_exceptions ADD CONSTRAINT campaign_date_exceptions_campaign_id_fkey
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE campaign_date_exceptions DROP CONSTRAINT IF EXISTS campaign_date_exceptions_campaign_id_fkey;
+ALTER TABLE campaign_holiday_calendars DROP CONSTRAINT IF EXISTS campaign_holiday_calendars_campaign_id_fkey;
+-- +goose StatementEnd
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	calendarsvc "github.com/acme/outbound-call-campaign/internal/service/calendar"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+type holidayRequest struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type createCalendarRequest struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Holidays    []holidayRequest `json:"holidays"`
+}
+
+type updateCalendarRequest struct {
+	Name        *string           `json:"name"`
+	Description *string           `json:"description"`
+	Holidays    *[]holidayRequest `json:"holidays"`
+}
+
+type calendarResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Holidays    []holidayRequest `json:"holidays,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type listCalendarsResponse struct {
+	Calendars []calendarResponse `json:"calendars"`
+}
+
+func (h *HandlerSet) createCalendar(ctx *fiber.Ctx) error {
+	var req createCalendarRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid request body")
+	}
+
+	holidays, err := parseHolidays(req.Holidays)
+	if err != nil {
+		return translateError(err)
+	}
+
+	calendar, err := h.calendars.Create(ctx.Context(), calendarsvc.CreateInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Holidays:    holidays,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(toCalendarResponse(calendar))
+}
+
+func (h *HandlerSet) listCalendars(ctx *fiber.Ctx) error {
+	calendars, err := h.calendars.List(ctx.Context())
+	if err != nil {
+		return translateError(err)
+	}
+
+	resp := listCalendarsResponse{Calendars: make([]calendarResponse, 0, len(calendars))}
+	for i := range calendars {
+		resp.Calendars = append(resp.Calendars, toCalendarResponse(&calendars[i]))
+	}
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *HandlerSet) getCalendar(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid calendar id")
+	}
+
+	calendar, err := h.calendars.Get(ctx.Context(), id)
+	if err != nil {
+		return translateError(err)
+	}
+	return ctx.Status(http.StatusOK).JSON(toCalendarResponse(calendar))
+}
+
+func (h *HandlerSet) updateCalendar(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid calendar id")
+	}
+
+	var req updateCalendarRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid request body")
+	}
+
+	input := calendarsvc.UpdateInput{ID: id, Name: req.Name, Description: req.Description}
+	if req.Holidays != nil {
+		holidays, err := parseHolidays(*req.Holidays)
+		if err != nil {
+			return translateError(err)
+		}
+		input.Holidays = &holidays
+	}
+
+	calendar, err := h.calendars.Update(ctx.Context(), input)
+	if err != nil {
+		return translateError(err)
+	}
+	return ctx.Status(http.StatusOK).JSON(toCalendarResponse(calendar))
+}
+
+func (h *HandlerSet) deleteCalendar(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid calendar id")
+	}
+
+	if err := h.calendars.Delete(ctx.Context(), id); err != nil {
+		return translateError(err)
+	}
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// importCalendar merges an iCalendar file into the calendar. It accepts a multipart upload
+// (file field "file") or the raw .ics body.
+func (h *HandlerSet) importCalendar(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid calendar id")
+	}
+
+	var body io.Reader
+	if strings.HasPrefix(ctx.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "multipart upload must include a file field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "unable to read uploaded file")
+		}
+		defer file.Close()
+		body = file
+	} else {
+		body = bytes.NewReader(ctx.Body())
+	}
+
+	result, err := h.calendars.ImportICal(ctx.Context(), id, body)
+	if err != nil {
+		return translateError(err)
+	}
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+func parseHolidays(req []holidayRequest) ([]domain.Holiday, error) {
+	holidays := make([]domain.Holiday, 0, len(req))
+	for _, h := range req {
+		date, err := time.Parse(time.DateOnly, h.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid holiday date %q", apperrors.ErrValidation, h.Date)
+		}
+		holidays = append(holidays, domain.Holiday{Date: date, Name: h.Name})
+	}
+	return holidays, nil
+}
+
+func toCalendarResponse(calendar *domain.HolidayCalendar) calendarResponse {
+	resp := calendarResponse{
+		ID:          calendar.ID,
+		Name:        calendar.Name,
+		Description: calendar.Description,
+		CreatedAt:   calendar.CreatedAt,
+		UpdatedAt:   calendar.UpdatedAt,
+	}
+	for _, h := range calendar.Holidays {
+		resp.Holidays = append(resp.Holidays, holidayRequest{Date: domain.DateKey(h.Date), Name: h.Name})
+	}
+	return resp
+}
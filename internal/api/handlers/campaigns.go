@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,10 +24,19 @@ type createCampaignRequest struct {
 	Name               string                   `json:"name"`
 	Description        string                   `json:"description"`
 	TimeZone           string                   `json:"time_zone"`
+	DefaultCountry     string                   `json:"default_country"`
+	RecipientLocalTime bool                     `json:"recipient_local_time"`
 	MaxConcurrentCalls int                      `json:"max_concurrent_calls"`
+	Priority           int                      `json:"priority"`
+	Weight             int                      `json:"weight"`
 	RetryPolicy        *retryPolicyRequest      `json:"retry_policy"`
 	BusinessHours      []businessHourRequest    `json:"business_hours"`
+	HolidayCalendarIDs []uuid.UUID              `json:"holiday_calendar_ids"`
+	BlackoutDates      []blackoutDateRequest    `json:"blackout_dates"`
+	OverrideWindows    []overrideWindowRequest  `json:"override_windows"`
 	Targets            []targetRequest          `json:"targets"`
+	StartAt            *time.Time               `json:"start_at"`
+	EndAt              *time.Time               `json:"end_at"`
 }
 
 type retryPolicyRequest struct {
@@ -38,6 +52,17 @@ type businessHourRequest struct {
 	End       string `json:"end"`
 }
 
+type blackoutDateRequest struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+type overrideWindowRequest struct {
+	Date  string `json:"date"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
 type targetRequest struct {
 	PhoneNumber string                 `json:"phone_number"`
 	Metadata    map[string]any         `json:"metadata"`
@@ -48,14 +73,25 @@ type campaignResponse struct {
 	Name               string                  `json:"name"`
 	Description        string                  `json:"description"`
 	TimeZone           string                  `json:"time_zone"`
+	DefaultCountry     string                  `json:"default_country,omitempty"`
+	RecipientLocalTime bool                    `json:"recipient_local_time"`
 	Status             domain.CampaignStatus   `json:"status"`
 	MaxConcurrentCalls int                     `json:"max_concurrent_calls"`
+	Priority           int                     `json:"priority"`
+	Weight             int                     `json:"weight"`
 	RetryPolicy        retryPolicyResponse     `json:"retry_policy"`
+	ConfigVersion      int64                   `json:"config_version"`
 	BusinessHours      []businessHourResponse  `json:"business_hours"`
+	HolidayCalendarIDs []uuid.UUID             `json:"holiday_calendar_ids"`
+	BlackoutDates      []blackoutDateResponse   `json:"blackout_dates"`
+	OverrideWindows    []overrideWindowResponse `json:"override_windows"`
+	StartAt            *time.Time              `json:"start_at,omitempty"`
+	EndAt              *time.Time              `json:"end_at,omitempty"`
 	CreatedAt          time.Time               `json:"created_at"`
 	UpdatedAt          time.Time               `json:"updated_at"`
 	StartedAt          *time.Time              `json:"started_at,omitempty"`
 	CompletedAt        *time.Time              `json:"completed_at,omitempty"`
+	CompletionSummary  *domain.CompletionSummary `json:"completion_summary,omitempty"`
 }
 
 type retryPolicyResponse struct {
@@ -71,6 +107,17 @@ type businessHourResponse struct {
 	End       string `json:"end"`
 }
 
+type blackoutDateResponse struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type overrideWindowResponse struct {
+	Date  string `json:"date"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
 type campaignStatsResponse struct {
 	TotalCalls       int64 `json:"total_calls"`
 	CompletedCalls   int64 `json:"completed_calls"`
@@ -112,7 +159,7 @@ func (h *HandlerSet) createCampaign(ctx *fiber.Ctx) error {
 		return translateError(err)
 	}
 
-	campaign, err := h.campaigns.Create(ctx.Context(), input)
+	campaign, err := h.campaigns.Create(actorContext(ctx), input)
 	if err != nil {
 		return translateError(err)
 	}
@@ -168,9 +215,18 @@ func (h *HandlerSet) getCampaign(ctx *fiber.Ctx) error {
 type updateCampaignRequest struct {
 	Name               *string                  `json:"name"`
 	Description        *string                  `json:"description"`
+	DefaultCountry     *string                  `json:"default_country"`
+	RecipientLocalTime *bool                    `json:"recipient_local_time"`
 	MaxConcurrentCalls *int                     `json:"max_concurrent_calls"`
+	Priority           *int                     `json:"priority"`
+	Weight             *int                     `json:"weight"`
 	RetryPolicy        *retryPolicyRequest      `json:"retry_policy"`
 	BusinessHours      *[]businessHourRequest   `json:"business_hours"`
+	HolidayCalendarIDs *[]uuid.UUID             `json:"holiday_calendar_ids"`
+	BlackoutDates      *[]blackoutDateRequest   `json:"blackout_dates"`
+	OverrideWindows    *[]overrideWindowRequest `json:"override_windows"`
+	StartAt            *time.Time               `json:"start_at"`
+	EndAt              *time.Time               `json:"end_at"`
 }
 
 func (h *HandlerSet) updateCampaign(ctx *fiber.Ctx) error {
@@ -191,9 +247,17 @@ func (h *HandlerSet) updateCampaign(ctx *fiber.Ctx) error {
 	if req.Description != nil {
 		input.Description = req.Description
 	}
+	if req.DefaultCountry != nil {
+		input.DefaultCountry = req.DefaultCountry
+	}
+	if req.RecipientLocalTime != nil {
+		input.RecipientLocalTime = req.RecipientLocalTime
+	}
 	if req.MaxConcurrentCalls != nil {
 		input.MaxConcurrentCalls = req.MaxConcurrentCalls
 	}
+	input.Priority = req.Priority
+	input.Weight = req.Weight
 	if req.RetryPolicy != nil {
 		rp, err := parseRetryPolicy(*req.RetryPolicy)
 		if err != nil {
@@ -208,8 +272,25 @@ func (h *HandlerSet) updateCampaign(ctx *fiber.Ctx) error {
 		}
 		input.BusinessHours = &bh
 	}
+	input.HolidayCalendarIDs = req.HolidayCalendarIDs
+	if req.BlackoutDates != nil {
+		blackouts, err := parseBlackoutDates(*req.BlackoutDates)
+		if err != nil {
+			return translateError(err)
+		}
+		input.Blackouts = &blackouts
+	}
+	if req.OverrideWindows != nil {
+		overrides, err := parseOverrideWindows(*req.OverrideWindows)
+		if err != nil {
+			return translateError(err)
+		}
+		input.OverrideWindows = &overrides
+	}
+	input.StartAt = req.StartAt
+	input.EndAt = req.EndAt
 
-	campaign, err := h.campaigns.Update(ctx.Context(), input)
+	campaign, err := h.campaigns.Update(actorContext(ctx), input)
 	if err != nil {
 		return translateError(err)
 	}
@@ -222,7 +303,7 @@ func (h *HandlerSet) startCampaign(ctx *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
 	}
-	if err := h.campaigns.Start(ctx.Context(), id); err != nil {
+	if err := h.campaigns.Start(actorContext(ctx), id); err != nil {
 		return translateError(err)
 	}
 	return ctx.SendStatus(http.StatusNoContent)
@@ -233,7 +314,29 @@ func (h *HandlerSet) pauseCampaign(ctx *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
 	}
-	if err := h.campaigns.Pause(ctx.Context(), id); err != nil {
+	if err := h.campaigns.Pause(actorContext(ctx), id); err != nil {
+		return translateError(err)
+	}
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (h *HandlerSet) resumeCampaign(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+	if err := h.campaigns.Resume(actorContext(ctx), id); err != nil {
+		return translateError(err)
+	}
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (h *HandlerSet) cancelCampaign(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+	if err := h.campaigns.Cancel(actorContext(ctx), id); err != nil {
 		return translateError(err)
 	}
 	return ctx.SendStatus(http.StatusNoContent)
@@ -244,7 +347,7 @@ func (h *HandlerSet) completeCampaign(ctx *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
 	}
-	if err := h.campaigns.Complete(ctx.Context(), id); err != nil {
+	if err := h.campaigns.Complete(actorContext(ctx), id); err != nil {
 		return translateError(err)
 	}
 	return ctx.SendStatus(http.StatusNoContent)
@@ -280,22 +383,92 @@ func (h *HandlerSet) addTargets(ctx *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Targets []targetRequest `json:"targets"`
+		Targets     []targetRequest `json:"targets"`
+		OnDuplicate string          `json:"on_duplicate"`
 	}
 	if err := ctx.BodyParser(&req); err != nil {
 		return fiber.NewError(http.StatusBadRequest, "invalid request body")
 	}
 
+	policy, err := domain.ParseDuplicatePolicy(req.OnDuplicate)
+	if err != nil {
+		return translateError(err)
+	}
+
 	targets := make([]campaignsvc.TargetInput, 0, len(req.Targets))
 	for _, t := range req.Targets {
 		targets = append(targets, campaignsvc.TargetInput{PhoneNumber: t.PhoneNumber, Payload: t.Metadata})
 	}
 
-	if err := h.campaigns.AddTargets(ctx.Context(), id, targets); err != nil {
+	result, err := h.campaigns.AddTargets(actorContext(ctx), id, targets, policy)
+	if err != nil {
 		return translateError(err)
 	}
 
-	return ctx.SendStatus(http.StatusAccepted)
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+// importTargets accepts a multipart upload (file field "file") or a raw CSV / NDJSON body.
+// The format comes from the "format" form field or query parameter, then the file extension
+// or Content-Type, defaulting to CSV.
+// Duplicate handling comes from the "on_duplicate" form field or query parameter.
+func (h *HandlerSet) importTargets(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+
+	var (
+		body        io.Reader
+		format      = ctx.Query("format")
+		onDuplicate = ctx.Query("on_duplicate")
+	)
+	if strings.HasPrefix(ctx.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "multipart upload must include a file field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "unable to read uploaded file")
+		}
+		defer file.Close()
+		body = file
+		if v := ctx.FormValue("format"); v != "" {
+			format = v
+		}
+		if v := ctx.FormValue("on_duplicate"); v != "" {
+			onDuplicate = v
+		}
+		if format == "" {
+			format = filepath.Ext(header.Filename)
+		}
+	} else {
+		body = ctx.Context().RequestBodyStream()
+		if body == nil {
+			body = bytes.NewReader(ctx.Body())
+		}
+		if format == "" {
+			format, _, _ = strings.Cut(ctx.Get(fiber.HeaderContentType), ";")
+		}
+	}
+
+	importFormat, err := campaignsvc.ParseImportFormat(format)
+	if err != nil {
+		return translateError(err)
+	}
+
+	policy, err := domain.ParseDuplicatePolicy(onDuplicate)
+	if err != nil {
+		return translateError(err)
+	}
+
+	report, err := h.campaigns.ImportTargets(actorContext(ctx), id, importFormat, policy, body)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(report)
 }
 
 func (h *HandlerSet) listCampaignCalls(ctx *fiber.Ctx) error {
@@ -335,25 +508,93 @@ func (h *HandlerSet) listCampaignCalls(ctx *fiber.Ctx) error {
 	return ctx.Status(http.StatusOK).JSON(resp)
 }
 
+type campaignEventResponse struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type listCampaignEventsResponse struct {
+	Events       []campaignEventResponse `json:"events"`
+	NextBeforeID *int64                  `json:"next_before_id,omitempty"`
+}
+
+func (h *HandlerSet) listCampaignEvents(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	var beforeID *int64
+	if beforeStr := ctx.Query("before_id"); beforeStr != "" {
+		v, err := strconv.ParseInt(beforeStr, 10, 64)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "invalid before_id")
+		}
+		beforeID = &v
+	}
+
+	events, err := h.campaigns.ListEvents(ctx.Context(), id, beforeID, limit)
+	if err != nil {
+		return translateError(err)
+	}
+
+	resp := listCampaignEventsResponse{Events: make([]campaignEventResponse, 0, len(events))}
+	for _, e := range events {
+		resp.Events = append(resp.Events, campaignEventResponse{
+			ID:        e.ID,
+			Type:      string(e.Type),
+			Actor:     e.Actor,
+			Before:    e.Before,
+			After:     e.After,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	if len(events) == limit {
+		last := events[len(events)-1].ID
+		resp.NextBeforeID = &last
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
 func toCampaignResponse(campaign *domain.Campaign) campaignResponse {
 	resp := campaignResponse{
 		ID:                 campaign.ID,
 		Name:               campaign.Name,
 		Description:        campaign.Description,
 		TimeZone:           campaign.TimeZone,
+		DefaultCountry:     campaign.DefaultCountry,
+		RecipientLocalTime: campaign.RecipientLocalTime,
 		Status:             campaign.Status,
 		MaxConcurrentCalls: campaign.MaxConcurrentCalls,
+		Priority:           campaign.Priority,
+		Weight:             campaign.Weight,
 		RetryPolicy: retryPolicyResponse{
 			MaxAttempts: campaign.RetryPolicy.MaxAttempts,
 			BaseDelay:   campaign.RetryPolicy.BaseDelay.String(),
 			MaxDelay:    campaign.RetryPolicy.MaxDelay.String(),
 			Jitter:      campaign.RetryPolicy.Jitter,
 		},
-		BusinessHours: make([]businessHourResponse, 0, len(campaign.BusinessHours)),
-		CreatedAt:     campaign.CreatedAt,
-		UpdatedAt:     campaign.UpdatedAt,
-		StartedAt:     campaign.StartedAt,
-		CompletedAt:   campaign.CompletedAt,
+		ConfigVersion:      campaign.ConfigVersion,
+		BusinessHours:      make([]businessHourResponse, 0, len(campaign.BusinessHours)),
+		HolidayCalendarIDs: append([]uuid.UUID{}, campaign.HolidayCalendarIDs...),
+		BlackoutDates:      make([]blackoutDateResponse, 0, len(campaign.Blackouts)),
+		OverrideWindows:    make([]overrideWindowResponse, 0, len(campaign.OverrideWindows)),
+		StartAt:            campaign.StartAt,
+		EndAt:              campaign.EndAt,
+		CreatedAt:          campaign.CreatedAt,
+		UpdatedAt:          campaign.UpdatedAt,
+		StartedAt:          campaign.StartedAt,
+		CompletedAt:        campaign.CompletedAt,
+		CompletionSummary:  campaign.CompletionSummary,
 	}
 
 	for _, window := range campaign.BusinessHours {
@@ -363,6 +604,16 @@ func toCampaignResponse(campaign *domain.Campaign) campaignResponse {
 			End:       window.End.Format("15:04"),
 		})
 	}
+	for _, b := range campaign.Blackouts {
+		resp.BlackoutDates = append(resp.BlackoutDates, blackoutDateResponse{Date: domain.DateKey(b.Date), Reason: b.Reason})
+	}
+	for _, o := range campaign.OverrideWindows {
+		resp.OverrideWindows = append(resp.OverrideWindows, overrideWindowResponse{
+			Date:  domain.DateKey(o.Date),
+			Start: o.Start.Format("15:04"),
+			End:   o.End.Format("15:04"),
+		})
+	}
 
 	return resp
 }
@@ -372,7 +623,13 @@ func (h *HandlerSet) toCreateCampaignInput(req createCampaignRequest) (campaigns
 		Name:               req.Name,
 		Description:        req.Description,
 		TimeZone:           req.TimeZone,
+		DefaultCountry:     req.DefaultCountry,
+		RecipientLocalTime: req.RecipientLocalTime,
 		MaxConcurrentCalls: req.MaxConcurrentCalls,
+		Priority:           req.Priority,
+		Weight:             req.Weight,
+		StartAt:            req.StartAt,
+		EndAt:              req.EndAt,
 	}
 
 	if req.RetryPolicy != nil {
@@ -391,6 +648,18 @@ func (h *HandlerSet) toCreateCampaignInput(req createCampaignRequest) (campaigns
 		input.BusinessHours = windows
 	}
 
+	input.HolidayCalendarIDs = req.HolidayCalendarIDs
+	blackouts, err := parseBlackoutDates(req.BlackoutDates)
+	if err != nil {
+		return campaignsvc.CreateCampaignInput{}, err
+	}
+	input.Blackouts = blackouts
+	overrides, err := parseOverrideWindows(req.OverrideWindows)
+	if err != nil {
+		return campaignsvc.CreateCampaignInput{}, err
+	}
+	input.OverrideWindows = overrides
+
 	targets := make([]campaignsvc.TargetInput, 0, len(req.Targets))
 	for _, t := range req.Targets {
 		targets = append(targets, campaignsvc.TargetInput{PhoneNumber: t.PhoneNumber, Payload: t.Metadata})
@@ -430,6 +699,10 @@ func parseBusinessHours(req []businessHourRequest) ([]campaignsvc.BusinessHourIn
 		if err != nil {
 			return nil, fmt.Errorf("%w: invalid end time", apperrors.ErrValidation)
 		}
+		// An end before the start runs past midnight; an equal one leaves no window at all.
+		if end.Equal(start) {
+			return nil, fmt.Errorf("%w: business hours end must differ from start", apperrors.ErrValidation)
+		}
 		windows = append(windows, campaignsvc.BusinessHourInput{
 			DayOfWeek: time.Weekday(bh.DayOfWeek),
 			Start:     start,
@@ -439,6 +712,38 @@ func parseBusinessHours(req []businessHourRequest) ([]campaignsvc.BusinessHourIn
 	return windows, nil
 }
 
+func parseBlackoutDates(req []blackoutDateRequest) ([]domain.BlackoutDate, error) {
+	blackouts := make([]domain.BlackoutDate, 0, len(req))
+	for _, b := range req {
+		date, err := time.Parse(time.DateOnly, b.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid blackout date %q", apperrors.ErrValidation, b.Date)
+		}
+		blackouts = append(blackouts, domain.BlackoutDate{Date: date, Reason: b.Reason})
+	}
+	return blackouts, nil
+}
+
+func parseOverrideWindows(req []overrideWindowRequest) ([]domain.OverrideWindow, error) {
+	windows := make([]domain.OverrideWindow, 0, len(req))
+	for _, o := range req {
+		date, err := time.Parse(time.DateOnly, o.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid override date %q", apperrors.ErrValidation, o.Date)
+		}
+		start, err := time.Parse("15:04", o.Start)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid start time", apperrors.ErrValidation)
+		}
+		end, err := time.Parse("15:04", o.End)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end time", apperrors.ErrValidation)
+		}
+		windows = append(windows, domain.OverrideWindow{Date: date, Start: start, End: end})
+	}
+	return windows, nil
+}
+
 func parseUUID(value string) (uuid.UUID, error) {
 	return uuid.Parse(value)
 }
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	dncsvc "github.com/acme/outbound-call-campaign/internal/service/dnc"
+)
+
+type suppressionRequest struct {
+	PhoneNumber string     `json:"phone_number"`
+	CampaignID  *uuid.UUID `json:"campaign_id,omitempty"`
+	Reason      string     `json:"reason"`
+}
+
+type importSuppressionsRequest struct {
+	CampaignID *uuid.UUID           `json:"campaign_id,omitempty"`
+	Reason     string               `json:"reason"`
+	Entries    []suppressionRequest `json:"entries"`
+}
+
+type suppressionResponse struct {
+	ID          int64      `json:"id"`
+	PhoneNumber string     `json:"phone_number"`
+	CampaignID  *uuid.UUID `json:"campaign_id,omitempty"`
+	Reason      string     `json:"reason"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type listSuppressionsResponse struct {
+	Entries     []suppressionResponse `json:"entries"`
+	NextAfterID *int64                `json:"next_after_id,omitempty"`
+}
+
+type suppressionCheckResponse struct {
+	PhoneNumber string `json:"phone_number"`
+	Suppressed  bool   `json:"suppressed"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+func (h *HandlerSet) addSuppression(ctx *fiber.Ctx) error {
+	var req suppressionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid request body")
+	}
+
+	entry, err := h.dnc.Add(actorContext(ctx), dncsvc.EntryInput{
+		CampaignID:  req.CampaignID,
+		PhoneNumber: req.PhoneNumber,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(toSuppressionResponse(*entry))
+}
+
+// importSuppressions adds many numbers at once. Entry-level campaign_id and reason fall back to the
+// request-level values.
+func (h *HandlerSet) importSuppressions(ctx *fiber.Ctx) error {
+	var req importSuppressionsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid request body")
+	}
+
+	inputs := make([]dncsvc.EntryInput, 0, len(req.Entries))
+	for _, e := range req.Entries {
+		in := dncsvc.EntryInput{CampaignID: e.CampaignID, PhoneNumber: e.PhoneNumber, Reason: e.Reason}
+		if in.CampaignID == nil {
+			in.CampaignID = req.CampaignID
+		}
+		if in.Reason == "" {
+			in.Reason = req.Reason
+		}
+		inputs = append(inputs, in)
+	}
+
+	result, err := h.dnc.Import(actorContext(ctx), inputs)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+func (h *HandlerSet) listSuppressions(ctx *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(ctx.Query("limit", "100"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	filter := repository.SuppressionFilter{
+		GlobalOnly:  ctx.Query("scope") == "global",
+		PhoneNumber: ctx.Query("phone_number"),
+	}
+	if campaignStr := ctx.Query("campaign_id"); campaignStr != "" {
+		id, err := parseUUID(campaignStr)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "invalid campaign_id")
+		}
+		filter.CampaignID = &id
+	}
+
+	var afterID *int64
+	if afterStr := ctx.Query("after_id"); afterStr != "" {
+		v, err := strconv.ParseInt(afterStr, 10, 64)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, "invalid after_id")
+		}
+		afterID = &v
+	}
+
+	entries, err := h.dnc.List(ctx.Context(), filter, afterID, limit)
+	if err != nil {
+		return translateError(err)
+	}
+
+	resp := listSuppressionsResponse{Entries: make([]suppressionResponse, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, toSuppressionResponse(e))
+	}
+	if len(entries) == limit {
+		last := entries[len(entries)-1].ID
+		resp.NextAfterID = &last
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *HandlerSet) checkSuppression(ctx *fiber.Ctx) error {
+	phone := ctx.Query("phone_number")
+	if phone == "" {
+		return fiber.NewError(http.StatusBadRequest, "phone_number is required")
+	}
+	campaignID, err := parseUUID(ctx.Query("campaign_id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign_id")
+	}
+
+	reason, suppressed, err := h.dnc.Check(ctx.Context(), campaignID, phone)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(suppressionCheckResponse{
+		PhoneNumber: phone,
+		Suppressed:  suppressed,
+		Reason:      reason,
+	})
+}
+
+func (h *HandlerSet) deleteSuppression(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid suppression id")
+	}
+
+	if err := h.dnc.Delete(ctx.Context(), id); err != nil {
+		return translateError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func toSuppressionResponse(entry domain.SuppressedNumber) suppressionResponse {
+	return suppressionResponse{
+		ID:          entry.ID,
+		PhoneNumber: entry.PhoneNumber,
+		CampaignID:  entry.CampaignID,
+		Reason:      entry.Reason,
+		CreatedBy:   entry.CreatedBy,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
@@ -2,21 +2,34 @@ package handlers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
 	"github.com/acme/outbound-call-campaign/internal/app"
+	calendarsvc "github.com/acme/outbound-call-campaign/internal/service/calendar"
 	campaignsvc "github.com/acme/outbound-call-campaign/internal/service/campaign"
 	callsvc "github.com/acme/outbound-call-campaign/internal/service/call"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+	dncsvc "github.com/acme/outbound-call-campaign/internal/service/dnc"
 )
 
+// actorHeader names the caller responsible for a change in the campaign audit trail. The API has
+// no authentication, so the value is only a claim and is recorded as such.
+const actorHeader = "X-Actor"
+
+// maxActorLength bounds the claimed actor stored on audit events.
+const maxActorLength = 128
+
 // HandlerSet bundles all HTTP handlers.
 type HandlerSet struct {
 	container *app.Container
 	campaigns *campaignsvc.Service
 	calls     *callsvc.Service
+	dnc       *dncsvc.Service
+	calendars *calendarsvc.Service
 }
 
 // NewHandlerSet creates a new handler bundle.
@@ -26,6 +39,8 @@ func NewHandlerSet(container *app.Container) *HandlerSet {
 		container: container,
 		campaigns: services.Campaign,
 		calls:     services.Call,
+		dnc:       services.DNC,
+		calendars: services.Calendar,
 	}
 }
 
@@ -43,14 +58,37 @@ func (h *HandlerSet) Register(app *fiber.App) {
 	campaigns.Put("/:id", h.updateCampaign)
 	campaigns.Post("/:id/start", h.startCampaign)
 	campaigns.Post("/:id/pause", h.pauseCampaign)
+	campaigns.Post("/:id/resume", h.resumeCampaign)
 	campaigns.Post("/:id/complete", h.completeCampaign)
+	campaigns.Post("/:id/cancel", h.cancelCampaign)
 	campaigns.Get("/:id/stats", h.campaignStats)
+	campaigns.Get("/:id/targets", h.listTargets)
 	campaigns.Post("/:id/targets", h.addTargets)
+	campaigns.Post("/:id/targets/import", h.importTargets)
+	campaigns.Patch("/:id/targets/:targetId", h.updateTarget)
+	campaigns.Delete("/:id/targets/:targetId", h.deleteTarget)
 	campaigns.Get("/:id/calls", h.listCampaignCalls)
+	campaigns.Get("/:id/events", h.listCampaignEvents)
+	campaigns.Get("/:id/schedule", h.getCampaignSchedule)
 
 	calls := v1.Group("/calls")
 	calls.Post("/", h.triggerCall)
 	calls.Get("/:id", h.getCall)
+
+	dnc := v1.Group("/dnc")
+	dnc.Post("/", h.addSuppression)
+	dnc.Get("/", h.listSuppressions)
+	dnc.Post("/import", h.importSuppressions)
+	dnc.Get("/check", h.checkSuppression)
+	dnc.Delete("/:id", h.deleteSuppression)
+
+	calendars := v1.Group("/calendars")
+	calendars.Post("/", h.createCalendar)
+	calendars.Get("/", h.listCalendars)
+	calendars.Get("/:id", h.getCalendar)
+	calendars.Patch("/:id", h.updateCalendar)
+	calendars.Delete("/:id", h.deleteCalendar)
+	calendars.Post("/:id/import", h.importCalendar)
 }
 
 // ErrorHandler provides centralized error responses.
@@ -73,6 +111,18 @@ func (h *HandlerSet) ErrorHandler(ctx *fiber.Ctx, err error) error {
 	})
 }
 
+// actorContext returns the request context tagged with the caller named in the X-Actor header.
+func actorContext(ctx *fiber.Ctx) context.Context {
+	claimed := strings.TrimSpace(ctx.Get(actorHeader))
+	if claimed == "" {
+		return common.WithActor(ctx.Context(), common.ActorAPI)
+	}
+	if len(claimed) > maxActorLength {
+		claimed = claimed[:maxActorLength]
+	}
+	return common.WithActor(ctx.Context(), common.UnauthenticatedActor(claimed))
+}
+
 func (h *HandlerSet) health(ctx *fiber.Ctx) error {
 	healthCtx, cancel := context.WithTimeout(ctx.Context(), 2*time.Second)
 	defer cancel()
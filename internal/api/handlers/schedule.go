@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+// defaultScheduleRange is previewed when the request gives no end.
+const defaultScheduleRange = 7 * 24 * time.Hour
+
+type intervalResponse struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type scheduleResponse struct {
+	CampaignID uuid.UUID             `json:"campaign_id"`
+	TimeZone   string                `json:"time_zone"`
+	Status     domain.CampaignStatus `json:"status"`
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Intervals  []intervalResponse    `json:"intervals"`
+	NextOpen   *time.Time            `json:"next_open,omitempty"`
+}
+
+// getCampaignSchedule previews dialling intervals; ?from= and ?to= are RFC 3339 and default to
+// now and a week later.
+func (h *HandlerSet) getCampaignSchedule(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+
+	from := time.Now().UTC()
+	if v := ctx.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			return fiber.NewError(http.StatusBadRequest, "invalid from: expected RFC 3339 time")
+		}
+	}
+	to := from.Add(defaultScheduleRange)
+	if v := ctx.Query("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			return fiber.NewError(http.StatusBadRequest, "invalid to: expected RFC 3339 time")
+		}
+	}
+
+	schedule, err := h.campaigns.Schedule(ctx.Context(), id, from, to)
+	if err != nil {
+		return translateError(err)
+	}
+
+	resp := scheduleResponse{
+		CampaignID: schedule.Campaign.ID,
+		TimeZone:   schedule.Campaign.TimeZone,
+		Status:     schedule.Campaign.Status,
+		From:       schedule.From,
+		To:         schedule.To,
+		Intervals:  make([]intervalResponse, 0, len(schedule.Intervals)),
+		NextOpen:   schedule.NextOpen,
+	}
+	for _, interval := range schedule.Intervals {
+		resp.Intervals = append(resp.Intervals, intervalResponse{Start: interval.Start, End: interval.End})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
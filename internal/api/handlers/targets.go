@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	campaignsvc "github.com/acme/outbound-call-campaign/internal/service/campaign"
+)
+
+type targetResponse struct {
+	ID            uuid.UUID      `json:"id"`
+	PhoneNumber   string         `json:"phone_number"`
+	State         string         `json:"state"`
+	StateReason   string         `json:"state_reason,omitempty"`
+	TimeZone      string         `json:"time_zone,omitempty"`
+	CallID        *uuid.UUID     `json:"call_id,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	AttemptCount  int            `json:"attempt_count"`
+	ScheduledAt   *time.Time     `json:"scheduled_at,omitempty"`
+	LastAttemptAt *time.Time     `json:"last_attempt_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type listTargetsResponse struct {
+	Targets  []targetResponse `json:"targets"`
+	NextPage string           `json:"next_page,omitempty"`
+}
+
+type updateTargetRequest struct {
+	Metadata map[string]any `json:"metadata"`
+}
+
+// listTargets supports ?state=, ?phone_number= (substring match), ?limit= and ?page_token=.
+func (h *HandlerSet) listTargets(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit", "100"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	page, err := h.campaigns.ListTargets(ctx.Context(), id, campaignsvc.TargetQuery{
+		State:       ctx.Query("state"),
+		PhoneNumber: ctx.Query("phone_number"),
+		PageToken:   ctx.Query("page_token"),
+		Limit:       limit,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	resp := listTargetsResponse{Targets: make([]targetResponse, 0, len(page.Targets)), NextPage: page.NextPageToken}
+	for _, t := range page.Targets {
+		resp.Targets = append(resp.Targets, toTargetResponse(t))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *HandlerSet) updateTarget(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+	targetID, err := parseUUID(ctx.Params("targetId"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid target id")
+	}
+
+	var req updateTargetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid request body")
+	}
+
+	target, err := h.campaigns.UpdateTargetPayload(actorContext(ctx), id, targetID, req.Metadata)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(toTargetResponse(*target))
+}
+
+func (h *HandlerSet) deleteTarget(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid campaign id")
+	}
+	targetID, err := parseUUID(ctx.Params("targetId"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, "invalid target id")
+	}
+
+	if err := h.campaigns.DeleteTarget(actorContext(ctx), id, targetID); err != nil {
+		return translateError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func toTargetResponse(t repository.CampaignTargetRecord) targetResponse {
+	return targetResponse{
+		ID:            t.ID,
+		PhoneNumber:   t.PhoneNumber,
+		State:         t.State,
+		StateReason:   t.StateReason,
+		TimeZone:      t.TimeZone,
+		CallID:        t.CallID,
+		Metadata:      t.Payload,
+		AttemptCount:  t.AttemptCount,
+		ScheduledAt:   t.ScheduledAt,
+		LastAttemptAt: t.LastAttempt,
+		CreatedAt:     t.CreatedAt,
+	}
+}
@@ -25,7 +25,10 @@ func NewServer(deps *app.Container, handlers *handlers.HandlerSet) *Server {
 		ReadTimeout:  deps.Config.HTTP.ReadTimeout,
 		WriteTimeout: deps.Config.HTTP.WriteTimeout,
 		IdleTimeout:  deps.Config.HTTP.IdleTimeout,
+		BodyLimit:    deps.Config.HTTP.BodyLimit,
 		ErrorHandler: handlers.ErrorHandler,
+		// Target uploads are read incrementally instead of being buffered whole.
+		StreamRequestBody: true,
 	}
 
 	app := fiber.New(cfg)
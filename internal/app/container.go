@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/acme/outbound-call-campaign/internal/compliance"
 	"github.com/acme/outbound-call-campaign/internal/config"
 	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/infra/db"
 	"github.com/acme/outbound-call-campaign/internal/infra/redis"
 	"github.com/acme/outbound-call-campaign/internal/queue"
 	"github.com/acme/outbound-call-campaign/internal/repository"
+	cacherepo "github.com/acme/outbound-call-campaign/internal/repository/cache"
 	pgrepo "github.com/acme/outbound-call-campaign/internal/repository/postgres"
 	scyllarepo "github.com/acme/outbound-call-campaign/internal/repository/scylla"
+	calendarsvc "github.com/acme/outbound-call-campaign/internal/service/calendar"
 	campaignsvc "github.com/acme/outbound-call-campaign/internal/service/campaign"
 	callsvc "github.com/acme/outbound-call-campaign/internal/service/call"
 	"github.com/acme/outbound-call-campaign/internal/service/concurrency"
+	dncsvc "github.com/acme/outbound-call-campaign/internal/service/dnc"
 	telephonySvc "github.com/acme/outbound-call-campaign/internal/telephony"
 	telephonyMock "github.com/acme/outbound-call-campaign/internal/telephony/mock"
 	"github.com/acme/outbound-call-campaign/pkg/logger"
@@ -31,6 +35,9 @@ type Container struct {
 	Redis    *redis.Client
 	Kafka    *queue.Kafka
 
+	// Compliance enforces regulatory quiet hours ahead of every call.
+	Compliance *compliance.Guard
+
 	// lazily initialised components
 	components struct {
 		once         sync.Once
@@ -45,14 +52,21 @@ type Container struct {
 type repositories struct {
 	Campaign      repository.CampaignRepository
 	BusinessHours repository.BusinessHourRepository
+	Calendars     repository.HolidayCalendarRepository
 	Targets       repository.CampaignTargetRepository
 	Stats         repository.CampaignStatisticsRepository
+	Events        repository.CampaignEventRepository
+	Suppression   repository.SuppressionRepository
 	CallStore     repository.CallStore
+	Outbox        repository.OutboxRepository
+	Fences        repository.FenceRepository
 }
 
 type services struct {
 	Campaign *campaignsvc.Service
 	Call     *callsvc.Service
+	DNC      *dncsvc.Service
+	Calendar *calendarsvc.Service
 }
 
 type dispatchers struct {
@@ -66,7 +80,8 @@ type providers struct {
 }
 
 type limiters struct {
-	Concurrency *concurrency.Limiter
+	Concurrency  *concurrency.Limiter
+	RetryBacklog *concurrency.RetryBacklog
 }
 
 // Build constructs a container for the given configuration path.
@@ -101,6 +116,11 @@ func Build(ctx context.Context, configPath string) (*Container, error) {
 		return nil, fmt.Errorf("bootstrap kafka: %w", err)
 	}
 
+	guard, err := compliance.NewGuard(cfg.Compliance.QuietHours)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap compliance: %w", err)
+	}
+
 	container := &Container{
 		Config:   cfg,
 		Logger:   lg,
@@ -108,6 +128,8 @@ func Build(ctx context.Context, configPath string) (*Container, error) {
 		Scylla:   scylla,
 		Redis:    redisClient,
 		Kafka:    kafka,
+
+		Compliance: guard,
 	}
 
 	return container, nil
@@ -115,12 +137,26 @@ func Build(ctx context.Context, configPath string) (*Container, error) {
 
 func (c *Container) initComponents() {
 	c.components.once.Do(func() {
+		var targets repository.CampaignTargetRepository = pgrepo.NewCampaignTargetRepository(c.Postgres.DB())
+		if ttl := c.Config.Redis.TargetCacheTTL; ttl > 0 {
+			targets = cacherepo.NewCampaignTargetRepository(targets, c.Redis.Inner(), ttl, c.Logger)
+		}
+		var campaigns repository.CampaignRepository = pgrepo.NewCampaignRepository(c.Postgres.DB())
+		if ttl := c.Config.Redis.CampaignSettingsTTL; ttl > 0 {
+			campaigns = cacherepo.NewCampaignRepository(campaigns, c.Redis.Inner(), ttl, c.Logger)
+		}
+
 		repos := &repositories{
-			Campaign:      pgrepo.NewCampaignRepository(c.Postgres.DB()),
+			Campaign:      campaigns,
 			BusinessHours: pgrepo.NewBusinessHourRepository(c.Postgres.DB()),
-			Targets:       pgrepo.NewCampaignTargetRepository(c.Postgres.DB()),
+			Calendars:     pgrepo.NewHolidayCalendarRepository(c.Postgres.DB()),
+			Targets:       targets,
 			Stats:         pgrepo.NewCampaignStatisticsRepository(c.Postgres.DB()),
+			Events:        pgrepo.NewCampaignEventRepository(c.Postgres.DB()),
+			Suppression:   pgrepo.NewSuppressionRepository(c.Postgres.DB()),
 			CallStore:     scyllarepo.NewCallStore(c.Scylla.Session()),
+			Outbox:        pgrepo.NewOutboxRepository(c.Postgres.DB()),
+			Fences:        pgrepo.NewFenceRepository(c.Postgres.DB()),
 		}
 
 		disp := &dispatchers{
@@ -133,10 +169,15 @@ func (c *Container) initComponents() {
 			Campaign: campaignsvc.NewService(
 				repos.Campaign,
 				repos.BusinessHours,
+				repos.Calendars,
 				repos.Targets,
 				repos.Stats,
+				repos.Events,
+				c.Logger,
 				c.Config.Throttle.DefaultPerCampaign,
 			),
+			DNC:      dncsvc.NewService(repos.Suppression, repos.Campaign),
+			Calendar: calendarsvc.NewService(repos.Calendars),
 		}
 
 		defaultRetry := domain.RetryPolicy{
@@ -150,8 +191,10 @@ func (c *Container) initComponents() {
 			repos.CallStore,
 			repos.Campaign,
 			repos.Targets,
-			repos.Stats,
-			disp.CallDispatcher,
+			repos.Suppression,
+			repos.Outbox,
+			c.Config.Kafka.CallTopic,
+			c.Logger,
 			defaultRetry,
 			c.Config.Throttle.DefaultPerCampaign,
 		)
@@ -161,7 +204,8 @@ func (c *Container) initComponents() {
 		}
 
 		limiters := &limiters{
-			Concurrency: concurrency.NewLimiter(c.Redis.Inner(), c.Config.Throttle.DefaultPerCampaign, c.Config.Scheduler.LockTTL),
+			Concurrency:  concurrency.NewLimiter(c.Redis.Inner(), c.Config.Throttle.DefaultPerCampaign, c.Config.Scheduler.LockTTL),
+			RetryBacklog: concurrency.NewRetryBacklog(c.Redis.Inner(), c.Config.Retry.BacklogGrace),
 		}
 
 		c.components.repositories = repos
@@ -0,0 +1,160 @@
+// Package compliance enforces regulatory calling rules that campaign settings cannot relax.
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/acme/outbound-call-campaign/internal/config"
+	"github.com/acme/outbound-call-campaign/pkg/phone"
+)
+
+// Reasons recorded on targets and calls held back by the quiet-hours guard.
+const (
+	ReasonQuietHours = "quiet_hours"
+	// ReasonUnverified is recorded when the recipient's local time could not be determined.
+	ReasonUnverified = "quiet_hours_unverified"
+)
+
+// UnverifiedDelay is how long a number whose quiet hours cannot be checked is held back before
+// it is checked again. Such numbers are never dialed.
+const UnverifiedDelay = time.Hour
+
+// Default calling window used when the configuration leaves it unset.
+const (
+	defaultStart = "08:00"
+	defaultEnd   = "21:00"
+)
+
+// window is a same-day span of local wall-clock minutes [start, end).
+type window struct {
+	start int
+	end   int
+}
+
+func (w window) intersect(other window) window {
+	return window{start: max(w.start, other.start), end: min(w.end, other.end)}
+}
+
+type region struct {
+	name string
+	window
+}
+
+// Guard decides whether a number may be called at a given time under the configured quiet hours.
+type Guard struct {
+	base    window
+	regions map[string][]region
+	longest int
+}
+
+// Decision is the outcome of a quiet-hours check.
+type Decision struct {
+	Allowed bool
+	// NextAllowed is the earliest time the call may be placed; it equals the checked time when allowed.
+	NextAllowed time.Time
+	// Rule names the most specific rule applied: "default" or a region name.
+	Rule string
+	// TimeZone is the recipient zone the rule was evaluated in.
+	TimeZone string
+}
+
+// NewGuard builds a guard from configuration. Region windows that do not overlap the base
+// window are rejected, as they would forbid calling those numbers altogether.
+func NewGuard(cfg config.QuietHoursConfig) (*Guard, error) {
+	start, end := cfg.Start, cfg.End
+	if start == "" && end == "" {
+		start, end = defaultStart, defaultEnd
+	}
+	base, err := parseWindow(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: quiet hours: %w", err)
+	}
+
+	g := &Guard{base: base, regions: make(map[string][]region)}
+	for _, r := range cfg.Regions {
+		w, err := parseWindow(r.Start, r.End)
+		if err != nil {
+			return nil, fmt.Errorf("compliance: quiet hours region %q: %w", r.Name, err)
+		}
+		if narrowed := base.intersect(w); narrowed.start >= narrowed.end {
+			return nil, fmt.Errorf("compliance: quiet hours region %q does not overlap %s-%s", r.Name, start, end)
+		}
+		if len(r.Prefixes) == 0 {
+			return nil, fmt.Errorf("compliance: quiet hours region %q has no prefixes", r.Name)
+		}
+		for _, prefix := range r.Prefixes {
+			prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "+")
+			if prefix == "" || strings.Trim(prefix, "0123456789") != "" {
+				return nil, fmt.Errorf("compliance: quiet hours region %q: invalid prefix %q", r.Name, prefix)
+			}
+			g.regions[prefix] = append(g.regions[prefix], region{name: r.Name, window: w})
+			g.longest = max(g.longest, len(prefix))
+		}
+	}
+	return g, nil
+}
+
+// Check reports whether number may be called at t in the recipient's zone. When zone is empty
+// it is derived from the number; a number whose zone cannot be determined is an error.
+// Every region matching a prefix of the number applies, so overlapping regions combine.
+func (g *Guard) Check(number, zone string, t time.Time) (Decision, error) {
+	if zone == "" {
+		zone, _ = phone.TimeZone(number)
+	}
+	if zone == "" {
+		return Decision{}, fmt.Errorf("compliance: no time zone known for %s", number)
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return Decision{}, fmt.Errorf("compliance: load time zone %s: %w", zone, err)
+	}
+
+	w, rule := g.base, "default"
+	digits := strings.TrimPrefix(number, "+")
+	for n := 1; n <= min(len(digits), g.longest); n++ {
+		for _, r := range g.regions[digits[:n]] {
+			w = w.intersect(r.window)
+			rule = r.name
+		}
+	}
+
+	if w.start >= w.end {
+		return Decision{}, fmt.Errorf("compliance: quiet hours leave no calling window for %s", number)
+	}
+	next := w.next(t, loc)
+	return Decision{Allowed: next.Equal(t), NextAllowed: next, Rule: rule, TimeZone: zone}, nil
+}
+
+// next returns the earliest time at or after t that falls inside the window on a local date.
+func (w window) next(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	for i := 0; ; i++ {
+		day := local.AddDate(0, 0, i)
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.start/60, w.start%60, 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), w.end/60, w.end%60, 0, 0, loc)
+		if t.Before(end) {
+			if t.Before(start) {
+				return start.UTC()
+			}
+			return t
+		}
+	}
+}
+
+func parseWindow(start, end string) (window, error) {
+	from, err := time.Parse("15:04", start)
+	if err != nil {
+		return window{}, fmt.Errorf("invalid start %q", start)
+	}
+	to, err := time.Parse("15:04", end)
+	if err != nil {
+		return window{}, fmt.Errorf("invalid end %q", end)
+	}
+	w := window{start: from.Hour()*60 + from.Minute(), end: to.Hour()*60 + to.Minute()}
+	if w.start >= w.end {
+		return window{}, fmt.Errorf("start %s must be before end %s", start, end)
+	}
+	return w, nil
+}
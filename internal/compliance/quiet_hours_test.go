@@ -0,0 +1,99 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/acme/outbound-call-campaign/internal/config"
+)
+
+func testGuard(t *testing.T) *Guard {
+	t.Helper()
+	guard, err := NewGuard(config.QuietHoursConfig{
+		Start: "08:00",
+		End:   "21:00",
+		Regions: []config.QuietHoursRegion{
+			{Name: "us-fl", Prefixes: []string{"1305"}, Start: "08:00", End: "20:00"},
+			{Name: "late-start", Prefixes: []string{"+44"}, Start: "09:00", End: "23:00"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return guard
+}
+
+func TestGuardCheck(t *testing.T) {
+	guard := testGuard(t)
+
+	cases := []struct {
+		name    string
+		number  string
+		zone    string
+		at      time.Time
+		allowed bool
+		next    time.Time
+		rule    string
+	}{
+		{
+			// 14:00 UTC is 10:00 in New York during daylight saving time.
+			name: "inside default window", number: "+12125550100", zone: "America/New_York",
+			at: time.Date(2024, 6, 4, 14, 0, 0, 0, time.UTC), allowed: true, rule: "default",
+		},
+		{
+			// 11:00 UTC is 07:00 in New York.
+			name: "before opening", number: "+12125550100",
+			at: time.Date(2024, 6, 4, 11, 0, 0, 0, time.UTC), next: time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC), rule: "default",
+		},
+		{
+			// 00:30 UTC on the 5th is 20:30 on the 4th in Miami, past the stricter Florida close.
+			name: "region closes earlier", number: "+13055550100", zone: "America/New_York",
+			at: time.Date(2024, 6, 5, 0, 30, 0, 0, time.UTC), next: time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC), rule: "us-fl",
+		},
+		{
+			// A region cannot open the window later than the default close: 21:30 in London is blocked.
+			name: "region cannot widen", number: "+442079460000",
+			at: time.Date(2024, 6, 4, 20, 30, 0, 0, time.UTC), next: time.Date(2024, 6, 5, 8, 0, 0, 0, time.UTC), rule: "late-start",
+		},
+		{
+			// The recipient zone decides, not the number: 10:00 UTC is 19:00 in Tokyo.
+			name: "explicit zone", number: "+12125550100", zone: "Asia/Tokyo",
+			at: time.Date(2024, 6, 4, 10, 0, 0, 0, time.UTC), allowed: true, rule: "default",
+		},
+	}
+
+	for _, tc := range cases {
+		decision, err := guard.Check(tc.number, tc.zone, tc.at)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if decision.Allowed != tc.allowed || decision.Rule != tc.rule {
+			t.Errorf("%s: got allowed=%v rule=%q, want allowed=%v rule=%q", tc.name, decision.Allowed, decision.Rule, tc.allowed, tc.rule)
+		}
+		want := tc.next
+		if tc.allowed {
+			want = tc.at
+		}
+		if !decision.NextAllowed.Equal(want) {
+			t.Errorf("%s: next allowed %v, want %v", tc.name, decision.NextAllowed, want)
+		}
+	}
+
+	if _, err := guard.Check("+18505550100", "", time.Now()); err == nil {
+		t.Fatalf("expected an error for a number without a known time zone")
+	}
+}
+
+func TestNewGuardRejectsInvalidRegions(t *testing.T) {
+	cases := []config.QuietHoursConfig{
+		{Start: "21:00", End: "08:00"},
+		{Regions: []config.QuietHoursRegion{{Name: "no-overlap", Prefixes: []string{"1"}, Start: "21:30", End: "23:00"}}},
+		{Regions: []config.QuietHoursRegion{{Name: "no-prefix", Start: "09:00", End: "20:00"}}},
+		{Regions: []config.QuietHoursRegion{{Name: "bad-prefix", Prefixes: []string{"1-305"}, Start: "09:00", End: "20:00"}}},
+	}
+	for i, cfg := range cases {
+		if _, err := NewGuard(cfg); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
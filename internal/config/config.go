@@ -21,6 +21,8 @@ type Config struct {
 	Retry      RetryConfig      `mapstructure:"retry"`
 	Throttle   ThrottleConfig   `mapstructure:"throttle"`
 	CallBridge CallBridgeConfig `mapstructure:"call_bridge"`
+	Compliance ComplianceConfig `mapstructure:"compliance"`
+	Outbox     OutboxConfig     `mapstructure:"outbox"`
 }
 
 type AppConfig struct {
@@ -34,6 +36,7 @@ type HTTPConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	BodyLimit    int           `mapstructure:"body_limit"`
 }
 
 type PostgresConfig struct {
@@ -81,6 +84,11 @@ type RedisConfig struct {
 	PoolSize     int           `mapstructure:"pool_size"`
 	MinIdleConns int           `mapstructure:"min_idle_conns"`
 	MaxRetries   int           `mapstructure:"max_retries"`
+	// TargetCacheTTL caches phone-to-target lookups in Redis; zero disables the cache.
+	TargetCacheTTL time.Duration `mapstructure:"target_cache_ttl"`
+	// CampaignSettingsTTL caches the limits workers apply to in-flight calls; updates evict the
+	// entry, so the TTL only bounds staleness when an eviction is lost. Zero disables the cache.
+	CampaignSettingsTTL time.Duration `mapstructure:"campaign_settings_ttl"`
 }
 
 type TelemetryConfig struct {
@@ -102,6 +110,25 @@ type SchedulerConfig struct {
 	WorkerCount   int           `mapstructure:"worker_count"`
 	LockTTL       time.Duration `mapstructure:"lock_ttl"`
 	LockKeyPrefix string        `mapstructure:"lock_key_prefix"`
+	ClaimTTL      time.Duration `mapstructure:"claim_ttl"`
+	// StageSpread is the period after a business window opens over which targets staged
+	// within LookAhead of the opening are released.
+	StageSpread time.Duration `mapstructure:"stage_spread"`
+	// DispatchHeadroom is how many targets beyond a campaign's free concurrency slots may be
+	// queued per tick so workers are not left idle between ticks.
+	DispatchHeadroom int `mapstructure:"dispatch_headroom"`
+	// TickBudget caps the targets claimed per tick across all campaigns (0 means no cap); it is
+	// shared by deficit round robin, each campaign earning FairShareQuantum times its weight per round.
+	TickBudget       int `mapstructure:"tick_budget"`
+	FairShareQuantum int `mapstructure:"fair_share_quantum"`
+	// ReconcileInterval is how often the leader looks for targets and calls stuck in queued or dialing;
+	// ReconcileStaleAfter is how long they may sit there before being requeued or failed.
+	ReconcileInterval   time.Duration `mapstructure:"reconcile_interval"`
+	ReconcileStaleAfter time.Duration `mapstructure:"reconcile_stale_after"`
+	// RecipientWindowStart and RecipientWindowEnd (HH:MM) bound calls to recipient-local-time
+	// campaigns that have no business hours of their own.
+	RecipientWindowStart string `mapstructure:"recipient_window_start"`
+	RecipientWindowEnd   string `mapstructure:"recipient_window_end"`
 }
 
 type RetryConfig struct {
@@ -109,6 +136,9 @@ type RetryConfig struct {
 	BaseDelay   time.Duration `mapstructure:"base_delay"`
 	MaxDelay    time.Duration `mapstructure:"max_delay"`
 	Jitter      float64       `mapstructure:"jitter"`
+	// BacklogGrace is how long past its due time a tracked retry is still counted against its
+	// campaign before it is presumed lost.
+	BacklogGrace time.Duration `mapstructure:"backlog_grace"`
 }
 
 type ThrottleConfig struct {
@@ -119,6 +149,43 @@ type ThrottleConfig struct {
 type CallBridgeConfig struct {
 	ProviderName string        `mapstructure:"provider_name"`
 	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// CampaignCacheTTL is how long the call worker reuses a campaign's status and schedule when
+	// re-checking a dispatch before dialing.
+	CampaignCacheTTL time.Duration `mapstructure:"campaign_cache_ttl"`
+}
+
+// OutboxConfig tunes the relay that publishes outbox messages to Kafka.
+type OutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	// ClaimTTL is how long a relay holds claimed messages; those not marked sent by then are
+	// published again.
+	ClaimTTL time.Duration `mapstructure:"claim_ttl"`
+	// Retention is how long sent messages are kept before they are purged; zero keeps them.
+	Retention time.Duration `mapstructure:"retention"`
+	// MaxAttempts is how many times a message is claimed before the relay gives up on it.
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// ComplianceConfig holds regulatory calling rules that apply to every campaign.
+type ComplianceConfig struct {
+	QuietHours QuietHoursConfig `mapstructure:"quiet_hours"`
+}
+
+// QuietHoursConfig bounds when recipients may be called, in their local time (HH:MM). Regions
+// apply to numbers starting with one of their prefixes and can only narrow the window.
+type QuietHoursConfig struct {
+	Start   string             `mapstructure:"start"`
+	End     string             `mapstructure:"end"`
+	Regions []QuietHoursRegion `mapstructure:"regions"`
+}
+
+// QuietHoursRegion is a stricter calling window for numbers with one of Prefixes (E.164 digits).
+type QuietHoursRegion struct {
+	Name     string   `mapstructure:"name"`
+	Prefixes []string `mapstructure:"prefixes"`
+	Start    string   `mapstructure:"start"`
+	End      string   `mapstructure:"end"`
 }
 
 // Load reads configuration from file and environment variables.
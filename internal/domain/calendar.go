@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HolidayCalendar is a named, reusable list of dates on which linked campaigns do not dial.
+type HolidayCalendar struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Holidays    []Holiday
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Holiday is one date of a holiday calendar. Date holds the calendar date at midnight UTC and
+// is read in each campaign's own time zone.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// BlackoutDate closes a campaign for a whole calendar date in its time zone.
+type BlackoutDate struct {
+	Date   time.Time
+	Reason string
+}
+
+// OverrideWindow replaces a campaign's weekly business hours on one calendar date. A date may
+// have several override windows; an End not after Start runs past midnight.
+type OverrideWindow struct {
+	Date  time.Time
+	Start time.Time
+	End   time.Time
+}
+
+// DateKey renders a calendar date as YYYY-MM-DD, ignoring its time of day and location.
+func DateKey(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
@@ -1,9 +1,13 @@
 package domain
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
 )
 
 // CampaignStatus enumerates lifecycle states of a campaign.
@@ -15,8 +19,39 @@ const (
 	CampaignStatusCompleted  CampaignStatus = "completed"
 	CampaignStatusFailed     CampaignStatus = "failed"
 	CampaignStatusPaused     CampaignStatus = "paused"
+	CampaignStatusCancelled  CampaignStatus = "cancelled"
 )
 
+// campaignTransitions lists the statuses reachable from each non-terminal status.
+var campaignTransitions = map[CampaignStatus][]CampaignStatus{
+	CampaignStatusPending:    {CampaignStatusInProgress, CampaignStatusCompleted, CampaignStatusCancelled},
+	CampaignStatusInProgress: {CampaignStatusPaused, CampaignStatusCompleted, CampaignStatusFailed, CampaignStatusCancelled},
+	CampaignStatusPaused:     {CampaignStatusInProgress, CampaignStatusCompleted, CampaignStatusCancelled},
+}
+
+// CanTransitionTo reports whether the campaign may move from s to next.
+func (s CampaignStatus) CanTransitionTo(next CampaignStatus) bool {
+	for _, allowed := range campaignTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns ErrConflict when moving from s to next is not permitted.
+func (s CampaignStatus) ValidateTransition(next CampaignStatus) error {
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: campaign cannot move from %s to %s", apperrors.ErrConflict, s, next)
+	}
+	return nil
+}
+
+// IsTerminal reports whether no further transitions are possible from s.
+func (s CampaignStatus) IsTerminal() bool {
+	return len(campaignTransitions[s]) == 0
+}
+
 // CallStatus enumerates lifecycle stages for an individual call.
 type CallStatus string
 
@@ -27,22 +62,141 @@ const (
 	CallStatusCompleted CallStatus = "completed"
 	CallStatusFailed    CallStatus = "failed"
 	CallStatusRetrying  CallStatus = "retrying"
+	CallStatusCancelled CallStatus = "cancelled"
+	// CallStatusDeferred reports an attempt held back before dialing; its target is deferred and
+	// claimed again for a new call once it may be dialed.
+	CallStatusDeferred CallStatus = "deferred"
+	// CallStatusSkipped reports an attempt dropped before dialing because it went stale; its
+	// target returns to pending.
+	CallStatusSkipped CallStatus = "skipped"
 )
 
-// Campaign models an outbound call campaign definition.
+// Target states recorded on campaign_targets.state.
+const (
+	TargetStatePending    = "pending"
+	TargetStateStaged     = "staged"
+	TargetStateDeferred   = "deferred"
+	TargetStateQueued     = "queued"
+	TargetStateDialing    = "dialing"
+	TargetStateRetrying   = "retrying"
+	TargetStateCompleted  = "completed"
+	TargetStateFailed     = "failed"
+	TargetStateCancelled  = "cancelled"
+	TargetStateExpired    = "expired"
+	TargetStateSuppressed = "suppressed"
+)
+
+// TargetStates lists every state a campaign target can be in.
+var TargetStates = []string{
+	TargetStatePending, TargetStateStaged, TargetStateDeferred, TargetStateQueued, TargetStateDialing, TargetStateRetrying,
+	TargetStateCompleted, TargetStateFailed, TargetStateCancelled, TargetStateExpired, TargetStateSuppressed,
+}
+
+// ActiveTargetStates lists target states that still require scheduler or worker action.
+var ActiveTargetStates = []string{TargetStatePending, TargetStateStaged, TargetStateDeferred, TargetStateQueued, TargetStateDialing, TargetStateRetrying}
+
+// TargetStateForCall maps a call status report to the state of the target being called.
+// It returns "" for statuses that do not move the target.
+func TargetStateForCall(status CallStatus, retryable bool) string {
+	switch status {
+	case CallStatusDialing:
+		return TargetStateDialing
+	case CallStatusCompleted:
+		return TargetStateCompleted
+	case CallStatusFailed:
+		if retryable {
+			return TargetStateRetrying
+		}
+		return TargetStateFailed
+	case CallStatusRetrying:
+		return TargetStateRetrying
+	case CallStatusDeferred:
+		return TargetStateDeferred
+	case CallStatusCancelled:
+		return TargetStateCancelled
+	case CallStatusSkipped:
+		return TargetStatePending
+	default:
+		return ""
+	}
+}
+
+// IsTerminalTargetState reports whether the target needs no further work.
+func IsTerminalTargetState(state string) bool {
+	for _, active := range ActiveTargetStates {
+		if state == active {
+			return false
+		}
+	}
+	return true
+}
+
+// DuplicatePolicy decides what happens when a target number is already in the campaign.
+type DuplicatePolicy string
+
+const (
+	// DuplicatePolicySkip leaves the existing target alone.
+	DuplicatePolicySkip DuplicatePolicy = "skip"
+	// DuplicatePolicyError rejects the duplicate row.
+	DuplicatePolicyError DuplicatePolicy = "error"
+	// DuplicatePolicyReset puts a finished target back to pending so it is dialled again.
+	DuplicatePolicyReset DuplicatePolicy = "reset"
+)
+
+// ParseDuplicatePolicy validates a policy name, defaulting to DuplicatePolicySkip when empty.
+func ParseDuplicatePolicy(value string) (DuplicatePolicy, error) {
+	switch policy := DuplicatePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return DuplicatePolicySkip, nil
+	case DuplicatePolicySkip, DuplicatePolicyError, DuplicatePolicyReset:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: unknown duplicate policy %q", apperrors.ErrValidation, value)
+	}
+}
+
+// Campaign models an outbound call campaign definition. Campaigns with a higher Priority are
+// scheduled before lower ones; Weight sets a campaign's share among those of equal priority.
+// Holidays holds the dates of the linked holiday calendars. With RecipientLocalTime the calling
+// windows are read in each target's time zone rather than in TimeZone. ConfigVersion is bumped
+// whenever the concurrency limit or retry policy changes.
 type Campaign struct {
 	ID                 uuid.UUID
 	Name               string
 	Description        string
 	TimeZone           string
+	RecipientLocalTime bool
+	DefaultCountry     string
 	BusinessHours      []BusinessHourWindow
+	HolidayCalendarIDs []uuid.UUID
+	Holidays           []Holiday
+	Blackouts          []BlackoutDate
+	OverrideWindows    []OverrideWindow
 	MaxConcurrentCalls int
+	Priority           int
+	Weight             int
 	RetryPolicy        RetryPolicy
+	ConfigVersion      int64
 	Status             CampaignStatus
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	StartAt            *time.Time
+	EndAt              *time.Time
 	StartedAt          *time.Time
 	CompletedAt        *time.Time
+	CompletionSummary  *CompletionSummary
+}
+
+// CompletionSummary captures final campaign tallies recorded when a campaign completes.
+type CompletionSummary struct {
+	TotalTargets     int64            `json:"total_targets"`
+	TargetsByState   map[string]int64 `json:"targets_by_state"`
+	TotalCalls       int64            `json:"total_calls"`
+	CompletedCalls   int64            `json:"completed_calls"`
+	FailedCalls      int64            `json:"failed_calls"`
+	RetriesAttempted int64            `json:"retries_attempted"`
+	Automatic        bool             `json:"automatic"`
+	CompletedAt      time.Time        `json:"completed_at"`
 }
 
 // BusinessHourWindow captures allowed calling window per day of week.
@@ -60,6 +214,41 @@ type RetryPolicy struct {
 	Jitter      float64
 }
 
+// CampaignSettings are the limits dispatch workers apply to a campaign's calls in flight.
+type CampaignSettings struct {
+	CampaignID         uuid.UUID
+	Version            int64
+	MaxConcurrentCalls int
+	RetryPolicy        RetryPolicy
+}
+
+// Settings returns the campaign's current dispatch settings.
+func (c *Campaign) Settings() CampaignSettings {
+	return CampaignSettings{
+		CampaignID:         c.ID,
+		Version:            c.ConfigVersion,
+		MaxConcurrentCalls: c.MaxConcurrentCalls,
+		RetryPolicy:        c.RetryPolicy,
+	}
+}
+
+// WithDefaults fills unset limits from the given defaults.
+func (s CampaignSettings) WithDefaults(concurrency int, retry RetryPolicy) CampaignSettings {
+	if s.MaxConcurrentCalls <= 0 {
+		s.MaxConcurrentCalls = concurrency
+	}
+	if s.RetryPolicy.MaxAttempts <= 0 {
+		s.RetryPolicy.MaxAttempts = retry.MaxAttempts
+	}
+	if s.RetryPolicy.BaseDelay <= 0 {
+		s.RetryPolicy.BaseDelay = retry.BaseDelay
+	}
+	if s.RetryPolicy.MaxDelay <= 0 {
+		s.RetryPolicy.MaxDelay = retry.MaxDelay
+	}
+	return s
+}
+
 // Call represents an individual outbound call within a campaign.
 type Call struct {
 	ID            uuid.UUID
@@ -94,3 +283,13 @@ type CampaignStats struct {
 	PendingCalls     int64 `db:"pending_calls"`
 	RetriesAttempted int64 `db:"retries_attempted"`
 }
+
+// SuppressedNumber is a do-not-call entry. A nil CampaignID suppresses the number for every campaign.
+type SuppressedNumber struct {
+	ID          int64
+	CampaignID  *uuid.UUID
+	PhoneNumber string
+	Reason      string
+	CreatedBy   string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+func TestCampaignStatusTransitions(t *testing.T) {
+	allowed := []struct{ from, to CampaignStatus }{
+		{CampaignStatusPending, CampaignStatusInProgress},
+		{CampaignStatusPending, CampaignStatusCompleted},
+		{CampaignStatusPending, CampaignStatusCancelled},
+		{CampaignStatusInProgress, CampaignStatusPaused},
+		{CampaignStatusInProgress, CampaignStatusCompleted},
+		{CampaignStatusPaused, CampaignStatusInProgress},
+		{CampaignStatusPaused, CampaignStatusCancelled},
+	}
+	for _, tc := range allowed {
+		if err := tc.from.ValidateTransition(tc.to); err != nil {
+			t.Errorf("expected %s -> %s to be allowed, got %v", tc.from, tc.to, err)
+		}
+	}
+
+	rejected := []struct{ from, to CampaignStatus }{
+		{CampaignStatusPending, CampaignStatusPaused},
+		{CampaignStatusPending, CampaignStatusFailed},
+		{CampaignStatusCompleted, CampaignStatusPaused},
+		{CampaignStatusCompleted, CampaignStatusInProgress},
+		{CampaignStatusCancelled, CampaignStatusInProgress},
+	}
+	for _, tc := range rejected {
+		err := tc.from.ValidateTransition(tc.to)
+		if !errors.Is(err, apperrors.ErrConflict) {
+			t.Errorf("expected %s -> %s to fail with conflict, got %v", tc.from, tc.to, err)
+		}
+	}
+
+	if !CampaignStatusCompleted.IsTerminal() || !CampaignStatusCancelled.IsTerminal() {
+		t.Fatalf("expected completed and cancelled to be terminal")
+	}
+	if CampaignStatusPaused.IsTerminal() {
+		t.Fatalf("expected paused to be non-terminal")
+	}
+}
+
+func TestTargetStateForCall(t *testing.T) {
+	cases := []struct {
+		status    CallStatus
+		retryable bool
+		want      string
+	}{
+		{CallStatusDialing, false, TargetStateDialing},
+		{CallStatusCompleted, false, TargetStateCompleted},
+		{CallStatusFailed, true, TargetStateRetrying},
+		{CallStatusFailed, false, TargetStateFailed},
+		{CallStatusCancelled, false, TargetStateCancelled},
+		{CallStatusDeferred, false, TargetStateDeferred},
+		{CallStatusSkipped, false, TargetStatePending},
+		{CallStatusQueued, false, ""},
+	}
+	for _, tc := range cases {
+		if got := TargetStateForCall(tc.status, tc.retryable); got != tc.want {
+			t.Errorf("TargetStateForCall(%s, %v) = %q, want %q", tc.status, tc.retryable, got, tc.want)
+		}
+	}
+
+	if IsTerminalTargetState(TargetStateRetrying) || IsTerminalTargetState(TargetStateDialing) {
+		t.Fatalf("expected in-flight target states to be non-terminal")
+	}
+	if !IsTerminalTargetState(TargetStateCompleted) || !IsTerminalTargetState(TargetStateFailed) {
+		t.Fatalf("expected completed and failed target states to be terminal")
+	}
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	cases := map[string]DuplicatePolicy{
+		"":       DuplicatePolicySkip,
+		"skip":   DuplicatePolicySkip,
+		"Error":  DuplicatePolicyError,
+		" reset": DuplicatePolicyReset,
+	}
+	for input, want := range cases {
+		got, err := ParseDuplicatePolicy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuplicatePolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseDuplicatePolicy("overwrite"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestCampaignSettingsWithDefaults(t *testing.T) {
+	defaults := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.2}
+
+	got := CampaignSettings{Version: 4}.WithDefaults(10, defaults)
+	want := CampaignSettings{Version: 4, MaxConcurrentCalls: 10, RetryPolicy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}}
+	if got != want {
+		t.Fatalf("WithDefaults on empty settings = %+v, want %+v", got, want)
+	}
+
+	set := CampaignSettings{Version: 7, MaxConcurrentCalls: 2, RetryPolicy: RetryPolicy{MaxAttempts: 1, BaseDelay: 5 * time.Second, MaxDelay: 10 * time.Second, Jitter: 0.1}}
+	if got := set.WithDefaults(10, defaults); got != set {
+		t.Fatalf("WithDefaults overrode campaign settings: got %+v, want %+v", got, set)
+	}
+}
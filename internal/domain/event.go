@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignEventType names a lifecycle change recorded in the campaign audit trail.
+type CampaignEventType string
+
+const (
+	CampaignEventCreated              CampaignEventType = "campaign.created"
+	CampaignEventUpdated              CampaignEventType = "campaign.updated"
+	CampaignEventStarted              CampaignEventType = "campaign.started"
+	CampaignEventPaused               CampaignEventType = "campaign.paused"
+	CampaignEventResumed              CampaignEventType = "campaign.resumed"
+	CampaignEventCompleted            CampaignEventType = "campaign.completed"
+	CampaignEventCancelled            CampaignEventType = "campaign.cancelled"
+	CampaignEventTargetsAdded         CampaignEventType = "campaign.targets_added"
+	CampaignEventTargetUpdated        CampaignEventType = "campaign.target_updated"
+	CampaignEventTargetRemoved        CampaignEventType = "campaign.target_removed"
+	CampaignEventBusinessHoursChanged CampaignEventType = "campaign.business_hours_changed"
+	CampaignEventCalendarChanged      CampaignEventType = "campaign.calendar_changed"
+)
+
+// CampaignEvent is a single audit trail entry with the state before and after the change.
+type CampaignEvent struct {
+	ID         int64
+	CampaignID uuid.UUID
+	Type       CampaignEventType
+	Actor      string
+	Before     json.RawMessage
+	After      json.RawMessage
+	CreatedAt  time.Time
+}
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// Interval is a half-open span [Start, End) in UTC.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t falls inside the interval.
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// nextOpenHorizon bounds how far NextOpen searches for a campaign without an end. Blackouts and
+// holidays can keep a campaign closed for much longer than a week.
+const nextOpenHorizon = 366 * 24 * time.Hour
+
+// nextOpenStep is how much of the horizon NextOpen expands at a time; business hours repeat
+// weekly, so the first step finds the opening unless date rules close the whole week.
+const nextOpenStep = 8 * 24 * time.Hour
+
+// OpenIntervals expands the campaign's business hours into the UTC intervals in which it may
+// dial between from and to, clipped to that range. The campaign's start_at/end_at are not applied.
+// Windows are read as wall-clock times in the campaign's time zone, so their UTC offsets follow
+// daylight saving changes; a window whose end is not after its start runs past midnight into
+// the next day. A campaign without business hours is open throughout.
+//
+// Dates are resolved per local calendar date, in order: a blackout closes the date; override
+// windows replace the weekly windows (even on a holiday); a holiday closes the date. A window
+// belongs to the date it starts on, so one running past midnight into a holiday is kept.
+func (c *Campaign) OpenIntervals(from, to time.Time) ([]Interval, error) {
+	from, to = from.UTC(), to.UTC()
+	if !to.After(from) {
+		return nil, nil
+	}
+	if len(c.BusinessHours) == 0 && !c.hasDateRules() {
+		return []Interval{{Start: from, End: to}}, nil
+	}
+
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid time zone %s: %v", apperrors.ErrValidation, c.TimeZone, err)
+	}
+
+	// Start a day early so windows that began yesterday and run past midnight are included.
+	first := from.In(loc).AddDate(0, 0, -1)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	rules := c.dateRules()
+	var intervals []Interval
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, window := range rules.windowsOn(day, c.BusinessHours) {
+			start := time.Date(day.Year(), day.Month(), day.Day(), window.start.Hour(), window.start.Minute(), 0, 0, loc)
+			endDay := day
+			if minuteOfDay(window.end) <= minuteOfDay(window.start) {
+				endDay = day.AddDate(0, 0, 1)
+			}
+			end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), window.end.Hour(), window.end.Minute(), 0, 0, loc)
+
+			interval := Interval{Start: maxTime(start.UTC(), from), End: minTime(end.UTC(), to)}
+			if interval.End.After(interval.Start) {
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+	return mergeIntervals(intervals), nil
+}
+
+// clockWindow is a start/end wall-clock pair on a single date.
+type clockWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// dateRules indexes a campaign's date-specific schedule changes by YYYY-MM-DD.
+type dateRules struct {
+	closed    map[string]bool
+	holidays  map[string]bool
+	overrides map[string][]clockWindow
+}
+
+func (c *Campaign) hasDateRules() bool {
+	return len(c.Holidays) > 0 || len(c.Blackouts) > 0 || len(c.OverrideWindows) > 0
+}
+
+func (c *Campaign) dateRules() dateRules {
+	rules := dateRules{
+		closed:    make(map[string]bool, len(c.Blackouts)),
+		holidays:  make(map[string]bool, len(c.Holidays)),
+		overrides: make(map[string][]clockWindow, len(c.OverrideWindows)),
+	}
+	for _, b := range c.Blackouts {
+		rules.closed[DateKey(b.Date)] = true
+	}
+	for _, h := range c.Holidays {
+		rules.holidays[DateKey(h.Date)] = true
+	}
+	for _, o := range c.OverrideWindows {
+		key := DateKey(o.Date)
+		rules.overrides[key] = append(rules.overrides[key], clockWindow{start: o.Start, end: o.End})
+	}
+	return rules
+}
+
+// windowsOn returns the windows that open on the local date day.
+func (r dateRules) windowsOn(day time.Time, weekly []BusinessHourWindow) []clockWindow {
+	key := DateKey(day)
+	if r.closed[key] {
+		return nil
+	}
+	if overrides, ok := r.overrides[key]; ok {
+		return overrides
+	}
+	if r.holidays[key] {
+		return nil
+	}
+	if len(weekly) == 0 {
+		// Without weekly hours the campaign is open all day on ordinary dates.
+		return []clockWindow{{start: day, end: day}}
+	}
+	var windows []clockWindow
+	for _, w := range weekly {
+		if w.DayOfWeek == day.Weekday() {
+			windows = append(windows, clockWindow{start: w.Start, end: w.End})
+		}
+	}
+	return windows
+}
+
+// DailyWindow is a wall-clock window applied on every day of the week.
+type DailyWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseDailyWindow parses an HH:MM start and end. Both empty yields the zero window.
+func ParseDailyWindow(start, end string) (DailyWindow, error) {
+	if start == "" && end == "" {
+		return DailyWindow{}, nil
+	}
+	from, err := time.Parse("15:04", start)
+	if err != nil {
+		return DailyWindow{}, fmt.Errorf("%w: invalid window start %q", apperrors.ErrValidation, start)
+	}
+	to, err := time.Parse("15:04", end)
+	if err != nil {
+		return DailyWindow{}, fmt.Errorf("%w: invalid window end %q", apperrors.ErrValidation, end)
+	}
+	return DailyWindow{Start: from, End: to}, nil
+}
+
+// IsZero reports whether the window is unset.
+func (w DailyWindow) IsZero() bool {
+	return w.Start.IsZero() && w.End.IsZero()
+}
+
+// Weekly expands the window into one business hour window per weekday.
+func (w DailyWindow) Weekly() []BusinessHourWindow {
+	windows := make([]BusinessHourWindow, 0, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		windows = append(windows, BusinessHourWindow{DayOfWeek: day, Start: w.Start, End: w.End})
+	}
+	return windows
+}
+
+// ForRecipient returns a copy of the campaign whose schedule is read in the recipient's time
+// zone. An empty zone keeps the campaign's own. A campaign without business hours uses fallback
+// on every day, unless fallback is unset.
+func (c *Campaign) ForRecipient(zone string, fallback DailyWindow) *Campaign {
+	local := *c
+	if zone != "" {
+		local.TimeZone = zone
+	}
+	if len(local.BusinessHours) == 0 && !fallback.IsZero() {
+		local.BusinessHours = fallback.Weekly()
+	}
+	return &local
+}
+
+// IsOpenAt reports whether the campaign may dial at t.
+func (c *Campaign) IsOpenAt(t time.Time) (bool, error) {
+	intervals, err := c.OpenIntervals(t, t.Add(time.Minute))
+	if err != nil {
+		return false, err
+	}
+	return len(intervals) > 0 && intervals[0].Contains(t), nil
+}
+
+// NextOpen returns the earliest time at or after t at which the campaign may dial, searching
+// until the campaign ends, or a year ahead when it has no end_at. It reports false when nothing
+// opens before then.
+func (c *Campaign) NextOpen(t time.Time) (time.Time, bool, error) {
+	limit := t.Add(nextOpenHorizon)
+	if c.EndAt != nil && c.EndAt.Before(limit) {
+		limit = *c.EndAt
+	}
+	for from := t; from.Before(limit); from = from.Add(nextOpenStep) {
+		intervals, err := c.OpenIntervals(from, minTime(from.Add(nextOpenStep), limit))
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if len(intervals) > 0 {
+			return intervals[0].Start, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+func mergeIntervals(intervals []Interval) []Interval {
+	if len(intervals) < 2 {
+		return intervals
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	merged := intervals[:1]
+	for _, next := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if !next.Start.After(last.End) {
+			if next.End.After(last.End) {
+				last.End = next.End
+			}
+			continue
+		}
+		merged = append(merged, next)
+	}
+	return merged
+}
+
+func minuteOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
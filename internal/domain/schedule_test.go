@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func clock(h, m int) time.Time {
+	return time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+}
+
+func TestOpenIntervalsFollowsDaylightSaving(t *testing.T) {
+	campaign := &Campaign{
+		TimeZone: "America/New_York",
+		BusinessHours: []BusinessHourWindow{
+			{DayOfWeek: time.Friday, Start: clock(9, 0), End: clock(17, 0)},
+			{DayOfWeek: time.Monday, Start: clock(9, 0), End: clock(17, 0)},
+		},
+	}
+
+	// US clocks moved forward on Sunday 10 March 2024.
+	from := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+	intervals, err := campaign.OpenIntervals(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Interval{
+		{Start: time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 8, 22, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 11, 21, 0, 0, 0, time.UTC)},
+	}
+	if len(intervals) != len(want) {
+		t.Fatalf("expected %d intervals, got %v", len(want), intervals)
+	}
+	for i := range want {
+		if !intervals[i].Start.Equal(want[i].Start) || !intervals[i].End.Equal(want[i].End) {
+			t.Fatalf("interval %d: expected %v, got %v", i, want[i], intervals[i])
+		}
+	}
+}
+
+func TestOpenIntervalsSpanningMidnight(t *testing.T) {
+	campaign := &Campaign{
+		TimeZone: "UTC",
+		BusinessHours: []BusinessHourWindow{
+			{DayOfWeek: time.Monday, Start: clock(22, 0), End: clock(2, 0)},
+			{DayOfWeek: time.Tuesday, Start: clock(1, 0), End: clock(4, 0)},
+		},
+	}
+
+	// Starting inside Monday's window clips its start; Tuesday's overlapping window merges in.
+	from := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	intervals, err := campaign.OpenIntervals(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(intervals) != 1 {
+		t.Fatalf("expected one merged interval, got %v", intervals)
+	}
+	if !intervals[0].Start.Equal(from) || !intervals[0].End.Equal(time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected interval %v", intervals[0])
+	}
+}
+
+func TestOpenIntervalsWithoutBusinessHours(t *testing.T) {
+	campaign := &Campaign{TimeZone: "UTC"}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+
+	intervals, err := campaign.OpenIntervals(from, to)
+	if err != nil || len(intervals) != 1 || !intervals[0].Start.Equal(from) || !intervals[0].End.Equal(to) {
+		t.Fatalf("expected the whole range to be open, got %v (err=%v)", intervals, err)
+	}
+}
+
+func TestNextOpen(t *testing.T) {
+	campaign := &Campaign{
+		TimeZone: "America/New_York",
+		BusinessHours: []BusinessHourWindow{
+			{DayOfWeek: time.Monday, Start: clock(9, 0), End: clock(17, 0)},
+			{DayOfWeek: time.Wednesday, Start: clock(10, 30), End: clock(17, 0)},
+		},
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before Monday opens", time.Date(2024, 1, 1, 13, 55, 0, 0, time.UTC), time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)},
+		{"inside Monday window", time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)},
+		{"after Monday closes", time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC), time.Date(2024, 1, 3, 15, 30, 0, 0, time.UTC)},
+		{"wraps to next week", time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, ok, err := campaign.NextOpen(tc.now)
+		if err != nil || !ok || !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v (ok=%v, err=%v)", tc.name, tc.want, got, ok, err)
+		}
+	}
+
+	if _, _, err := (&Campaign{TimeZone: "Not/AZone", BusinessHours: campaign.BusinessHours}).NextOpen(time.Now()); err == nil {
+		t.Fatalf("expected an error for an unknown time zone")
+	}
+}
+
+func TestNextOpenAfterLongBlackout(t *testing.T) {
+	// Closed every day of December 2024; open 09:00–17:00 UTC on weekdays otherwise.
+	var blackouts []BlackoutDate
+	for day := 1; day <= 31; day++ {
+		blackouts = append(blackouts, BlackoutDate{Date: time.Date(2024, 12, day, 0, 0, 0, 0, time.UTC)})
+	}
+	var hours []BusinessHourWindow
+	for d := time.Monday; d <= time.Friday; d++ {
+		hours = append(hours, BusinessHourWindow{DayOfWeek: d, Start: clock(9, 0), End: clock(17, 0)})
+	}
+	campaign := &Campaign{TimeZone: "UTC", BusinessHours: hours, Blackouts: blackouts}
+	now := time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC)
+
+	got, ok, err := campaign.NextOpen(now)
+	if want := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC); err != nil || !ok || !got.Equal(want) {
+		t.Fatalf("expected %v, got %v (ok=%v, err=%v)", want, got, ok, err)
+	}
+
+	endAt := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	campaign.EndAt = &endAt
+	if got, ok, err := campaign.NextOpen(now); err != nil || ok {
+		t.Fatalf("expected nothing before the campaign ends, got %v (ok=%v, err=%v)", got, ok, err)
+	}
+}
+
+func TestOpenIntervalsHonoursDateRules(t *testing.T) {
+	weekday := func(d time.Weekday) BusinessHourWindow {
+		return BusinessHourWindow{DayOfWeek: d, Start: clock(9, 0), End: clock(17, 0)}
+	}
+	date := func(day int) time.Time { return time.Date(2024, 12, day, 0, 0, 0, 0, time.UTC) }
+	campaign := &Campaign{
+		TimeZone:      "Europe/London",
+		BusinessHours: []BusinessHourWindow{weekday(time.Monday), weekday(time.Tuesday), weekday(time.Wednesday), weekday(time.Thursday)},
+		// Monday 23rd is overridden, Tuesday 24th blacked out, Wednesday 25th a holiday,
+		// Thursday 26th a holiday with an override that wins.
+		Holidays:  []Holiday{{Date: date(25), Name: "Christmas Day"}, {Date: date(26), Name: "Boxing Day"}},
+		Blackouts: []BlackoutDate{{Date: date(24), Reason: "office closed"}},
+		OverrideWindows: []OverrideWindow{
+			{Date: date(23), Start: clock(10, 0), End: clock(12, 0)},
+			{Date: date(26), Start: clock(11, 0), End: clock(13, 0)},
+		},
+	}
+
+	intervals, err := campaign.OpenIntervals(date(23), date(27))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Interval{
+		{Start: time.Date(2024, 12, 23, 10, 0, 0, 0, time.UTC), End: time.Date(2024, 12, 23, 12, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 12, 26, 11, 0, 0, 0, time.UTC), End: time.Date(2024, 12, 26, 13, 0, 0, 0, time.UTC)},
+	}
+	if len(intervals) != len(want) {
+		t.Fatalf("expected %v, got %v", want, intervals)
+	}
+	for i := range want {
+		if !intervals[i].Start.Equal(want[i].Start) || !intervals[i].End.Equal(want[i].End) {
+			t.Fatalf("interval %d: expected %v, got %v", i, want[i], intervals[i])
+		}
+	}
+}
+
+func TestOpenIntervalsHolidayWithoutBusinessHours(t *testing.T) {
+	campaign := &Campaign{
+		TimeZone: "Asia/Tokyo",
+		Holidays: []Holiday{{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+	}
+	// 1–3 January in Tokyo, with the 2nd closed.
+	from := time.Date(2023, 12, 31, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+
+	intervals, err := campaign.OpenIntervals(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(intervals) != 2 ||
+		!intervals[0].Start.Equal(from) || !intervals[0].End.Equal(time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)) ||
+		!intervals[1].Start.Equal(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)) || !intervals[1].End.Equal(to) {
+		t.Fatalf("unexpected intervals %v", intervals)
+	}
+}
+
+func TestForRecipient(t *testing.T) {
+	campaign := &Campaign{TimeZone: "America/New_York"}
+	window := DailyWindow{Start: clock(8, 0), End: clock(21, 0)}
+
+	// 14:00 UTC is 10:00 in New York and 07:00 in Los Angeles during daylight saving time.
+	at := time.Date(2024, 6, 4, 14, 0, 0, 0, time.UTC)
+	cases := []struct {
+		zone string
+		want bool
+	}{
+		{"America/New_York", true},
+		{"America/Los_Angeles", false},
+		{"", true},
+	}
+	for _, tc := range cases {
+		open, err := campaign.ForRecipient(tc.zone, window).IsOpenAt(at)
+		if err != nil {
+			t.Fatalf("zone %q: unexpected error: %v", tc.zone, err)
+		}
+		if open != tc.want {
+			t.Errorf("zone %q: expected open=%v, got %v", tc.zone, tc.want, open)
+		}
+	}
+
+	if campaign.TimeZone != "America/New_York" || len(campaign.BusinessHours) != 0 {
+		t.Fatalf("ForRecipient modified the campaign: %+v", campaign)
+	}
+
+	campaign.BusinessHours = []BusinessHourWindow{{DayOfWeek: time.Tuesday, Start: clock(6, 0), End: clock(8, 0)}}
+	open, err := campaign.ForRecipient("America/Los_Angeles", window).IsOpenAt(at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !open {
+		t.Fatalf("expected campaign business hours to replace the fallback window")
+	}
+}
@@ -6,44 +6,47 @@ import (
 	"github.com/google/uuid"
 )
 
-// DispatchMessage represents an instruction to initiate a call attempt.
+// DispatchMessage represents an instruction to initiate a call attempt. Workers resolve the
+// campaign's concurrency limit and retry policy when they handle it; ConfigVersion records the
+// campaign settings version current at enqueue time, for audit only. EndAt is the campaign's
+// scheduled end at enqueue time; the call is not dialed from then on, even if the campaign
+// cannot be looked up.
 type DispatchMessage struct {
 	CallID           uuid.UUID         `json:"call_id"`
 	CampaignID       uuid.UUID         `json:"campaign_id"`
+	TargetID         uuid.UUID         `json:"target_id,omitzero"`
 	PhoneNumber      string            `json:"phone_number"`
+	TimeZone         string            `json:"time_zone,omitempty"`
 	Attempt          int               `json:"attempt"`
-	MaxAttempts      int               `json:"max_attempts"`
-	RetryBaseMs      int64             `json:"retry_base_ms"`
-	RetryMaxMs       int64             `json:"retry_max_ms"`
-	RetryJitter      float64           `json:"retry_jitter"`
-	ConcurrencyLimit int               `json:"concurrency_limit"`
+	ConfigVersion    int64             `json:"config_version,omitempty"`
 	Metadata         map[string]any    `json:"metadata"`
 	EnqueuedAt       time.Time         `json:"enqueued_at"`
+	EndAt            *time.Time        `json:"end_at,omitempty"`
 }
 
-// StatusMessage represents the outcome of a call attempt.
+// StatusMessage represents the outcome of a call attempt. ConfigVersion is the campaign
+// settings version its retry decision was made under. EndAt carries the dispatch's EndAt over
+// to a retry.
 type StatusMessage struct {
 	CallID           uuid.UUID      `json:"call_id"`
 	CampaignID       uuid.UUID      `json:"campaign_id"`
+	TargetID         uuid.UUID      `json:"target_id,omitzero"`
 	PhoneNumber      string         `json:"phone_number"`
+	TimeZone         string         `json:"time_zone,omitempty"`
 	Status           string         `json:"status"`
 	Attempt          int            `json:"attempt"`
-	MaxAttempts      int            `json:"max_attempts"`
 	Retryable        bool           `json:"retryable"`
-	RetryBaseMs      int64          `json:"retry_base_ms"`
-	RetryMaxMs       int64          `json:"retry_max_ms"`
-	RetryJitter      float64        `json:"retry_jitter"`
-	ConcurrencyLimit int            `json:"concurrency_limit"`
+	ConfigVersion    int64          `json:"config_version,omitempty"`
 	DurationMs       int64          `json:"duration_ms"`
 	Error            string         `json:"error,omitempty"`
 	OccurredAt       time.Time      `json:"occurred_at"`
 	NextAttempt      *time.Time     `json:"next_attempt,omitempty"`
+	EndAt            *time.Time     `json:"end_at,omitempty"`
 	Metadata         map[string]any `json:"metadata"`
 }
 
 // RetryMessage represents a retry instruction for a failed call.
 type RetryMessage struct {
 	DispatchMessage
-	MaxAttempts  int       `json:"max_attempts"`
 	NextAttempt  time.Time `json:"next_attempt"`
 }
@@ -7,8 +7,14 @@ import (
 	"time"
 
 	"github.com/segmentio/kafka-go"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
 )
 
+// ReasonCampaignCancelled is recorded on calls cancelled along with their campaign before they
+// were dialed.
+const ReasonCampaignCancelled = "campaign cancelled"
+
 // StatusPublisher publishes call status events.
 type StatusPublisher struct {
 	writer *kafka.Writer
@@ -36,6 +42,60 @@ func (p *StatusPublisher) PublishStatus(ctx context.Context, msg StatusMessage)
 	return nil
 }
 
+// PublishCancelled reports the dispatch's call as cancelled along with its campaign, without
+// it having been dialed.
+func (p *StatusPublisher) PublishCancelled(ctx context.Context, dispatch DispatchMessage) error {
+	return p.PublishStatus(ctx, StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		Status:        string(domain.CallStatusCancelled),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		Error:         ReasonCampaignCancelled,
+		OccurredAt:    time.Now().UTC(),
+		Metadata:      dispatch.Metadata,
+	})
+}
+
+// PublishSkipped reports the dispatch's call as skipped for reason without it having been
+// dialed, handing its target back.
+func (p *StatusPublisher) PublishSkipped(ctx context.Context, dispatch DispatchMessage, reason string) error {
+	return p.PublishStatus(ctx, StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		TimeZone:      dispatch.TimeZone,
+		Status:        string(domain.CallStatusSkipped),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		Error:         reason,
+		OccurredAt:    time.Now().UTC(),
+		Metadata:      dispatch.Metadata,
+	})
+}
+
+// PublishUndelivered reports the dispatch's call as failed, without a retry, because the
+// dispatch itself could not be published.
+func (p *StatusPublisher) PublishUndelivered(ctx context.Context, dispatch DispatchMessage, reason string) error {
+	return p.PublishStatus(ctx, StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		TimeZone:      dispatch.TimeZone,
+		Status:        string(domain.CallStatusFailed),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		Error:         reason,
+		OccurredAt:    time.Now().UTC(),
+		EndAt:         dispatch.EndAt,
+		Metadata:      dispatch.Metadata,
+	})
+}
+
 // Close closes the publisher.
 func (p *StatusPublisher) Close() error {
 	return p.writer.Close()
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+const campaignSettingsKeyPrefix = "campaign:settings:"
+
+// storeSettingsScript caches ARGV[2], the settings at config version ARGV[1], for ARGV[3]
+// milliseconds unless the entry already holds a newer version. This stops a reader that loaded
+// the row just before an update from putting the old settings back after the update stored the
+// new ones.
+var storeSettingsScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+  local ok, decoded = pcall(cjson.decode, current)
+  if ok and tonumber(decoded['version']) and tonumber(decoded['version']) > tonumber(ARGV[1]) then
+    return 0
+  end
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// CampaignRepository caches campaign dispatch settings in Redis in front of another campaign
+// repository, so workers can resolve them for every message. Every other method is passed
+// straight through.
+type CampaignRepository struct {
+	repository.CampaignRepository
+	client *redis.Client
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewCampaignRepository wraps inner with a settings cache whose entries live for ttl.
+func NewCampaignRepository(inner repository.CampaignRepository, client *redis.Client, ttl time.Duration, lg *logger.Logger) *CampaignRepository {
+	return &CampaignRepository{CampaignRepository: inner, client: client, ttl: ttl, logger: lg}
+}
+
+type settingsRecord struct {
+	Version            int64   `json:"version"`
+	MaxConcurrentCalls int     `json:"max_concurrent_calls"`
+	RetryMaxAttempts   int     `json:"retry_max_attempts"`
+	RetryBaseDelayMs   int64   `json:"retry_base_delay_ms"`
+	RetryMaxDelayMs    int64   `json:"retry_max_delay_ms"`
+	RetryJitter        float64 `json:"retry_jitter"`
+}
+
+// GetSettings serves the settings from Redis when possible; a Redis failure falls back to the
+// inner repository.
+func (r *CampaignRepository) GetSettings(ctx context.Context, id uuid.UUID) (*domain.CampaignSettings, error) {
+	key := campaignSettingsKey(id)
+	if cached, err := r.client.Get(ctx, key).Bytes(); err == nil {
+		var record settingsRecord
+		if err := json.Unmarshal(cached, &record); err == nil {
+			return record.toDomain(id), nil
+		}
+	}
+
+	settings, err := r.CampaignRepository.GetSettings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.store(ctx, settings)
+	return settings, nil
+}
+
+// Update caches the campaign's settings as stored by the update, so workers pick up the new
+// limits with their next message. The update has already succeeded when the cache is written,
+// so a Redis failure is logged and the old entry lives out its TTL.
+func (r *CampaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
+	if err := r.CampaignRepository.Update(ctx, campaign); err != nil {
+		return err
+	}
+	r.refresh(ctx, campaign)
+	return nil
+}
+
+// Transition caches the campaign's settings once the transition is stored, as Update does.
+func (r *CampaignRepository) Transition(ctx context.Context, t repository.CampaignTransition) error {
+	if err := r.CampaignRepository.Transition(ctx, t); err != nil {
+		return err
+	}
+	r.refresh(ctx, t.Campaign)
+	return nil
+}
+
+func (r *CampaignRepository) refresh(ctx context.Context, campaign *domain.Campaign) {
+	settings := campaign.Settings()
+	if err := r.store(ctx, &settings); err != nil {
+		r.logger.Warn("campaign cache: refresh settings",
+			zap.Error(err),
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.Int64("config_version", campaign.ConfigVersion))
+	}
+}
+
+// store caches settings unless a newer version is already cached.
+func (r *CampaignRepository) store(ctx context.Context, settings *domain.CampaignSettings) error {
+	value, err := json.Marshal(newSettingsRecord(settings))
+	if err != nil {
+		return fmt.Errorf("campaign cache: marshal settings: %w", err)
+	}
+	key := campaignSettingsKey(settings.CampaignID)
+	if err := storeSettingsScript.Run(ctx, r.client, []string{key}, settings.Version, value, r.ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("campaign cache: store settings: %w", err)
+	}
+	return nil
+}
+
+func newSettingsRecord(s *domain.CampaignSettings) settingsRecord {
+	return settingsRecord{
+		Version:            s.Version,
+		MaxConcurrentCalls: s.MaxConcurrentCalls,
+		RetryMaxAttempts:   s.RetryPolicy.MaxAttempts,
+		RetryBaseDelayMs:   s.RetryPolicy.BaseDelay.Milliseconds(),
+		RetryMaxDelayMs:    s.RetryPolicy.MaxDelay.Milliseconds(),
+		RetryJitter:        s.RetryPolicy.Jitter,
+	}
+}
+
+func (r settingsRecord) toDomain(id uuid.UUID) *domain.CampaignSettings {
+	return &domain.CampaignSettings{
+		CampaignID:         id,
+		Version:            r.Version,
+		MaxConcurrentCalls: r.MaxConcurrentCalls,
+		RetryPolicy: domain.RetryPolicy{
+			MaxAttempts: r.RetryMaxAttempts,
+			BaseDelay:   time.Duration(r.RetryBaseDelayMs) * time.Millisecond,
+			MaxDelay:    time.Duration(r.RetryMaxDelayMs) * time.Millisecond,
+			Jitter:      r.RetryJitter,
+		},
+	}
+}
+
+func campaignSettingsKey(id uuid.UUID) string {
+	return campaignSettingsKeyPrefix + id.String()
+}
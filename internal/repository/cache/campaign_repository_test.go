@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+type fakeCampaignStore struct {
+	repository.CampaignRepository
+	campaigns map[uuid.UUID]domain.Campaign
+	reads     int
+}
+
+func (f *fakeCampaignStore) GetSettings(_ context.Context, id uuid.UUID) (*domain.CampaignSettings, error) {
+	f.reads++
+	campaign, ok := f.campaigns[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	settings := campaign.Settings()
+	return &settings, nil
+}
+
+func (f *fakeCampaignStore) Update(_ context.Context, campaign *domain.Campaign) error {
+	f.campaigns[campaign.ID] = *campaign
+	return nil
+}
+
+func testCampaignCache(t *testing.T) (*miniredis.Miniredis, *fakeCampaignStore, *CampaignRepository) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := &fakeCampaignStore{campaigns: make(map[uuid.UUID]domain.Campaign)}
+	lg := &logger.Logger{Logger: zap.NewNop()}
+	return server, store, NewCampaignRepository(store, client, time.Minute, lg)
+}
+
+func testCampaign(version int64, concurrency int) domain.Campaign {
+	return domain.Campaign{
+		ID:                 uuid.New(),
+		ConfigVersion:      version,
+		MaxConcurrentCalls: concurrency,
+		RetryPolicy: domain.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+			MaxDelay:    time.Minute,
+			Jitter:      0.2,
+		},
+	}
+}
+
+func TestCampaignCacheServesSettings(t *testing.T) {
+	ctx := context.Background()
+	server, store, repo := testCampaignCache(t)
+	campaign := testCampaign(1, 5)
+	store.campaigns[campaign.ID] = campaign
+
+	for i := 0; i < 2; i++ {
+		settings, err := repo.GetSettings(ctx, campaign.ID)
+		if err != nil {
+			t.Fatalf("GetSettings: %v", err)
+		}
+		if settings.MaxConcurrentCalls != 5 || settings.RetryPolicy != campaign.RetryPolicy {
+			t.Fatalf("GetSettings = %+v, want the stored settings", settings)
+		}
+	}
+	if store.reads != 1 {
+		t.Fatalf("inner reads = %d, want 1", store.reads)
+	}
+	if ttl := server.TTL(campaignSettingsKey(campaign.ID)); ttl != time.Minute {
+		t.Fatalf("cache TTL = %v, want 1m", ttl)
+	}
+}
+
+func TestCampaignCacheUpdateWritesThrough(t *testing.T) {
+	ctx := context.Background()
+	_, store, repo := testCampaignCache(t)
+	campaign := testCampaign(1, 5)
+	store.campaigns[campaign.ID] = campaign
+	if _, err := repo.GetSettings(ctx, campaign.ID); err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+
+	campaign.ConfigVersion, campaign.MaxConcurrentCalls = 2, 9
+	if err := repo.Update(ctx, &campaign); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	settings, err := repo.GetSettings(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if settings.Version != 2 || settings.MaxConcurrentCalls != 9 {
+		t.Fatalf("GetSettings after update = %+v, want version 2 with 9 calls", settings)
+	}
+	if store.reads != 1 {
+		t.Fatalf("inner reads = %d, want 1", store.reads)
+	}
+}
+
+func TestCampaignCacheKeepsNewerVersion(t *testing.T) {
+	ctx := context.Background()
+	_, store, repo := testCampaignCache(t)
+	stale := testCampaign(1, 5)
+	current := stale
+	current.ConfigVersion, current.MaxConcurrentCalls = 2, 9
+
+	// A reader loaded version 1 just before the update, but stores it after.
+	if err := repo.Update(ctx, &current); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	staleSettings := stale.Settings()
+	if err := repo.store(ctx, &staleSettings); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	settings, err := repo.GetSettings(ctx, current.ID)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if settings.Version != 2 || settings.MaxConcurrentCalls != 9 {
+		t.Fatalf("GetSettings = %+v, want version 2 kept over the stale write", settings)
+	}
+	if store.reads != 0 {
+		t.Fatalf("inner reads = %d, want 0", store.reads)
+	}
+}
+
+func TestCampaignCacheUpdateSurvivesRedisFailure(t *testing.T) {
+	ctx := context.Background()
+	server, store, repo := testCampaignCache(t)
+	campaign := testCampaign(1, 5)
+	server.Close()
+
+	if err := repo.Update(ctx, &campaign); err != nil {
+		t.Fatalf("Update with Redis down = %v, want nil", err)
+	}
+	if _, ok := store.campaigns[campaign.ID]; !ok {
+		t.Fatal("update not stored")
+	}
+	settings, err := repo.GetSettings(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("GetSettings with Redis down: %v", err)
+	}
+	if settings.MaxConcurrentCalls != 5 {
+		t.Fatalf("GetSettings = %+v, want the stored settings", settings)
+	}
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+const targetPhoneKeyPrefix = "campaign:target-phone:"
+
+// CampaignTargetRepository caches phone-to-target lookups in Redis in front of another
+// target repository. Every other method is passed straight through.
+type CampaignTargetRepository struct {
+	repository.CampaignTargetRepository
+	client *redis.Client
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewCampaignTargetRepository wraps inner with a lookup cache whose entries live for ttl.
+func NewCampaignTargetRepository(inner repository.CampaignTargetRepository, client *redis.Client, ttl time.Duration, lg *logger.Logger) *CampaignTargetRepository {
+	return &CampaignTargetRepository{CampaignTargetRepository: inner, client: client, ttl: ttl, logger: lg}
+}
+
+// FindIDByPhone serves the lookup from Redis when possible. Misses are not cached so numbers
+// added later are found straight away; a Redis failure falls back to the inner repository.
+func (r *CampaignTargetRepository) FindIDByPhone(ctx context.Context, campaignID uuid.UUID, phoneNumber string) (uuid.UUID, error) {
+	key := targetPhoneKey(campaignID, phoneNumber)
+	if cached, err := r.client.Get(ctx, key).Result(); err == nil {
+		if id, err := uuid.Parse(cached); err == nil {
+			return id, nil
+		}
+	}
+
+	id, err := r.CampaignTargetRepository.FindIDByPhone(ctx, campaignID, phoneNumber)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	_ = r.client.Set(ctx, key, id.String(), r.ttl).Err()
+	return id, nil
+}
+
+// Delete removes the target and then evicts its cached lookup. Evicting only after the row is
+// gone keeps a lookup racing the delete from caching the target again, and leaves the entry in
+// place when the delete is refused. A Redis failure is logged; the entry lives out its TTL.
+func (r *CampaignTargetRepository) Delete(ctx context.Context, campaignID, targetID uuid.UUID, fromStates []string) (bool, error) {
+	target, err := r.CampaignTargetRepository.Get(ctx, campaignID, targetID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return false, err
+	}
+	deleted, err := r.CampaignTargetRepository.Delete(ctx, campaignID, targetID, fromStates)
+	if err != nil || !deleted || target == nil {
+		return deleted, err
+	}
+	if err := r.client.Del(ctx, targetPhoneKey(campaignID, target.PhoneNumber)).Err(); err != nil {
+		r.logger.Warn("target cache: evict",
+			zap.Error(err),
+			zap.String("campaign_id", campaignID.String()),
+			zap.String("target_id", targetID.String()))
+	}
+	return true, nil
+}
+
+func targetPhoneKey(campaignID uuid.UUID, phoneNumber string) string {
+	return targetPhoneKeyPrefix + campaignID.String() + ":" + phoneNumber
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+type fakeTargetStore struct {
+	repository.CampaignTargetRepository
+	targets map[uuid.UUID]repository.CampaignTargetRecord
+	lookups int
+	// onDelete runs inside Delete before the row is removed.
+	onDelete func()
+}
+
+func (f *fakeTargetStore) Get(_ context.Context, _, targetID uuid.UUID) (*repository.CampaignTargetRecord, error) {
+	target, ok := f.targets[targetID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &target, nil
+}
+
+func (f *fakeTargetStore) FindIDByPhone(_ context.Context, campaignID uuid.UUID, phoneNumber string) (uuid.UUID, error) {
+	f.lookups++
+	for _, target := range f.targets {
+		if target.CampaignID == campaignID && target.PhoneNumber == phoneNumber {
+			return target.ID, nil
+		}
+	}
+	return uuid.Nil, repository.ErrNotFound
+}
+
+func (f *fakeTargetStore) Delete(_ context.Context, _, targetID uuid.UUID, fromStates []string) (bool, error) {
+	if f.onDelete != nil {
+		f.onDelete()
+	}
+	target, ok := f.targets[targetID]
+	if !ok {
+		return false, nil
+	}
+	for _, state := range fromStates {
+		if target.State == state {
+			delete(f.targets, targetID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func testTargetCache(t *testing.T) (*miniredis.Miniredis, *fakeTargetStore, *CampaignTargetRepository) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := &fakeTargetStore{targets: make(map[uuid.UUID]repository.CampaignTargetRecord)}
+	lg := &logger.Logger{Logger: zap.NewNop()}
+	return server, store, NewCampaignTargetRepository(store, client, time.Minute, lg)
+}
+
+func addTarget(store *fakeTargetStore, phoneNumber, state string) repository.CampaignTargetRecord {
+	target := repository.CampaignTargetRecord{ID: uuid.New(), CampaignID: uuid.New(), PhoneNumber: phoneNumber, State: state}
+	store.targets[target.ID] = target
+	return target
+}
+
+func TestTargetCacheDeleteEvictsAfterDelete(t *testing.T) {
+	ctx := context.Background()
+	server, store, repo := testTargetCache(t)
+	target := addTarget(store, "+14155550100", "pending")
+	key := targetPhoneKey(target.CampaignID, target.PhoneNumber)
+
+	// A lookup landing between the eviction and the delete must not leave the target cached.
+	store.onDelete = func() {
+		if _, err := repo.FindIDByPhone(ctx, target.CampaignID, target.PhoneNumber); err != nil {
+			t.Fatalf("FindIDByPhone during delete: %v", err)
+		}
+	}
+	deleted, err := repo.Delete(ctx, target.CampaignID, target.ID, []string{"pending"})
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true, nil", deleted, err)
+	}
+	if server.Exists(key) {
+		t.Fatal("lookup still cached after the target was deleted")
+	}
+	if _, err := repo.FindIDByPhone(ctx, target.CampaignID, target.PhoneNumber); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindIDByPhone after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTargetCacheRefusedDeleteKeepsEntry(t *testing.T) {
+	ctx := context.Background()
+	server, store, repo := testTargetCache(t)
+	target := addTarget(store, "+14155550101", "completed")
+	if _, err := repo.FindIDByPhone(ctx, target.CampaignID, target.PhoneNumber); err != nil {
+		t.Fatalf("FindIDByPhone: %v", err)
+	}
+
+	deleted, err := repo.Delete(ctx, target.CampaignID, target.ID, []string{"pending"})
+	if err != nil || deleted {
+		t.Fatalf("Delete = %v, %v; want false, nil", deleted, err)
+	}
+	if !server.Exists(targetPhoneKey(target.CampaignID, target.PhoneNumber)) {
+		t.Fatal("lookup evicted although the target was kept")
+	}
+	id, err := repo.FindIDByPhone(ctx, target.CampaignID, target.PhoneNumber)
+	if err != nil || id != target.ID {
+		t.Fatalf("FindIDByPhone = %v, %v; want %v", id, err, target.ID)
+	}
+	if store.lookups != 1 {
+		t.Fatalf("inner lookups = %d, want 1", store.lookups)
+	}
+}
+
+func TestTargetCacheDeleteSurvivesRedisFailure(t *testing.T) {
+	ctx := context.Background()
+	server, store, repo := testTargetCache(t)
+	target := addTarget(store, "+14155550102", "pending")
+	server.Close()
+
+	deleted, err := repo.Delete(ctx, target.CampaignID, target.ID, []string{"pending"})
+	if err != nil || !deleted {
+		t.Fatalf("Delete with Redis down = %v, %v; want true, nil", deleted, err)
+	}
+	if _, ok := store.targets[target.ID]; ok {
+		t.Fatal("target not deleted")
+	}
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "context"
+
+// Fence names a leader lease and the fencing token it was acquired under.
+type Fence struct {
+	Name  string
+	Token int64
+}
+
+type fenceKey struct{}
+
+// WithFence returns a context whose writes are rejected with ErrFenced once the fence has been
+// raised past fence.Token by a newer leader.
+func WithFence(ctx context.Context, fence Fence) context.Context {
+	return context.WithValue(ctx, fenceKey{}, fence)
+}
+
+// FenceFrom returns the fence carried by ctx, if any.
+func FenceFrom(ctx context.Context) (Fence, bool) {
+	fence, ok := ctx.Value(fenceKey{}).(Fence)
+	return fence, ok
+}
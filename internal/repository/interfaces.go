@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,31 +16,120 @@ var (
 	ErrNotFound = apperrors.ErrNotFound
 	// ErrConflict indicates a unique constraint violation.
 	ErrConflict = apperrors.ErrConflict
+	// ErrFenced indicates a write carried a fencing token that a newer scheduler leader has superseded.
+	ErrFenced = errors.New("repository: fencing token superseded")
 )
 
 // CampaignRepository manages campaign metadata persistence.
 type CampaignRepository interface {
 	Create(ctx context.Context, campaign *domain.Campaign) error
 	Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error)
+	// Update stores the campaign's metadata and settings. Its status, start and completion are
+	// only changed by Transition.
 	Update(ctx context.Context, campaign *domain.Campaign) error
+	// Transition stores a campaign whose status has changed and moves its targets, atomically.
+	// It returns ErrConflict when the stored status is no longer the transition's From.
+	Transition(ctx context.Context, t CampaignTransition) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.CampaignStatus) error
 	List(ctx context.Context, afterID *uuid.UUID, limit int) ([]*domain.Campaign, error)
 	ListByStatus(ctx context.Context, status domain.CampaignStatus, limit int) ([]*domain.Campaign, error)
+	ListDueToStart(ctx context.Context, now time.Time, limit int) ([]*domain.Campaign, error)
+	ListPastEnd(ctx context.Context, now time.Time, limit int) ([]*domain.Campaign, error)
+	// GetSettings returns the limits dispatch workers apply to the campaign's calls.
+	GetSettings(ctx context.Context, id uuid.UUID) (*domain.CampaignSettings, error)
 }
 
 // BusinessHourRepository manages campaign business hours.
 type BusinessHourRepository interface {
 	Replace(ctx context.Context, campaignID uuid.UUID, windows []domain.BusinessHourWindow) error
 	List(ctx context.Context, campaignID uuid.UUID) ([]domain.BusinessHourWindow, error)
+	// ReplaceExceptions replaces the campaign's blackout dates and override windows.
+	ReplaceExceptions(ctx context.Context, campaignID uuid.UUID, blackouts []domain.BlackoutDate, overrides []domain.OverrideWindow) error
+	ListExceptions(ctx context.Context, campaignID uuid.UUID) ([]domain.BlackoutDate, []domain.OverrideWindow, error)
+	// ListByCampaigns and ListExceptionsByCampaigns load the schedules of several campaigns at
+	// once, keyed by campaign id; campaigns without any are absent.
+	ListByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]domain.BusinessHourWindow, error)
+	ListExceptionsByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID]DateExceptions, error)
+}
+
+// DateExceptions are a campaign's blackout dates and override windows.
+type DateExceptions struct {
+	Blackouts []domain.BlackoutDate
+	Overrides []domain.OverrideWindow
+}
+
+// HolidayCalendarRepository stores shared holiday calendars and the campaigns linked to them.
+type HolidayCalendarRepository interface {
+	Create(ctx context.Context, calendar *domain.HolidayCalendar) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.HolidayCalendar, error)
+	List(ctx context.Context) ([]domain.HolidayCalendar, error)
+	Update(ctx context.Context, calendar *domain.HolidayCalendar) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ReplaceHolidays(ctx context.Context, id uuid.UUID, holidays []domain.Holiday) error
+	// MergeHolidays upserts holidays by date and returns how many dates were added.
+	MergeHolidays(ctx context.Context, id uuid.UUID, holidays []domain.Holiday) (int64, error)
+	SetCampaignCalendars(ctx context.Context, campaignID uuid.UUID, calendarIDs []uuid.UUID) error
+	ListCampaignCalendars(ctx context.Context, campaignID uuid.UUID) ([]uuid.UUID, error)
+	// ListCampaignHolidays returns the holidays of every calendar linked to the campaign.
+	ListCampaignHolidays(ctx context.Context, campaignID uuid.UUID) ([]domain.Holiday, error)
+	// ListCalendarsByCampaigns and ListHolidaysByCampaigns do the same for several campaigns at
+	// once, keyed by campaign id; campaigns without any are absent.
+	ListCalendarsByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	ListHolidaysByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]domain.Holiday, error)
 }
 
 // CampaignTargetRepository stores campaign call targets.
 type CampaignTargetRepository interface {
-	BulkInsert(ctx context.Context, campaignID uuid.UUID, targets []CampaignTargetRecord) error
-	NextBatchForScheduling(ctx context.Context, campaignID uuid.UUID, limit int) ([]CampaignTargetRecord, error)
-	MarkScheduled(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, scheduledAt time.Time) error
+	BulkInsert(ctx context.Context, campaignID uuid.UUID, targets []CampaignTargetRecord, policy domain.DuplicatePolicy) (TargetInsertResult, error)
+	// ClaimBatch atomically takes up to limit targets for scheduling on behalf of owner; the claim
+	// lapses after ttl unless a call is attached to the target first. A non-empty zones restricts
+	// the claim to targets in those time zones ("" being targets whose zone is unknown).
+	ClaimBatch(ctx context.Context, campaignID uuid.UUID, owner string, limit int, ttl time.Duration, zones []string) ([]CampaignTargetRecord, error)
 	SetState(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, state string) error
+	SetStateWithReason(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, state, reason string) error
+	AttachCall(ctx context.Context, campaignID, targetID, callID uuid.UUID, claimOwner string) error
+	ApplyCallUpdate(ctx context.Context, update TargetCallUpdate) (bool, error)
 	ListByCampaign(ctx context.Context, campaignID uuid.UUID, limit int, state string) ([]CampaignTargetRecord, error)
+	// Stage pre-assigns future release times to pending targets ahead of a business window.
+	Stage(ctx context.Context, campaignID uuid.UUID, limit int, releaseAt time.Time, spacing time.Duration) (int64, error)
+	// ListStale returns targets of any campaign left in one of states since before olderThan, oldest first.
+	ListStale(ctx context.Context, states []string, olderThan time.Time, limit int) ([]CampaignTargetRecord, error)
+	// Requeue puts queued targets back to pending, detaching any call and claim, and records reason.
+	Requeue(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, reason string) (int64, error)
+	// Defer moves queued targets to deferred until the given time, detaching their claim, and records reason.
+	Defer(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, until time.Time, reason string) (int64, error)
+	Query(ctx context.Context, filter TargetFilter, after *TargetCursor, limit int) ([]CampaignTargetRecord, error)
+	Get(ctx context.Context, campaignID, targetID uuid.UUID) (*CampaignTargetRecord, error)
+	// FindIDByPhone returns the id of the campaign target with the given normalised number, or ErrNotFound.
+	FindIDByPhone(ctx context.Context, campaignID uuid.UUID, phoneNumber string) (uuid.UUID, error)
+	// UpdatePayload and Delete apply only while the target is in one of fromStates and report whether it was.
+	UpdatePayload(ctx context.Context, campaignID, targetID uuid.UUID, payload map[string]any, timeZone string, fromStates []string) (bool, error)
+	Delete(ctx context.Context, campaignID, targetID uuid.UUID, fromStates []string) (bool, error)
+	CountByState(ctx context.Context, campaignID uuid.UUID) (map[string]int64, error)
+	CountInState(ctx context.Context, campaignID uuid.UUID, state string) (int64, error)
+	// ListTimeZones returns the distinct time zones of the campaign's targets in one of states.
+	ListTimeZones(ctx context.Context, campaignID uuid.UUID, states []string) ([]string, error)
+}
+
+// TargetInsertResult reports what BulkInsert did. Existing lists the numbers already in the
+// campaign that were left untouched.
+type TargetInsertResult struct {
+	Inserted int
+	Reset    int
+	Existing []string
+}
+
+// TargetFilter narrows target listings. PhoneNumber matches any part of the stored number.
+type TargetFilter struct {
+	CampaignID  uuid.UUID
+	State       string
+	PhoneNumber string
+}
+
+// TargetCursor is the keyset position of the last target of a page.
+type TargetCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
 }
 
 // CampaignStatisticsRepository keeps aggregate counters.
@@ -49,6 +139,30 @@ type CampaignStatisticsRepository interface {
 	ApplyDelta(ctx context.Context, campaignID uuid.UUID, delta StatsDelta) error
 }
 
+// CampaignEventRepository stores the campaign audit trail.
+type CampaignEventRepository interface {
+	Append(ctx context.Context, event *domain.CampaignEvent) error
+	List(ctx context.Context, campaignID uuid.UUID, beforeID *int64, limit int) ([]domain.CampaignEvent, error)
+}
+
+// SuppressionRepository stores do-not-call entries, global and per campaign.
+type SuppressionRepository interface {
+	Add(ctx context.Context, entry *domain.SuppressedNumber) error
+	BulkAdd(ctx context.Context, entries []domain.SuppressedNumber) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, filter SuppressionFilter, afterID *int64, limit int) ([]domain.SuppressedNumber, error)
+	// Match returns the suppression reason for each of phones that is suppressed globally or for the campaign.
+	Match(ctx context.Context, campaignID uuid.UUID, phones []string) (map[string]string, error)
+}
+
+// SuppressionFilter narrows suppression list queries. A nil CampaignID with GlobalOnly
+// false lists every entry.
+type SuppressionFilter struct {
+	CampaignID  *uuid.UUID
+	GlobalOnly  bool
+	PhoneNumber string
+}
+
 // CallStore persists call execution data.
 type CallStore interface {
 	CreateCall(ctx context.Context, record *domain.Call) error
@@ -58,19 +172,93 @@ type CallStore interface {
 	AppendAttempt(ctx context.Context, attempt domain.CallAttempt) error
 }
 
-// CampaignTargetRecord is the storage representation of a campaign target.
+// OutboxRepository records Kafka messages in the same transaction as the Postgres changes they
+// announce, and hands them to the relay that publishes them.
+type OutboxRepository interface {
+	// EnqueueCall links the call to its target, applies the stats delta and records the dispatch
+	// message atomically.
+	EnqueueCall(ctx context.Context, enqueue CallEnqueue) error
+	// Claim takes up to limit unsent messages, oldest first, for owner until ttl passes. Messages
+	// already claimed maxAttempts times are left in place and no longer claimed.
+	Claim(ctx context.Context, owner string, limit, maxAttempts int, ttl time.Duration) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	// Release returns claimed messages that could not be published, recording why.
+	Release(ctx context.Context, ids []int64, reason string) error
+	// PurgeSent deletes messages sent before the given time and reports how many were removed.
+	PurgeSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+// FenceRepository records the fencing token of the current scheduler leader.
+type FenceRepository interface {
+	// Advance raises the named fence to token for holder. It returns ErrFenced when the fence
+	// already stands at a newer token.
+	Advance(ctx context.Context, name, holder string, token int64) error
+}
+
+// PhoneNumberRepository reads and rewrites stored phone numbers for the E.164 backfill.
+type PhoneNumberRepository interface {
+	// TargetNumbers pages campaign targets by id together with their campaign's default country.
+	TargetNumbers(ctx context.Context, afterID uuid.UUID, limit int) ([]StoredTargetNumber, error)
+	// RenumberTarget stores number on the target. It returns ErrConflict when another target of
+	// the same campaign already has it.
+	RenumberTarget(ctx context.Context, id uuid.UUID, number string) error
+	// FlagTarget suppresses a target that has not been dialled yet, recording reason. It reports
+	// false when the target has already left the claimable states.
+	FlagTarget(ctx context.Context, id uuid.UUID, reason string) (bool, error)
+	// SuppressedNumbers pages suppression entries by id together with their campaign's default country.
+	SuppressedNumbers(ctx context.Context, afterID int64, limit int) ([]StoredSuppressedNumber, error)
+	// RenumberSuppressed stores number on the entry. It returns ErrConflict when the number is
+	// already suppressed in the same scope.
+	RenumberSuppressed(ctx context.Context, id int64, number string) error
+}
+
+// CampaignTargetRecord is the storage representation of a campaign target. TimeZone is the
+// recipient's IANA zone, empty when unknown.
 type CampaignTargetRecord struct {
 	ID           uuid.UUID
 	CampaignID   uuid.UUID
 	PhoneNumber  string
 	Payload      map[string]any
+	TimeZone     string
 	State        string
+	StateReason  string
+	CallID       *uuid.UUID
 	ScheduledAt  *time.Time
 	LastAttempt  *time.Time
 	AttemptCount int
 	CreatedAt    time.Time
 }
 
+// TargetCallUpdate moves a target according to a call status report. The target is matched by
+// TargetID, or by CallID when TargetID is nil. When OnlyFrom is set the update applies only to
+// targets currently in one of those states.
+type TargetCallUpdate struct {
+	CampaignID uuid.UUID
+	TargetID   uuid.UUID
+	CallID     uuid.UUID
+	State      string
+	Attempt    int
+	OccurredAt time.Time
+	Dialed     bool
+	// Reason is recorded as the target's state reason; empty clears it.
+	Reason string
+	// Detach unlinks the call so the target can be claimed for a new one.
+	Detach bool
+	// ScheduledAt, when set, is when a deferred target may be claimed again.
+	ScheduledAt *time.Time
+	OnlyFrom    []string
+}
+
+// CampaignTransition is a campaign status change together with the targets it moves. From is the
+// status the change was decided on and is required. Targets in one of TargetsFrom move to
+// TargetsTo; none move when TargetsFrom is empty.
+type CampaignTransition struct {
+	Campaign    *domain.Campaign
+	From        domain.CampaignStatus
+	TargetsFrom []string
+	TargetsTo   string
+}
+
 // StatsDelta captures atomic counter increments.
 type StatsDelta struct {
 	TotalCallsDelta      int64
@@ -80,3 +268,45 @@ type StatsDelta struct {
 	PendingCallsDelta    int64
 	RetriesDelta         int64
 }
+
+// OutboxMessage is a Kafka message waiting in the outbox.
+type OutboxMessage struct {
+	ID        int64
+	Topic     string
+	Key       []byte
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// CallEnqueue is the Postgres side of creating a call: the target it is attached to, the stats
+// change and the dispatch message to publish.
+type CallEnqueue struct {
+	CampaignID uuid.UUID
+	TargetID   uuid.UUID
+	// ClaimOwner is the scheduler replica whose claim on the target is settled; empty when the
+	// target was not claimed.
+	ClaimOwner string
+	CallID     uuid.UUID
+	Delta      StatsDelta
+	Message    OutboxMessage
+}
+
+// StoredTargetNumber is a campaign target's phone number as stored, with the region used to read
+// national-format numbers.
+type StoredTargetNumber struct {
+	ID          uuid.UUID
+	CampaignID  uuid.UUID
+	PhoneNumber string
+	State       string
+	Region      string
+}
+
+// StoredSuppressedNumber is a suppression entry's phone number as stored. Region is empty for
+// global entries.
+type StoredSuppressedNumber struct {
+	ID          int64
+	CampaignID  *uuid.UUID
+	PhoneNumber string
+	Region      string
+}
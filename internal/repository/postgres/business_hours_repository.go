@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
 // BusinessHourRepository persists campaign business hours.
@@ -51,24 +53,37 @@ func (r *BusinessHourRepository) Replace(ctx context.Context, campaignID uuid.UU
 
 // List retrieves business hours for a campaign.
 func (r *BusinessHourRepository) List(ctx context.Context, campaignID uuid.UUID) ([]domain.BusinessHourWindow, error) {
-	rows, err := r.db.QueryxContext(ctx, `SELECT day_of_week, start_minute, end_minute FROM campaign_business_hours WHERE campaign_id = $1 ORDER BY day_of_week, start_minute`, campaignID)
+	windows, err := r.ListByCampaigns(ctx, []uuid.UUID{campaignID})
+	if err != nil {
+		return nil, err
+	}
+	return windows[campaignID], nil
+}
+
+// ListByCampaigns retrieves the business hours of several campaigns in one query.
+func (r *BusinessHourRepository) ListByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]domain.BusinessHourWindow, error) {
+	windows := make(map[uuid.UUID][]domain.BusinessHourWindow)
+	if len(campaignIDs) == 0 {
+		return windows, nil
+	}
+	rows, err := r.db.QueryxContext(ctx, `SELECT campaign_id, day_of_week, start_minute, end_minute FROM campaign_business_hours WHERE campaign_id = ANY($1) ORDER BY campaign_id, day_of_week, start_minute`, campaignIDs)
 	if err != nil {
 		return nil, fmt.Errorf("business hours: query: %w", err)
 	}
 	defer rows.Close()
 
-	var windows []domain.BusinessHourWindow
 	for rows.Next() {
 		var row struct {
-			Day      int `db:"day_of_week"`
-			StartMin int `db:"start_minute"`
-			EndMin   int `db:"end_minute"`
+			CampaignID uuid.UUID `db:"campaign_id"`
+			Day        int       `db:"day_of_week"`
+			StartMin   int       `db:"start_minute"`
+			EndMin     int       `db:"end_minute"`
 		}
 		if err := rows.StructScan(&row); err != nil {
 			return nil, fmt.Errorf("business hours: scan: %w", err)
 		}
 
-		windows = append(windows, domain.BusinessHourWindow{
+		windows[row.CampaignID] = append(windows[row.CampaignID], domain.BusinessHourWindow{
 			DayOfWeek: time.Weekday(row.Day),
 			Start:     minuteToTime(row.StartMin),
 			End:       minuteToTime(row.EndMin),
@@ -82,6 +97,94 @@ func (r *BusinessHourRepository) List(ctx context.Context, campaignID uuid.UUID)
 	return windows, nil
 }
 
+// ReplaceExceptions replaces the campaign's blackout dates and override windows.
+func (r *BusinessHourRepository) ReplaceExceptions(ctx context.Context, campaignID uuid.UUID, blackouts []domain.BlackoutDate, overrides []domain.OverrideWindow) error {
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_date_exceptions WHERE campaign_id = $1`, campaignID); err != nil {
+			return fmt.Errorf("business hours: delete exceptions: %w", err)
+		}
+
+		if len(blackouts) == 0 && len(overrides) == 0 {
+			return nil
+		}
+
+		stmt, err := tx.PreparexContext(ctx, `INSERT INTO campaign_date_exceptions (campaign_id, exception_date, start_minute, end_minute, reason) VALUES ($1, $2, $3, $4, $5)`)
+		if err != nil {
+			return fmt.Errorf("business hours: prepare exception insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, b := range blackouts {
+			if _, err := stmt.ExecContext(ctx, campaignID, domain.DateKey(b.Date), nil, nil, b.Reason); err != nil {
+				return fmt.Errorf("business hours: insert blackout: %w", err)
+			}
+		}
+		for _, o := range overrides {
+			start := o.Start.Hour()*60 + o.Start.Minute()
+			end := o.End.Hour()*60 + o.End.Minute()
+			if _, err := stmt.ExecContext(ctx, campaignID, domain.DateKey(o.Date), start, end, ""); err != nil {
+				return fmt.Errorf("business hours: insert override: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListExceptions retrieves the campaign's blackout dates and override windows in date order.
+func (r *BusinessHourRepository) ListExceptions(ctx context.Context, campaignID uuid.UUID) ([]domain.BlackoutDate, []domain.OverrideWindow, error) {
+	exceptions, err := r.ListExceptionsByCampaigns(ctx, []uuid.UUID{campaignID})
+	if err != nil {
+		return nil, nil, err
+	}
+	return exceptions[campaignID].Blackouts, exceptions[campaignID].Overrides, nil
+}
+
+// ListExceptionsByCampaigns retrieves the date exceptions of several campaigns in one query, each
+// in date order.
+func (r *BusinessHourRepository) ListExceptionsByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID]repository.DateExceptions, error) {
+	exceptions := make(map[uuid.UUID]repository.DateExceptions)
+	if len(campaignIDs) == 0 {
+		return exceptions, nil
+	}
+	rows, err := r.db.QueryxContext(ctx, `SELECT campaign_id, exception_date, start_minute, end_minute, reason FROM campaign_date_exceptions WHERE campaign_id = ANY($1) ORDER BY campaign_id, exception_date, start_minute NULLS FIRST`, campaignIDs)
+	if err != nil {
+		return nil, fmt.Errorf("business hours: query exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			CampaignID uuid.UUID     `db:"campaign_id"`
+			Date       time.Time     `db:"exception_date"`
+			StartMin   sql.NullInt64 `db:"start_minute"`
+			EndMin     sql.NullInt64 `db:"end_minute"`
+			Reason     string        `db:"reason"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, fmt.Errorf("business hours: scan exception: %w", err)
+		}
+
+		date := dateOnly(row.Date)
+		entry := exceptions[row.CampaignID]
+		if !row.StartMin.Valid {
+			entry.Blackouts = append(entry.Blackouts, domain.BlackoutDate{Date: date, Reason: row.Reason})
+		} else {
+			entry.Overrides = append(entry.Overrides, domain.OverrideWindow{
+				Date:  date,
+				Start: minuteToTime(int(row.StartMin.Int64)),
+				End:   minuteToTime(int(row.EndMin.Int64)),
+			})
+		}
+		exceptions[row.CampaignID] = entry
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("business hours: exception rows err: %w", err)
+	}
+
+	return exceptions, nil
+}
+
 func minuteToTime(min int) time.Time {
 	hour := min / 60
 	minute := min % 60
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+func TestBusinessHoursStoreOvernightWindows(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	hours := NewBusinessHourRepository(db)
+	campaignID := insertCampaign(t, db)
+
+	overnight := domain.BusinessHourWindow{
+		DayOfWeek: time.Friday,
+		Start:     time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC),
+		End:       time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC),
+	}
+	if err := hours.Replace(ctx, campaignID, []domain.BusinessHourWindow{overnight}); err != nil {
+		t.Fatalf("replace with overnight window: %v", err)
+	}
+	windows, err := hours.List(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(windows) != 1 || windows[0].Start.Hour() != 22 || windows[0].End.Hour() != 6 {
+		t.Fatalf("windows = %+v, want the 22:00-06:00 window", windows)
+	}
+
+	empty := overnight
+	empty.End = empty.Start
+	if err := hours.Replace(ctx, campaignID, []domain.BusinessHourWindow{empty}); err == nil {
+		t.Fatal("replace with an empty window succeeded, want the check to reject it")
+	}
+}
@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// HolidayCalendarRepository implements repository.HolidayCalendarRepository.
+type HolidayCalendarRepository struct {
+	db *sqlx.DB
+}
+
+// NewHolidayCalendarRepository builds the repository.
+func NewHolidayCalendarRepository(db *sqlx.DB) *HolidayCalendarRepository {
+	return &HolidayCalendarRepository{db: db}
+}
+
+// Create inserts a calendar with its holidays, returning ErrConflict if the name is taken.
+func (r *HolidayCalendarRepository) Create(ctx context.Context, calendar *domain.HolidayCalendar) error {
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		row := tx.QueryRowxContext(ctx, `INSERT INTO holiday_calendars (id, name, description)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO NOTHING
+			RETURNING created_at, updated_at`,
+			calendar.ID, calendar.Name, calendar.Description)
+		if err := row.Scan(&calendar.CreatedAt, &calendar.UpdatedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: holiday calendar %q already exists", repository.ErrConflict, calendar.Name)
+			}
+			return fmt.Errorf("holiday calendars: insert: %w", err)
+		}
+		_, err := upsertHolidays(ctx, tx, calendar.ID, calendar.Holidays)
+		return err
+	})
+}
+
+// Get returns a calendar with its holidays in date order.
+func (r *HolidayCalendarRepository) Get(ctx context.Context, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	var rec calendarRecord
+	err := r.db.GetContext(ctx, &rec, `SELECT id, name, description, created_at, updated_at FROM holiday_calendars WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("holiday calendars: get: %w", err)
+	}
+
+	holidays, err := r.queryHolidays(ctx, `SELECT holiday_date, name FROM holiday_calendar_dates WHERE calendar_id = $1 ORDER BY holiday_date`, id)
+	if err != nil {
+		return nil, err
+	}
+	calendar := rec.toDomain()
+	calendar.Holidays = holidays
+	return &calendar, nil
+}
+
+// List returns every calendar by name, without holidays.
+func (r *HolidayCalendarRepository) List(ctx context.Context) ([]domain.HolidayCalendar, error) {
+	var recs []calendarRecord
+	if err := r.db.SelectContext(ctx, &recs, `SELECT id, name, description, created_at, updated_at FROM holiday_calendars ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("holiday calendars: list: %w", err)
+	}
+	calendars := make([]domain.HolidayCalendar, 0, len(recs))
+	for _, rec := range recs {
+		calendars = append(calendars, rec.toDomain())
+	}
+	return calendars, nil
+}
+
+// Update renames or redescribes a calendar, returning ErrConflict if another calendar has the name.
+func (r *HolidayCalendarRepository) Update(ctx context.Context, calendar *domain.HolidayCalendar) error {
+	row := r.db.QueryRowxContext(ctx, `UPDATE holiday_calendars SET name = $2, description = $3
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM holiday_calendars WHERE name = $2 AND id <> $1)
+		RETURNING updated_at`,
+		calendar.ID, calendar.Name, calendar.Description)
+	if err := row.Scan(&calendar.UpdatedAt); err != nil {
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("holiday calendars: update: %w", err)
+		}
+		var exists bool
+		if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM holiday_calendars WHERE id = $1)`, calendar.ID); err != nil {
+			return fmt.Errorf("holiday calendars: update: %w", err)
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("%w: holiday calendar %q already exists", repository.ErrConflict, calendar.Name)
+	}
+	return nil
+}
+
+// Delete removes a calendar; campaigns using it stop observing its holidays.
+func (r *HolidayCalendarRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM holiday_calendars WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("holiday calendars: delete: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("holiday calendars: rows affected: %w", err)
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceHolidays swaps the calendar's holidays for the given list.
+func (r *HolidayCalendarRepository) ReplaceHolidays(ctx context.Context, id uuid.UUID, holidays []domain.Holiday) error {
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM holiday_calendar_dates WHERE calendar_id = $1`, id); err != nil {
+			return fmt.Errorf("holiday calendars: delete holidays: %w", err)
+		}
+		_, err := upsertHolidays(ctx, tx, id, holidays)
+		return err
+	})
+}
+
+// MergeHolidays adds holidays to the calendar, renaming dates already present, and returns how
+// many dates were new.
+func (r *HolidayCalendarRepository) MergeHolidays(ctx context.Context, id uuid.UUID, holidays []domain.Holiday) (int64, error) {
+	var added int64
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var err error
+		added, err = upsertHolidays(ctx, tx, id, holidays)
+		return err
+	})
+	return added, err
+}
+
+// SetCampaignCalendars replaces the calendars a campaign observes.
+func (r *HolidayCalendarRepository) SetCampaignCalendars(ctx context.Context, campaignID uuid.UUID, calendarIDs []uuid.UUID) error {
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_holiday_calendars WHERE campaign_id = $1`, campaignID); err != nil {
+			return fmt.Errorf("holiday calendars: unlink campaign: %w", err)
+		}
+		for _, id := range calendarIDs {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO campaign_holiday_calendars (campaign_id, calendar_id) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING`, campaignID, id); err != nil {
+				return fmt.Errorf("holiday calendars: link campaign: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListCampaignCalendars returns the ids of the calendars a campaign observes.
+func (r *HolidayCalendarRepository) ListCampaignCalendars(ctx context.Context, campaignID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, `SELECT calendar_id FROM campaign_holiday_calendars WHERE campaign_id = $1 ORDER BY calendar_id`, campaignID); err != nil {
+		return nil, fmt.Errorf("holiday calendars: list campaign calendars: %w", err)
+	}
+	return ids, nil
+}
+
+// ListCalendarsByCampaigns returns the ids of the calendars each of several campaigns observes.
+func (r *HolidayCalendarRepository) ListCalendarsByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	calendars := make(map[uuid.UUID][]uuid.UUID)
+	if len(campaignIDs) == 0 {
+		return calendars, nil
+	}
+	var rows []struct {
+		CampaignID uuid.UUID `db:"campaign_id"`
+		CalendarID uuid.UUID `db:"calendar_id"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, `SELECT campaign_id, calendar_id FROM campaign_holiday_calendars WHERE campaign_id = ANY($1) ORDER BY campaign_id, calendar_id`, campaignIDs); err != nil {
+		return nil, fmt.Errorf("holiday calendars: list campaign calendars: %w", err)
+	}
+	for _, row := range rows {
+		calendars[row.CampaignID] = append(calendars[row.CampaignID], row.CalendarID)
+	}
+	return calendars, nil
+}
+
+// ListCampaignHolidays returns the holidays of every calendar the campaign observes.
+func (r *HolidayCalendarRepository) ListCampaignHolidays(ctx context.Context, campaignID uuid.UUID) ([]domain.Holiday, error) {
+	return r.queryHolidays(ctx, `SELECT d.holiday_date, d.name
+		FROM holiday_calendar_dates d
+		JOIN campaign_holiday_calendars c ON c.calendar_id = d.calendar_id
+		WHERE c.campaign_id = $1
+		ORDER BY d.holiday_date`, campaignID)
+}
+
+// ListHolidaysByCampaigns returns the holidays of every calendar each of several campaigns
+// observes, in date order.
+func (r *HolidayCalendarRepository) ListHolidaysByCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID][]domain.Holiday, error) {
+	holidays := make(map[uuid.UUID][]domain.Holiday)
+	if len(campaignIDs) == 0 {
+		return holidays, nil
+	}
+	rows, err := r.db.QueryxContext(ctx, `SELECT c.campaign_id, d.holiday_date, d.name
+		FROM holiday_calendar_dates d
+		JOIN campaign_holiday_calendars c ON c.calendar_id = d.calendar_id
+		WHERE c.campaign_id = ANY($1)
+		ORDER BY c.campaign_id, d.holiday_date`, campaignIDs)
+	if err != nil {
+		return nil, fmt.Errorf("holiday calendars: query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			campaignID uuid.UUID
+			date       time.Time
+			name       string
+		)
+		if err := rows.Scan(&campaignID, &date, &name); err != nil {
+			return nil, fmt.Errorf("holiday calendars: scan holiday: %w", err)
+		}
+		holidays[campaignID] = append(holidays[campaignID], domain.Holiday{Date: dateOnly(date), Name: name})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("holiday calendars: rows err: %w", err)
+	}
+	return holidays, nil
+}
+
+func (r *HolidayCalendarRepository) queryHolidays(ctx context.Context, query string, arg any) ([]domain.Holiday, error) {
+	rows, err := r.db.QueryxContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("holiday calendars: query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	var holidays []domain.Holiday
+	for rows.Next() {
+		var (
+			date time.Time
+			name string
+		)
+		if err := rows.Scan(&date, &name); err != nil {
+			return nil, fmt.Errorf("holiday calendars: scan holiday: %w", err)
+		}
+		holidays = append(holidays, domain.Holiday{Date: dateOnly(date), Name: name})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("holiday calendars: rows err: %w", err)
+	}
+	return holidays, nil
+}
+
+func upsertHolidays(ctx context.Context, tx *sqlx.Tx, calendarID uuid.UUID, holidays []domain.Holiday) (int64, error) {
+	if len(holidays) == 0 {
+		return 0, nil
+	}
+	stmt, err := tx.PreparexContext(ctx, `INSERT INTO holiday_calendar_dates (calendar_id, holiday_date, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (calendar_id, holiday_date) DO UPDATE SET name = EXCLUDED.name
+		RETURNING (xmax = 0)`)
+	if err != nil {
+		return 0, fmt.Errorf("holiday calendars: prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	var added int64
+	for _, h := range holidays {
+		var inserted bool
+		if err := stmt.QueryRowxContext(ctx, calendarID, domain.DateKey(h.Date), h.Name).Scan(&inserted); err != nil {
+			return 0, fmt.Errorf("holiday calendars: insert holiday: %w", err)
+		}
+		if inserted {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// dateOnly normalises a DATE column to midnight UTC regardless of the driver's location.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type calendarRecord struct {
+	ID          uuid.UUID `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r calendarRecord) toDomain() domain.HolidayCalendar {
+	return domain.HolidayCalendar{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
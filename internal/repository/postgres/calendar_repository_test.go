@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+func TestCampaignScheduleRowsGoWithTheCampaign(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	calendars := NewHolidayCalendarRepository(db)
+	hours := NewBusinessHourRepository(db)
+
+	calendar := &domain.HolidayCalendar{ID: uuid.New(), Name: "holidays-" + uuid.NewString()}
+	if err := calendars.Create(ctx, calendar); err != nil {
+		t.Fatalf("create calendar: %v", err)
+	}
+	if err := calendars.SetCampaignCalendars(ctx, uuid.New(), []uuid.UUID{calendar.ID}); err == nil {
+		t.Fatal("linked a calendar to a campaign that does not exist")
+	}
+
+	campaignID := insertCampaign(t, db)
+	if err := calendars.SetCampaignCalendars(ctx, campaignID, []uuid.UUID{calendar.ID}); err != nil {
+		t.Fatalf("link calendar: %v", err)
+	}
+	blackout := domain.BlackoutDate{Date: time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), Reason: "office closed"}
+	if err := hours.ReplaceExceptions(ctx, campaignID, []domain.BlackoutDate{blackout}, nil); err != nil {
+		t.Fatalf("store exceptions: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM campaigns WHERE id = $1`, campaignID); err != nil {
+		t.Fatalf("delete campaign: %v", err)
+	}
+	var links, exceptions int
+	if err := db.GetContext(ctx, &links, `SELECT COUNT(*) FROM campaign_holiday_calendars WHERE campaign_id = $1`, campaignID); err != nil {
+		t.Fatalf("count calendar links: %v", err)
+	}
+	if err := db.GetContext(ctx, &exceptions, `SELECT COUNT(*) FROM campaign_date_exceptions WHERE campaign_id = $1`, campaignID); err != nil {
+		t.Fatalf("count date exceptions: %v", err)
+	}
+	if links != 0 || exceptions != 0 {
+		t.Fatalf("%d calendar links and %d date exceptions left after the campaign was deleted, want none", links, exceptions)
+	}
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,6 +14,12 @@ import (
 	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
+const campaignColumns = `id, name, description, time_zone, recipient_local_time, default_country, max_concurrent_calls, priority, weight, status,
+	retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter, config_version,
+	start_at, end_at, created_at, updated_at, started_at, completed_at, completion_summary`
+
+const campaignSettingsColumns = `id, max_concurrent_calls, retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter, config_version`
+
 // CampaignRepository implements repository.CampaignRepository using PostgreSQL.
 type CampaignRepository struct {
 	db *sqlx.DB
@@ -25,14 +32,17 @@ func NewCampaignRepository(db *sqlx.DB) *CampaignRepository {
 
 // Create inserts a new campaign.
 func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	if campaign.ConfigVersion <= 0 {
+		campaign.ConfigVersion = 1
+	}
 	q := `INSERT INTO campaigns (
-		id, name, description, time_zone, max_concurrent_calls, status,
-		retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter,
-		created_at, updated_at, started_at, completed_at
+		id, name, description, time_zone, recipient_local_time, default_country, max_concurrent_calls, priority, weight, status,
+		retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter, config_version,
+		start_at, end_at, created_at, updated_at, started_at, completed_at
 	) VALUES (
-		:id, :name, :description, :time_zone, :max_concurrent_calls, :status,
-		:retry_max_attempts, :retry_base_delay_ms, :retry_max_delay_ms, :retry_jitter,
-		:created_at, :updated_at, :started_at, :completed_at
+		:id, :name, :description, :time_zone, :recipient_local_time, :default_country, :max_concurrent_calls, :priority, :weight, :status,
+		:retry_max_attempts, :retry_base_delay_ms, :retry_max_delay_ms, :retry_jitter, :config_version,
+		:start_at, :end_at, :created_at, :updated_at, :started_at, :completed_at
 	)`
 
 	params := map[string]any{
@@ -40,12 +50,19 @@ func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campai
 		"name":                 campaign.Name,
 		"description":          campaign.Description,
 		"time_zone":            campaign.TimeZone,
+		"recipient_local_time": campaign.RecipientLocalTime,
+		"default_country":      campaign.DefaultCountry,
 		"max_concurrent_calls": campaign.MaxConcurrentCalls,
+		"priority":             campaign.Priority,
+		"weight":               campaign.Weight,
 		"status":               campaign.Status,
 		"retry_max_attempts":   campaign.RetryPolicy.MaxAttempts,
 		"retry_base_delay_ms":  campaign.RetryPolicy.BaseDelay.Milliseconds(),
 		"retry_max_delay_ms":   campaign.RetryPolicy.MaxDelay.Milliseconds(),
 		"retry_jitter":         campaign.RetryPolicy.Jitter,
+		"config_version":       campaign.ConfigVersion,
+		"start_at":             campaign.StartAt,
+		"end_at":               campaign.EndAt,
 		"created_at":           campaign.CreatedAt,
 		"updated_at":           campaign.UpdatedAt,
 		"started_at":           campaign.StartedAt,
@@ -61,10 +78,7 @@ func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campai
 
 // Get fetches a campaign by id.
 func (r *CampaignRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
-	q := `SELECT id, name, description, time_zone, max_concurrent_calls, status,
-	       retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter,
-	       created_at, updated_at, started_at, completed_at
-	  FROM campaigns WHERE id = $1`
+	q := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
 
 	row := r.db.QueryRowxContext(ctx, q, id)
 	var record campaignRecord
@@ -79,50 +93,123 @@ func (r *CampaignRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Cam
 	return &campaign, nil
 }
 
-// Update updates campaign metadata.
+// Update updates campaign metadata, bumping the config version when the concurrency limit or
+// retry policy changes. The campaign's ConfigVersion is refreshed from the stored row. The status
+// and its timestamps are left alone; they change only through Transition.
 func (r *CampaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
 	q := `UPDATE campaigns SET
 		name = :name,
 		description = :description,
-		status = :status,
 		time_zone = :time_zone,
+		recipient_local_time = :recipient_local_time,
+		default_country = :default_country,
 		max_concurrent_calls = :max_concurrent_calls,
+		priority = :priority,
+		weight = :weight,
 		retry_max_attempts = :retry_max_attempts,
 		retry_base_delay_ms = :retry_base_delay_ms,
 		retry_max_delay_ms = :retry_max_delay_ms,
 		retry_jitter = :retry_jitter,
-		started_at = :started_at,
-		completed_at = :completed_at
-	 WHERE id = :id`
+		config_version = config_version + CASE
+			WHEN (max_concurrent_calls, retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter)
+				IS DISTINCT FROM (CAST(:max_concurrent_calls AS INTEGER), CAST(:retry_max_attempts AS INTEGER),
+					CAST(:retry_base_delay_ms AS BIGINT), CAST(:retry_max_delay_ms AS BIGINT), CAST(:retry_jitter AS DOUBLE PRECISION))
+			THEN 1 ELSE 0 END,
+		start_at = :start_at,
+		end_at = :end_at
+	 WHERE id = :id
+	 RETURNING config_version`
 
 	params := map[string]any{
 		"id":                   campaign.ID,
 		"name":                 campaign.Name,
 		"description":          campaign.Description,
-		"status":               campaign.Status,
 		"time_zone":            campaign.TimeZone,
+		"recipient_local_time": campaign.RecipientLocalTime,
+		"default_country":      campaign.DefaultCountry,
 		"max_concurrent_calls": campaign.MaxConcurrentCalls,
+		"priority":             campaign.Priority,
+		"weight":               campaign.Weight,
 		"retry_max_attempts":   campaign.RetryPolicy.MaxAttempts,
 		"retry_base_delay_ms":  campaign.RetryPolicy.BaseDelay.Milliseconds(),
 		"retry_max_delay_ms":   campaign.RetryPolicy.MaxDelay.Milliseconds(),
 		"retry_jitter":         campaign.RetryPolicy.Jitter,
-		"started_at":           campaign.StartedAt,
-		"completed_at":         campaign.CompletedAt,
+		"start_at":             campaign.StartAt,
+		"end_at":               campaign.EndAt,
 	}
 
-	res, err := r.db.NamedExecContext(ctx, q, params)
+	query, args, err := r.db.BindNamed(q, params)
 	if err != nil {
+		return fmt.Errorf("campaign repo: bind update: %w", err)
+	}
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&campaign.ConfigVersion); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
 		return fmt.Errorf("campaign repo: update: %w", err)
 	}
+	return nil
+}
 
-	n, err := res.RowsAffected()
+// Transition stores a status change and moves the campaign's targets in the same transaction.
+// Only the status, its timestamps and the completion summary are written; the campaign's
+// settings are refreshed from the stored row so a concurrent Update is not undone.
+func (r *CampaignRepository) Transition(ctx context.Context, t repository.CampaignTransition) error {
+	if t.From == "" {
+		return fmt.Errorf("campaign repo: transition to %s without a from status", t.Campaign.Status)
+	}
+	summary, err := marshalCompletionSummary(t.Campaign.CompletionSummary)
 	if err != nil {
-		return fmt.Errorf("campaign repo: rows affected: %w", err)
+		return err
 	}
-	if n == 0 {
-		return repository.ErrNotFound
+
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var record campaignRecord
+		err := tx.QueryRowxContext(ctx, `UPDATE campaigns SET
+			status = $1,
+			started_at = $2,
+			completed_at = $3,
+			completion_summary = $4
+		 WHERE id = $5 AND status = $6
+		 RETURNING `+campaignSettingsColumns,
+			t.Campaign.Status, t.Campaign.StartedAt, t.Campaign.CompletedAt, summary, t.Campaign.ID, t.From).StructScan(&record)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: campaign is no longer %s", repository.ErrConflict, t.From)
+			}
+			return fmt.Errorf("campaign repo: transition: %w", err)
+		}
+		stored := record.toDomain()
+		t.Campaign.MaxConcurrentCalls = stored.MaxConcurrentCalls
+		t.Campaign.RetryPolicy = stored.RetryPolicy
+		t.Campaign.ConfigVersion = stored.ConfigVersion
+
+		if len(t.TargetsFrom) == 0 {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE campaign_targets SET state = $1 WHERE campaign_id = $2 AND state = ANY($3)`,
+			t.TargetsTo, t.Campaign.ID, t.TargetsFrom); err != nil {
+			return fmt.Errorf("campaign repo: transition targets: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSettings fetches the campaign's current dispatch settings.
+func (r *CampaignRepository) GetSettings(ctx context.Context, id uuid.UUID) (*domain.CampaignSettings, error) {
+	q := `SELECT ` + campaignSettingsColumns + ` FROM campaigns WHERE id = $1`
+
+	var record campaignRecord
+	if err := r.db.QueryRowxContext(ctx, q, id).StructScan(&record); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("campaign repo: get settings: %w", err)
 	}
-	return nil
+
+	campaign := record.toDomain()
+	settings := campaign.Settings()
+	return &settings, nil
 }
 
 // UpdateStatus updates campaign status.
@@ -149,14 +236,10 @@ func (r *CampaignRepository) List(ctx context.Context, afterID *uuid.UUID, limit
 	var rows *sqlx.Rows
 	var err error
 	if afterID != nil {
-		rows, err = r.db.QueryxContext(ctx, `SELECT id, name, description, time_zone, max_concurrent_calls, status,
-			retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter,
-			created_at, updated_at, started_at, completed_at
+		rows, err = r.db.QueryxContext(ctx, `SELECT `+campaignColumns+`
 		FROM campaigns WHERE id > $1 ORDER BY id ASC LIMIT $2`, *afterID, limit)
 	} else {
-		rows, err = r.db.QueryxContext(ctx, `SELECT id, name, description, time_zone, max_concurrent_calls, status,
-			retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter,
-			created_at, updated_at, started_at, completed_at
+		rows, err = r.db.QueryxContext(ctx, `SELECT `+campaignColumns+`
 		FROM campaigns ORDER BY id ASC LIMIT $1`, limit)
 	}
 	if err != nil {
@@ -181,19 +264,54 @@ func (r *CampaignRepository) List(ctx context.Context, afterID *uuid.UUID, limit
 	return results, nil
 }
 
-// ListByStatus returns campaigns filtered by status.
+// ListByStatus returns campaigns filtered by status, highest priority first.
 func (r *CampaignRepository) ListByStatus(ctx context.Context, status domain.CampaignStatus, limit int) ([]*domain.Campaign, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 
-	rows, err := r.db.QueryxContext(ctx, `SELECT id, name, description, time_zone, max_concurrent_calls, status,
-		retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter,
-		created_at, updated_at, started_at, completed_at
-		FROM campaigns WHERE status = $1 ORDER BY updated_at ASC LIMIT $2`, status, limit)
+	rows, err := r.db.QueryxContext(ctx, `SELECT `+campaignColumns+`
+		FROM campaigns WHERE status = $1 ORDER BY priority DESC, updated_at ASC LIMIT $2`, status, limit)
 	if err != nil {
 		return nil, fmt.Errorf("campaign repo: list by status: %w", err)
 	}
+	return scanCampaigns(rows)
+}
+
+// ListDueToStart returns pending campaigns whose scheduled start has passed and whose end has not.
+func (r *CampaignRepository) ListDueToStart(ctx context.Context, now time.Time, limit int) ([]*domain.Campaign, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := r.db.QueryxContext(ctx, `SELECT `+campaignColumns+`
+		FROM campaigns
+		WHERE status = $1 AND start_at IS NOT NULL AND start_at <= $2 AND (end_at IS NULL OR end_at > $2)
+		ORDER BY start_at ASC LIMIT $3`, domain.CampaignStatusPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign repo: list due to start: %w", err)
+	}
+	return scanCampaigns(rows)
+}
+
+// ListPastEnd returns pending, running or paused campaigns whose scheduled end has passed.
+func (r *CampaignRepository) ListPastEnd(ctx context.Context, now time.Time, limit int) ([]*domain.Campaign, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := r.db.QueryxContext(ctx, `SELECT `+campaignColumns+`
+		FROM campaigns
+		WHERE status = ANY($1) AND end_at IS NOT NULL AND end_at <= $2
+		ORDER BY end_at ASC LIMIT $3`,
+		[]string{string(domain.CampaignStatusPending), string(domain.CampaignStatusInProgress), string(domain.CampaignStatusPaused)}, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign repo: list past end: %w", err)
+	}
+	return scanCampaigns(rows)
+}
+
+func scanCampaigns(rows *sqlx.Rows) ([]*domain.Campaign, error) {
 	defer rows.Close()
 
 	var results []*domain.Campaign
@@ -218,16 +336,24 @@ type campaignRecord struct {
 	Name               string         `db:"name"`
 	Description        sql.NullString `db:"description"`
 	TimeZone           string         `db:"time_zone"`
+	RecipientLocalTime bool           `db:"recipient_local_time"`
+	DefaultCountry     string         `db:"default_country"`
 	MaxConcurrentCalls int            `db:"max_concurrent_calls"`
+	Priority           int            `db:"priority"`
+	Weight             int            `db:"weight"`
 	Status             string         `db:"status"`
 	RetryMaxAttempts   int            `db:"retry_max_attempts"`
 	RetryBaseDelayMs   int64          `db:"retry_base_delay_ms"`
 	RetryMaxDelayMs    int64          `db:"retry_max_delay_ms"`
 	RetryJitter        float64        `db:"retry_jitter"`
+	ConfigVersion      int64          `db:"config_version"`
+	StartAt            sql.NullTime   `db:"start_at"`
+	EndAt              sql.NullTime   `db:"end_at"`
 	CreatedAt          sql.NullTime   `db:"created_at"`
 	UpdatedAt          sql.NullTime   `db:"updated_at"`
 	StartedAt          sql.NullTime   `db:"started_at"`
 	CompletedAt        sql.NullTime   `db:"completed_at"`
+	CompletionSummary  []byte         `db:"completion_summary"`
 }
 
 func (r campaignRecord) toDomain() domain.Campaign {
@@ -236,7 +362,11 @@ func (r campaignRecord) toDomain() domain.Campaign {
 		Name:               r.Name,
 		Description:        r.Description.String,
 		TimeZone:           r.TimeZone,
+		RecipientLocalTime: r.RecipientLocalTime,
+		DefaultCountry:     r.DefaultCountry,
 		MaxConcurrentCalls: r.MaxConcurrentCalls,
+		Priority:           r.Priority,
+		Weight:             r.Weight,
 		Status:             domain.CampaignStatus(r.Status),
 		RetryPolicy: domain.RetryPolicy{
 			MaxAttempts: r.RetryMaxAttempts,
@@ -244,7 +374,43 @@ func (r campaignRecord) toDomain() domain.Campaign {
 			MaxDelay:    time.Duration(r.RetryMaxDelayMs) * time.Millisecond,
 			Jitter:      r.RetryJitter,
 		},
+		ConfigVersion: r.ConfigVersion,
+		CreatedAt:     r.CreatedAt.Time,
+		UpdatedAt:     r.UpdatedAt.Time,
+	}
+	if r.StartAt.Valid {
+		t := r.StartAt.Time
+		campaign.StartAt = &t
+	}
+	if r.EndAt.Valid {
+		t := r.EndAt.Time
+		campaign.EndAt = &t
+	}
+	if r.StartedAt.Valid {
+		t := r.StartedAt.Time
+		campaign.StartedAt = &t
+	}
+	if r.CompletedAt.Valid {
+		t := r.CompletedAt.Time
+		campaign.CompletedAt = &t
+	}
+	if len(r.CompletionSummary) > 0 {
+		var summary domain.CompletionSummary
+		if err := json.Unmarshal(r.CompletionSummary, &summary); err == nil {
+			campaign.CompletionSummary = &summary
+		}
 	}
 
 	return campaign
-}
\ No newline at end of file
+}
+
+func marshalCompletionSummary(summary *domain.CompletionSummary) ([]byte, error) {
+	if summary == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return nil, fmt.Errorf("campaign repo: marshal completion summary: %w", err)
+	}
+	return data, nil
+}
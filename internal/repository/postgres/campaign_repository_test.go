@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+func TestCampaignUpdateLeavesStatusToTransition(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	campaigns := NewCampaignRepository(db)
+	campaign, err := campaigns.Get(ctx, insertCampaign(t, db))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	stale := *campaign
+	campaign.Status = domain.CampaignStatusCancelled
+	if err := campaigns.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: domain.CampaignStatusInProgress}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	// A change decided on the stale in-progress read neither reopens nor pauses the campaign.
+	stale.Name = "renamed"
+	if err := campaigns.Update(ctx, &stale); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stale.Status = domain.CampaignStatusPaused
+	if err := campaigns.Transition(ctx, repository.CampaignTransition{Campaign: &stale, From: domain.CampaignStatusInProgress}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("pause after cancel = %v, want ErrConflict", err)
+	}
+
+	stored, err := campaigns.Get(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.Name != "renamed" || stored.Status != domain.CampaignStatusCancelled {
+		t.Fatalf("stored campaign = %q %s, want renamed and cancelled", stored.Name, stored.Status)
+	}
+}
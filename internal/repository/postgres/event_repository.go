@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+// CampaignEventRepository implements repository.CampaignEventRepository.
+type CampaignEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewCampaignEventRepository builds the repository.
+func NewCampaignEventRepository(db *sqlx.DB) *CampaignEventRepository {
+	return &CampaignEventRepository{db: db}
+}
+
+type eventPayload struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Append records an event and populates its id and creation time.
+func (r *CampaignEventRepository) Append(ctx context.Context, event *domain.CampaignEvent) error {
+	payload, err := json.Marshal(eventPayload{Before: event.Before, After: event.After})
+	if err != nil {
+		return fmt.Errorf("campaign events: marshal payload: %w", err)
+	}
+
+	row := r.db.QueryRowxContext(ctx, `INSERT INTO campaign_events (campaign_id, event_type, actor, payload)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		event.CampaignID, string(event.Type), event.Actor, payload)
+	if err := row.Scan(&event.ID, &event.CreatedAt); err != nil {
+		return fmt.Errorf("campaign events: insert: %w", err)
+	}
+	return nil
+}
+
+// List returns events for a campaign newest first, starting below beforeID when provided.
+func (r *CampaignEventRepository) List(ctx context.Context, campaignID uuid.UUID, beforeID *int64, limit int) ([]domain.CampaignEvent, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	query := `SELECT id, campaign_id, event_type, actor, payload, created_at FROM campaign_events WHERE campaign_id = $1`
+	args := []any{campaignID}
+	if beforeID != nil {
+		query += ` AND id < $2 ORDER BY id DESC LIMIT $3`
+		args = append(args, *beforeID, limit)
+	} else {
+		query += ` ORDER BY id DESC LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("campaign events: list: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.CampaignEvent
+	for rows.Next() {
+		var rec struct {
+			ID         int64     `db:"id"`
+			CampaignID uuid.UUID `db:"campaign_id"`
+			EventType  string    `db:"event_type"`
+			Actor      string    `db:"actor"`
+			Payload    []byte    `db:"payload"`
+			CreatedAt  time.Time `db:"created_at"`
+		}
+		if err := rows.StructScan(&rec); err != nil {
+			return nil, fmt.Errorf("campaign events: scan: %w", err)
+		}
+
+		var payload eventPayload
+		if len(rec.Payload) > 0 {
+			if err := json.Unmarshal(rec.Payload, &payload); err != nil {
+				return nil, fmt.Errorf("campaign events: unmarshal payload: %w", err)
+			}
+		}
+
+		events = append(events, domain.CampaignEvent{
+			ID:         rec.ID,
+			CampaignID: rec.CampaignID,
+			Type:       domain.CampaignEventType(rec.EventType),
+			Actor:      rec.Actor,
+			Before:     payload.Before,
+			After:      payload.After,
+			CreatedAt:  rec.CreatedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("campaign events: rows err: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// FenceRepository implements repository.FenceRepository using PostgreSQL.
+type FenceRepository struct {
+	db *sqlx.DB
+}
+
+// NewFenceRepository constructs a new repository.
+func NewFenceRepository(db *sqlx.DB) *FenceRepository {
+	return &FenceRepository{db: db}
+}
+
+// Advance raises the named fence to token. Raising it to the token it already has is a no-op.
+func (r *FenceRepository) Advance(ctx context.Context, name, holder string, token int64) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO scheduler_fences (name, token, holder) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET token = EXCLUDED.token, holder = EXCLUDED.holder, updated_at = NOW()
+		WHERE scheduler_fences.token <= EXCLUDED.token`, name, token, holder)
+	if err != nil {
+		return fmt.Errorf("scheduler fence: advance: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("scheduler fence: rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s is past token %d", repository.ErrFenced, name, token)
+	}
+	return nil
+}
+
+// checkFence fails with repository.ErrFenced when ctx carries a fence that has since been
+// raised. The share lock holds off a new leader's Advance until the caller's transaction ends,
+// so nothing the caller writes in it can land after the fence moved.
+func checkFence(ctx context.Context, tx *sqlx.Tx) error {
+	fence, ok := repository.FenceFrom(ctx)
+	if !ok {
+		return nil
+	}
+	var token int64
+	err := tx.QueryRowxContext(ctx, `SELECT token FROM scheduler_fences WHERE name = $1 FOR SHARE`, fence.Name).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s has not been raised", repository.ErrFenced, fence.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("scheduler fence: check: %w", err)
+	}
+	if token != fence.Token {
+		return fmt.Errorf("%w: %s moved from token %d to %d", repository.ErrFenced, fence.Name, fence.Token, token)
+	}
+	return nil
+}
+
+// fenced runs fn on db, or in a transaction behind checkFence when ctx carries a fence.
+func fenced(ctx context.Context, db *sqlx.DB, fn func(sqlx.ExtContext) error) error {
+	if _, ok := repository.FenceFrom(ctx); !ok {
+		return fn(db)
+	}
+	return withTx(ctx, db, func(tx *sqlx.Tx) error {
+		return fn(tx)
+	})
+}
+
+// execFenced executes a single statement through fenced.
+func execFenced(ctx context.Context, db *sqlx.DB, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := fenced(ctx, db, func(exec sqlx.ExtContext) error {
+		var err error
+		res, err = exec.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
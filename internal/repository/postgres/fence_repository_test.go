@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+func TestFenceRejectsSupersededWrites(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	fences := NewFenceRepository(db)
+	targets := NewCampaignTargetRepository(db)
+
+	if err := fences.Advance(ctx, "lease", "a", 1); err != nil {
+		t.Fatalf("advance to 1: %v", err)
+	}
+	if err := fences.Advance(ctx, "lease", "a", 1); err != nil {
+		t.Fatalf("advance to the current token again: %v", err)
+	}
+
+	campaignID := uuid.New()
+	oldLeader := repository.WithFence(ctx, repository.Fence{Name: "lease", Token: 1})
+	if _, err := targets.ClaimBatch(oldLeader, campaignID, "a", 10, time.Minute, nil); err != nil {
+		t.Fatalf("claim under the current token: %v", err)
+	}
+
+	if err := fences.Advance(ctx, "lease", "b", 2); err != nil {
+		t.Fatalf("advance to 2: %v", err)
+	}
+	if err := fences.Advance(ctx, "lease", "a", 1); !errors.Is(err, repository.ErrFenced) {
+		t.Fatalf("advance back to 1 = %v, want ErrFenced", err)
+	}
+
+	if _, err := targets.ClaimBatch(oldLeader, campaignID, "a", 10, time.Minute, nil); !errors.Is(err, repository.ErrFenced) {
+		t.Errorf("ClaimBatch with a superseded token = %v, want ErrFenced", err)
+	}
+	if _, err := targets.Stage(oldLeader, campaignID, 10, time.Now(), time.Second); !errors.Is(err, repository.ErrFenced) {
+		t.Errorf("Stage with a superseded token = %v, want ErrFenced", err)
+	}
+	if _, err := targets.Defer(oldLeader, campaignID, []uuid.UUID{uuid.New()}, time.Now(), "test"); !errors.Is(err, repository.ErrFenced) {
+		t.Errorf("Defer with a superseded token = %v, want ErrFenced", err)
+	}
+	if _, err := targets.Requeue(oldLeader, campaignID, []uuid.UUID{uuid.New()}, "test"); !errors.Is(err, repository.ErrFenced) {
+		t.Errorf("Requeue with a superseded token = %v, want ErrFenced", err)
+	}
+
+	// Writes without a fence are not affected.
+	if _, err := targets.ClaimBatch(ctx, campaignID, "a", 10, time.Minute, nil); err != nil {
+		t.Errorf("unfenced claim: %v", err)
+	}
+}
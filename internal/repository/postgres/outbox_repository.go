@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// OutboxRepository implements repository.OutboxRepository using PostgreSQL.
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewOutboxRepository constructs a new repository.
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// EnqueueCall attaches the call to its target, applies the stats delta and inserts the dispatch
+// message in one transaction, so either all of them happen or none does. A target that cannot
+// take the call fails it with ErrConflict.
+func (r *OutboxRepository) EnqueueCall(ctx context.Context, enqueue repository.CallEnqueue) error {
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if err := attachCall(ctx, tx, enqueue.CampaignID, enqueue.TargetID, enqueue.CallID, enqueue.ClaimOwner); err != nil {
+			return err
+		}
+		if err := applyStatsDelta(ctx, tx, enqueue.CampaignID, enqueue.Delta); err != nil {
+			return err
+		}
+		msg := enqueue.Message
+		if _, err := tx.ExecContext(ctx, `INSERT INTO outbox (topic, message_key, payload) VALUES ($1, $2, $3)`,
+			msg.Topic, msg.Key, msg.Payload); err != nil {
+			return fmt.Errorf("outbox: insert: %w", err)
+		}
+		return nil
+	})
+}
+
+// Claim takes up to limit unsent messages for owner. Messages whose previous claim expired are
+// claimable again, which is what makes delivery at-least-once; those claimed maxAttempts times
+// are not, so a message Kafka keeps rejecting stops being retried. SKIP LOCKED lets several
+// relays claim disjoint batches.
+func (r *OutboxRepository) Claim(ctx context.Context, owner string, limit, maxAttempts int, ttl time.Duration) ([]repository.OutboxMessage, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	now := time.Now().UTC()
+	rows, err := r.db.QueryxContext(ctx, `UPDATE outbox o SET
+			claim_owner = $2,
+			claim_expires_at = $3,
+			attempts = o.attempts + 1
+		FROM (
+			SELECT id FROM outbox
+			WHERE sent_at IS NULL AND (claim_expires_at IS NULL OR claim_expires_at < $1) AND attempts < $5
+			ORDER BY id ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		) claimable
+		WHERE o.id = claimable.id
+		RETURNING o.id, o.topic, o.message_key, o.payload, o.attempts, o.created_at`,
+		now, owner, now.Add(ttl), limit, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: claim: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []repository.OutboxMessage
+	for rows.Next() {
+		var msg repository.OutboxMessage
+		if err := rows.Scan(&msg.ID, &msg.Topic, &msg.Key, &msg.Payload, &msg.Attempts, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("outbox: scan: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("outbox: rows err: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkSent records the messages as published.
+func (r *OutboxRepository) MarkSent(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox SET sent_at = NOW(), claim_owner = NULL, claim_expires_at = NULL, last_error = NULL
+		WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return fmt.Errorf("outbox: mark sent: %w", err)
+	}
+	return nil
+}
+
+// Release drops the claim on unsent messages so the next poll publishes them again.
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox SET claim_owner = NULL, claim_expires_at = NULL, last_error = NULLIF($2, '')
+		WHERE id = ANY($1) AND sent_at IS NULL`, ids, reason)
+	if err != nil {
+		return fmt.Errorf("outbox: release: %w", err)
+	}
+	return nil
+}
+
+// PurgeSent deletes messages sent before the given time.
+func (r *OutboxRepository) PurgeSent(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < $1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("outbox: purge: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("outbox: rows affected: %w", err)
+	}
+	return n, nil
+}
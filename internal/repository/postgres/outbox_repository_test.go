@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOutboxClaimMarkSentRelease(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	outbox := NewOutboxRepository(db)
+
+	for i := 0; i < 3; i++ {
+		if _, err := db.ExecContext(ctx, `INSERT INTO outbox (topic, message_key, payload) VALUES ('calls', $1, '{}')`, []byte{byte(i)}); err != nil {
+			t.Fatalf("insert message: %v", err)
+		}
+	}
+
+	first, err := outbox.Claim(ctx, "a", 2, 5, time.Minute)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if len(first) != 2 || first[0].ID >= first[1].ID || first[0].Attempts != 1 {
+		t.Fatalf("first claim = %+v, want the two oldest messages at attempt 1", first)
+	}
+
+	// Claimed messages are skipped until their claim expires.
+	second, err := outbox.Claim(ctx, "b", 10, 5, time.Minute)
+	if err != nil {
+		t.Fatalf("second claim: %v", err)
+	}
+	if len(second) != 1 {
+		t.Fatalf("second claim took %d messages, want 1", len(second))
+	}
+
+	if err := outbox.MarkSent(ctx, []int64{first[0].ID}); err != nil {
+		t.Fatalf("mark sent: %v", err)
+	}
+	if err := outbox.Release(ctx, []int64{first[1].ID}, "broker down"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	// Releasing a sent message leaves it sent.
+	if err := outbox.Release(ctx, []int64{first[0].ID}, "late"); err != nil {
+		t.Fatalf("release sent: %v", err)
+	}
+
+	third, err := outbox.Claim(ctx, "b", 10, 5, time.Minute)
+	if err != nil {
+		t.Fatalf("third claim: %v", err)
+	}
+	if len(third) != 1 || third[0].ID != first[1].ID || third[0].Attempts != 2 {
+		t.Fatalf("third claim = %+v, want the released message at attempt 2", third)
+	}
+	var lastError string
+	if err := db.GetContext(ctx, &lastError, `SELECT last_error FROM outbox WHERE id = $1`, first[1].ID); err != nil {
+		t.Fatalf("read last_error: %v", err)
+	}
+	if lastError != "broker down" {
+		t.Errorf("last_error = %q, want broker down", lastError)
+	}
+}
+
+func TestOutboxClaimReclaimsExpiredAndStopsAtMaxAttempts(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	outbox := NewOutboxRepository(db)
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO outbox (topic, payload) VALUES ('calls', '{}')`); err != nil {
+		t.Fatalf("insert message: %v", err)
+	}
+
+	// A negative TTL leaves the claim already expired, as if the relay had died holding it.
+	for attempt := 1; attempt <= 2; attempt++ {
+		claimed, err := outbox.Claim(ctx, "a", 10, 2, -time.Second)
+		if err != nil {
+			t.Fatalf("claim %d: %v", attempt, err)
+		}
+		if len(claimed) != 1 || claimed[0].Attempts != attempt {
+			t.Fatalf("claim %d = %+v, want the message at attempt %d", attempt, claimed, attempt)
+		}
+	}
+
+	claimed, err := outbox.Claim(ctx, "a", 10, 2, -time.Second)
+	if err != nil {
+		t.Fatalf("claim after max attempts: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Fatalf("claimed %+v after max attempts, want nothing", claimed)
+	}
+}
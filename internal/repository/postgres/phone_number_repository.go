@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// unsentTargetStates are the states a target can be flagged from: no call has been placed for it.
+var unsentTargetStates = []string{domain.TargetStatePending, domain.TargetStateStaged, domain.TargetStateDeferred}
+
+// PhoneNumberRepository implements repository.PhoneNumberRepository.
+type PhoneNumberRepository struct {
+	db *sqlx.DB
+}
+
+// NewPhoneNumberRepository builds the repository.
+func NewPhoneNumberRepository(db *sqlx.DB) *PhoneNumberRepository {
+	return &PhoneNumberRepository{db: db}
+}
+
+// TargetNumbers pages campaign targets by id.
+func (r *PhoneNumberRepository) TargetNumbers(ctx context.Context, afterID uuid.UUID, limit int) ([]repository.StoredTargetNumber, error) {
+	var rows []struct {
+		ID          uuid.UUID `db:"id"`
+		CampaignID  uuid.UUID `db:"campaign_id"`
+		PhoneNumber string    `db:"phone_number"`
+		State       string    `db:"state"`
+		Region      string    `db:"default_country"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `SELECT t.id, t.campaign_id, t.phone_number, t.state, c.default_country
+		FROM campaign_targets t
+		JOIN campaigns c ON c.id = t.campaign_id
+		WHERE t.id > $1
+		ORDER BY t.id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("phone numbers: list targets: %w", err)
+	}
+
+	out := make([]repository.StoredTargetNumber, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, repository.StoredTargetNumber(row))
+	}
+	return out, nil
+}
+
+// RenumberTarget stores number unless another target of the campaign already has it.
+func (r *PhoneNumberRepository) RenumberTarget(ctx context.Context, id uuid.UUID, number string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE campaign_targets t SET phone_number = $2, updated_at = NOW()
+		WHERE t.id = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM campaign_targets o
+			WHERE o.campaign_id = t.campaign_id AND o.phone_number = $2 AND o.id <> t.id
+		  )`, id, number)
+	if err != nil {
+		return fmt.Errorf("phone numbers: renumber target: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("phone numbers: rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: campaign already lists %s", repository.ErrConflict, number)
+	}
+	return nil
+}
+
+// FlagTarget suppresses the target if no call has been placed for it.
+func (r *PhoneNumberRepository) FlagTarget(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE campaign_targets SET state = $2, state_reason = $3, updated_at = NOW()
+		WHERE id = $1 AND state = ANY($4)`, id, domain.TargetStateSuppressed, reason, unsentTargetStates)
+	if err != nil {
+		return false, fmt.Errorf("phone numbers: flag target: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("phone numbers: rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SuppressedNumbers pages suppression entries by id.
+func (r *PhoneNumberRepository) SuppressedNumbers(ctx context.Context, afterID int64, limit int) ([]repository.StoredSuppressedNumber, error) {
+	var rows []struct {
+		ID          int64      `db:"id"`
+		CampaignID  *uuid.UUID `db:"campaign_id"`
+		PhoneNumber string     `db:"phone_number"`
+		Region      string     `db:"default_country"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `SELECT s.id, s.campaign_id, s.phone_number, COALESCE(c.default_country, '') AS default_country
+		FROM suppressed_numbers s
+		LEFT JOIN campaigns c ON c.id = s.campaign_id
+		WHERE s.id > $1
+		ORDER BY s.id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("phone numbers: list suppressed: %w", err)
+	}
+
+	out := make([]repository.StoredSuppressedNumber, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, repository.StoredSuppressedNumber(row))
+	}
+	return out, nil
+}
+
+// RenumberSuppressed stores number unless it is already suppressed in the entry's scope.
+func (r *PhoneNumberRepository) RenumberSuppressed(ctx context.Context, id int64, number string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE suppressed_numbers s SET phone_number = $2
+		WHERE s.id = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM suppressed_numbers o
+			WHERE o.campaign_id IS NOT DISTINCT FROM s.campaign_id AND o.phone_number = $2 AND o.id <> s.id
+		  )`, id, number)
+	if err != nil {
+		return fmt.Errorf("phone numbers: renumber suppressed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("phone numbers: rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s is already suppressed", repository.ErrConflict, number)
+	}
+	return nil
+}
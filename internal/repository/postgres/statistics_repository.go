@@ -49,7 +49,11 @@ func (r *CampaignStatisticsRepository) Get(ctx context.Context, campaignID uuid.
 
 // ApplyDelta applies counter deltas atomically.
 func (r *CampaignStatisticsRepository) ApplyDelta(ctx context.Context, campaignID uuid.UUID, delta repository.StatsDelta) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE campaign_statistics SET
+	return applyStatsDelta(ctx, r.db, campaignID, delta)
+}
+
+func applyStatsDelta(ctx context.Context, exec sqlx.ExecerContext, campaignID uuid.UUID, delta repository.StatsDelta) error {
+	_, err := exec.ExecContext(ctx, `UPDATE campaign_statistics SET
 		total_calls = total_calls + $2,
 		completed_calls = completed_calls + $3,
 		failed_calls = failed_calls + $4,
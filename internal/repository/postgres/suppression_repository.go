@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// SuppressionRepository implements repository.SuppressionRepository.
+type SuppressionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSuppressionRepository builds the repository.
+func NewSuppressionRepository(db *sqlx.DB) *SuppressionRepository {
+	return &SuppressionRepository{db: db}
+}
+
+// Add inserts a single entry, returning ErrConflict if the number is already suppressed in that scope.
+func (r *SuppressionRepository) Add(ctx context.Context, entry *domain.SuppressedNumber) error {
+	row := r.db.QueryRowxContext(ctx, `INSERT INTO suppressed_numbers (campaign_id, phone_number, reason, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at`,
+		entry.CampaignID, entry.PhoneNumber, entry.Reason, entry.CreatedBy)
+	if err := row.Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s is already suppressed", repository.ErrConflict, entry.PhoneNumber)
+		}
+		return fmt.Errorf("suppression: insert: %w", err)
+	}
+	return nil
+}
+
+// BulkAdd inserts entries, skipping numbers already suppressed in the same scope, and returns the number inserted.
+func (r *SuppressionRepository) BulkAdd(ctx context.Context, entries []domain.SuppressedNumber) (int64, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	var inserted int64
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		stmt, err := tx.PreparexContext(ctx, `INSERT INTO suppressed_numbers (campaign_id, phone_number, reason, created_by)
+			VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`)
+		if err != nil {
+			return fmt.Errorf("suppression: prepare insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, e := range entries {
+			res, err := stmt.ExecContext(ctx, e.CampaignID, e.PhoneNumber, e.Reason, e.CreatedBy)
+			if err != nil {
+				return fmt.Errorf("suppression: insert: %w", err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("suppression: rows affected: %w", err)
+			}
+			inserted += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// Delete removes an entry by id.
+func (r *SuppressionRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM suppressed_numbers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("suppression: delete: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("suppression: rows affected: %w", err)
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// List returns entries ordered by id, starting after afterID when provided.
+func (r *SuppressionRepository) List(ctx context.Context, filter repository.SuppressionFilter, afterID *int64, limit int) ([]domain.SuppressedNumber, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	switch {
+	case filter.CampaignID != nil:
+		conds = append(conds, "campaign_id = "+arg(*filter.CampaignID))
+	case filter.GlobalOnly:
+		conds = append(conds, "campaign_id IS NULL")
+	}
+	if filter.PhoneNumber != "" {
+		conds = append(conds, "phone_number = "+arg(filter.PhoneNumber))
+	}
+	if afterID != nil {
+		conds = append(conds, "id > "+arg(*afterID))
+	}
+
+	query := `SELECT id, campaign_id, phone_number, reason, created_by, created_at FROM suppressed_numbers`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY id ASC LIMIT " + arg(limit)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("suppression: list: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SuppressedNumber
+	for rows.Next() {
+		var rec struct {
+			ID          int64         `db:"id"`
+			CampaignID  uuid.NullUUID `db:"campaign_id"`
+			PhoneNumber string        `db:"phone_number"`
+			Reason      string        `db:"reason"`
+			CreatedBy   string        `db:"created_by"`
+			CreatedAt   time.Time     `db:"created_at"`
+		}
+		if err := rows.StructScan(&rec); err != nil {
+			return nil, fmt.Errorf("suppression: scan: %w", err)
+		}
+		entry := domain.SuppressedNumber{
+			ID:          rec.ID,
+			PhoneNumber: rec.PhoneNumber,
+			Reason:      rec.Reason,
+			CreatedBy:   rec.CreatedBy,
+			CreatedAt:   rec.CreatedAt,
+		}
+		if rec.CampaignID.Valid {
+			id := rec.CampaignID.UUID
+			entry.CampaignID = &id
+		}
+		results = append(results, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("suppression: rows err: %w", err)
+	}
+	return results, nil
+}
+
+// Match returns the reason for every number in phones suppressed globally or for the campaign.
+// Campaign-specific entries take precedence over global ones.
+func (r *SuppressionRepository) Match(ctx context.Context, campaignID uuid.UUID, phones []string) (map[string]string, error) {
+	matches := make(map[string]string)
+	if len(phones) == 0 {
+		return matches, nil
+	}
+
+	rows, err := r.db.QueryxContext(ctx, `SELECT phone_number, reason, campaign_id IS NOT NULL AS campaign_scoped
+		FROM suppressed_numbers
+		WHERE phone_number = ANY($1) AND (campaign_id IS NULL OR campaign_id = $2)`, phones, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("suppression: match: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			phone, reason  string
+			campaignScoped bool
+		)
+		if err := rows.Scan(&phone, &reason, &campaignScoped); err != nil {
+			return nil, fmt.Errorf("suppression: scan match: %w", err)
+		}
+		if _, seen := matches[phone]; seen && !campaignScoped {
+			continue
+		}
+		matches[phone] = reason
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("suppression: rows err: %w", err)
+	}
+	return matches, nil
+}
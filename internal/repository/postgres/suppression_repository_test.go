@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+func TestSuppressionMatchPrefersCampaignEntries(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	suppression := NewSuppressionRepository(db)
+	campaignID := insertCampaign(t, db)
+	otherID := insertCampaign(t, db)
+
+	entries := []domain.SuppressedNumber{
+		{PhoneNumber: "+14155550100", Reason: "global"},
+		{CampaignID: &campaignID, PhoneNumber: "+14155550100", Reason: "campaign"},
+		{PhoneNumber: "+14155550101", Reason: "global only"},
+		{CampaignID: &otherID, PhoneNumber: "+14155550102", Reason: "other campaign"},
+	}
+	for i := range entries {
+		if err := suppression.Add(ctx, &entries[i]); err != nil {
+			t.Fatalf("add %s: %v", entries[i].PhoneNumber, err)
+		}
+	}
+
+	matches, err := suppression.Match(ctx, campaignID, []string{"+14155550100", "+14155550101", "+14155550102", "+14155550103"})
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	want := map[string]string{"+14155550100": "campaign", "+14155550101": "global only"}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("Match = %v, want %v", matches, want)
+	}
+
+	if matches, err := suppression.Match(ctx, uuid.New(), nil); err != nil || len(matches) != 0 {
+		t.Fatalf("Match with no numbers = %v, %v; want empty", matches, err)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
@@ -23,28 +27,51 @@ func NewCampaignTargetRepository(db *sqlx.DB) *CampaignTargetRepository {
 	return &CampaignTargetRepository{db: db}
 }
 
-// BulkInsert inserts a batch of targets.
-func (r *CampaignTargetRepository) BulkInsert(ctx context.Context, campaignID uuid.UUID, targets []repository.CampaignTargetRecord) error {
+// BulkInsert inserts a batch of targets whose phone numbers are unique within the batch. Numbers
+// already in the campaign are left as they are, or under DuplicatePolicyReset put back to pending
+// unless a call for them is in flight.
+func (r *CampaignTargetRepository) BulkInsert(ctx context.Context, campaignID uuid.UUID, targets []repository.CampaignTargetRecord, policy domain.DuplicatePolicy) (repository.TargetInsertResult, error) {
+	result := repository.TargetInsertResult{}
 	if len(targets) == 0 {
-		return nil
+		return result, nil
 	}
 
 	query := `INSERT INTO campaign_targets (
-		id, campaign_id, phone_number, payload, state, scheduled_at, last_attempt_at, attempt_count, created_at, updated_at
-	) VALUES (:id, :campaign_id, :phone_number, :payload, :state, :scheduled_at, :last_attempt_at, :attempt_count, :created_at, :updated_at)
-	ON CONFLICT (id) DO NOTHING`
+		id, campaign_id, phone_number, payload, time_zone, state, scheduled_at, last_attempt_at, attempt_count, created_at, updated_at
+	) VALUES (:id, :campaign_id, :phone_number, :payload, :time_zone, :state, :scheduled_at, :last_attempt_at, :attempt_count, :created_at, :updated_at)
+	ON CONFLICT (campaign_id, phone_number) DO NOTHING
+	RETURNING phone_number, true AS inserted`
+	if policy == domain.DuplicatePolicyReset {
+		query = `INSERT INTO campaign_targets (
+		id, campaign_id, phone_number, payload, time_zone, state, scheduled_at, last_attempt_at, attempt_count, created_at, updated_at
+	) VALUES (:id, :campaign_id, :phone_number, :payload, :time_zone, :state, :scheduled_at, :last_attempt_at, :attempt_count, :created_at, :updated_at)
+	ON CONFLICT (campaign_id, phone_number) DO UPDATE SET
+		payload = EXCLUDED.payload,
+		time_zone = EXCLUDED.time_zone,
+		state = 'pending',
+		state_reason = NULL,
+		call_id = NULL,
+		claim_owner = NULL,
+		claim_expires_at = NULL,
+		scheduled_at = NULL,
+		last_attempt_at = NULL,
+		attempt_count = 0
+	WHERE campaign_targets.state NOT IN ('pending', 'staged', 'deferred', 'queued', 'dialing', 'retrying')
+	RETURNING phone_number, (xmax = 0) AS inserted`
+	}
 
 	rows := make([]map[string]any, 0, len(targets))
 	for _, t := range targets {
 		payload, err := json.Marshal(t.Payload)
 		if err != nil {
-			return fmt.Errorf("campaign targets: marshal payload: %w", err)
+			return result, fmt.Errorf("campaign targets: marshal payload: %w", err)
 		}
 		rows = append(rows, map[string]any{
 			"id":             t.ID,
 			"campaign_id":    campaignID,
 			"phone_number":   t.PhoneNumber,
 			"payload":        payload,
+			"time_zone":      t.TimeZone,
 			"state":          t.State,
 			"scheduled_at":   t.ScheduledAt,
 			"last_attempt_at": t.LastAttempt,
@@ -54,82 +81,201 @@ func (r *CampaignTargetRepository) BulkInsert(ctx context.Context, campaignID uu
 		})
 	}
 
-	if _, err := r.db.NamedExecContext(ctx, query, rows); err != nil {
-		return fmt.Errorf("campaign targets: bulk insert: %w", err)
+	res, err := r.db.NamedQueryContext(ctx, query, rows)
+	if err != nil {
+		return result, fmt.Errorf("campaign targets: bulk insert: %w", err)
 	}
+	defer res.Close()
 
-	return nil
+	written := make(map[string]struct{}, len(targets))
+	for res.Next() {
+		var (
+			phone    string
+			inserted bool
+		)
+		if err := res.Scan(&phone, &inserted); err != nil {
+			return result, fmt.Errorf("campaign targets: scan insert: %w", err)
+		}
+		written[phone] = struct{}{}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Reset++
+		}
+	}
+	if err := res.Err(); err != nil {
+		return result, fmt.Errorf("campaign targets: rows err: %w", err)
+	}
+
+	for _, t := range targets {
+		if _, ok := written[t.PhoneNumber]; !ok {
+			result.Existing = append(result.Existing, t.PhoneNumber)
+		}
+	}
+	return result, nil
 }
 
-// NextBatchForScheduling fetches pending targets for scheduling.
-func (r *CampaignTargetRepository) NextBatchForScheduling(ctx context.Context, campaignID uuid.UUID, limit int) ([]repository.CampaignTargetRecord, error) {
+// ClaimBatch atomically moves up to limit claimable targets to queued on behalf of owner.
+// Claimable targets are staged and deferred ones whose release time has come, pending ones once
+// nothing is left staged, and queued ones whose claim expired before a call was attached. SKIP LOCKED lets
+// concurrent schedulers claim disjoint batches of the same campaign. A non-empty zones limits
+// the claim to targets in those time zones.
+func (r *CampaignTargetRepository) ClaimBatch(ctx context.Context, campaignID uuid.UUID, owner string, limit int, ttl time.Duration, zones []string) ([]repository.CampaignTargetRecord, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 
-	rows, err := r.db.QueryxContext(ctx, `SELECT id, phone_number, payload, state, scheduled_at, last_attempt_at, attempt_count, created_at
-		FROM campaign_targets
-		WHERE campaign_id = $1 AND state = 'pending'
-		ORDER BY created_at ASC
-		LIMIT $2`, campaignID, limit)
-	if err != nil {
-		return nil, fmt.Errorf("campaign targets: select for scheduling: %w", err)
+	now := time.Now().UTC()
+	args := []any{campaignID, now, owner, now.Add(ttl), limit}
+	zoneFilter := ""
+	if len(zones) > 0 {
+		zoneFilter = "AND time_zone = ANY($6)"
+		args = append(args, zones)
 	}
-	defer rows.Close()
-
 	var results []repository.CampaignTargetRecord
-	for rows.Next() {
-		var rec targetRecord
-		if err := rows.StructScan(&rec); err != nil {
-			return nil, fmt.Errorf("campaign targets: scan: %w", err)
+	err := fenced(ctx, r.db, func(exec sqlx.ExtContext) error {
+		rows, err := exec.QueryxContext(ctx, `UPDATE campaign_targets t SET
+			state = 'queued',
+			scheduled_at = $2,
+			claim_owner = $3,
+			claim_expires_at = $4
+		FROM (
+			SELECT id FROM campaign_targets
+			WHERE campaign_id = $1
+				AND (
+					(state IN ('staged', 'deferred') AND scheduled_at <= $2)
+					OR (state = 'pending' AND NOT EXISTS (
+						SELECT 1 FROM campaign_targets s WHERE s.campaign_id = $1 AND s.state = 'staged'))
+					OR (state = 'queued' AND call_id IS NULL AND claim_expires_at < $2))
+				`+zoneFilter+`
+			ORDER BY CASE WHEN state IN ('staged', 'deferred') THEN scheduled_at END ASC NULLS LAST, created_at ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		) claimable
+		WHERE t.campaign_id = $1 AND t.id = claimable.id
+		RETURNING t.id, t.phone_number, t.payload, t.time_zone, t.state, t.state_reason, t.call_id, t.scheduled_at, t.last_attempt_at, t.attempt_count, t.created_at`,
+			args...)
+		if err != nil {
+			return fmt.Errorf("campaign targets: claim batch: %w", err)
 		}
-		results = append(results, rec.toModel(campaignID))
-	}
+		defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("campaign targets: rows err: %w", err)
+		for rows.Next() {
+			var rec targetRecord
+			if err := rows.StructScan(&rec); err != nil {
+				return fmt.Errorf("campaign targets: scan: %w", err)
+			}
+			results = append(results, rec.toModel(campaignID))
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("campaign targets: rows err: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	sort.Slice(results, func(i, j int) bool { return results[i].CreatedAt.Before(results[j].CreatedAt) })
 	return results, nil
 }
 
-// MarkScheduled marks targets as scheduled.
-func (r *CampaignTargetRepository) MarkScheduled(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, scheduledAt time.Time) error {
+// SetState updates the state for the specified targets.
+func (r *CampaignTargetRepository) SetState(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, state string) error {
 	if len(targetIDs) == 0 {
 		return nil
 	}
-
-	query := `UPDATE campaign_targets SET state = 'queued', scheduled_at = $1 WHERE campaign_id = $2 AND id = ANY($3)`
+	query := `UPDATE campaign_targets SET state = $1 WHERE campaign_id = $2 AND id = ANY($3)`
 	ids := make([]uuid.UUID, len(targetIDs))
 	copy(ids, targetIDs)
-
-	if _, err := r.db.ExecContext(ctx, query, scheduledAt, campaignID, ids); err != nil {
-		return fmt.Errorf("campaign targets: mark scheduled: %w", err)
+	if _, err := execFenced(ctx, r.db, query, state, campaignID, ids); err != nil {
+		return fmt.Errorf("campaign targets: set state: %w", err)
 	}
 	return nil
 }
 
-// SetState updates the state for the specified targets.
-func (r *CampaignTargetRepository) SetState(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, state string) error {
+// SetStateWithReason updates the state for the specified targets and records why.
+func (r *CampaignTargetRepository) SetStateWithReason(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, state, reason string) error {
 	if len(targetIDs) == 0 {
 		return nil
 	}
-	query := `UPDATE campaign_targets SET state = $1 WHERE campaign_id = $2 AND id = ANY($3)`
+	query := `UPDATE campaign_targets SET state = $1, state_reason = $2 WHERE campaign_id = $3 AND id = ANY($4)`
 	ids := make([]uuid.UUID, len(targetIDs))
 	copy(ids, targetIDs)
-	if _, err := r.db.ExecContext(ctx, query, state, campaignID, ids); err != nil {
-		return fmt.Errorf("campaign targets: set state: %w", err)
+	if _, err := execFenced(ctx, r.db, query, state, reason, campaignID, ids); err != nil {
+		return fmt.Errorf("campaign targets: set state with reason: %w", err)
+	}
+	return nil
+}
+
+// AttachCall links the call created for a target, marks the target queued and settles any claim on it.
+func (r *CampaignTargetRepository) AttachCall(ctx context.Context, campaignID, targetID, callID uuid.UUID, claimOwner string) error {
+	return attachCall(ctx, r.db, campaignID, targetID, callID, claimOwner)
+}
+
+// attachCall links callID to a pending target, or to one claimed by claimOwner that has no call
+// yet; an empty claimOwner matches only unclaimed targets. Any other target is already being
+// called or has finished, and ErrConflict is returned.
+func attachCall(ctx context.Context, exec sqlx.ExecerContext, campaignID, targetID, callID uuid.UUID, claimOwner string) error {
+	res, err := exec.ExecContext(ctx, `UPDATE campaign_targets SET call_id = $1, state = 'queued', claim_owner = NULL, claim_expires_at = NULL
+		WHERE campaign_id = $2 AND id = $3
+			AND (state = 'pending' OR (state = 'queued' AND call_id IS NULL AND claim_owner IS NOT DISTINCT FROM NULLIF($4, '')))`,
+		callID, campaignID, targetID, claimOwner)
+	if err != nil {
+		return fmt.Errorf("campaign targets: attach call: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: target %s cannot take a new call", repository.ErrConflict, targetID)
 	}
 	return nil
 }
 
+// ApplyCallUpdate records a call status report on its target and reports whether a row changed.
+// Attempt bookkeeping only moves forward so replayed or reordered reports cannot rewind it.
+func (r *CampaignTargetRepository) ApplyCallUpdate(ctx context.Context, update repository.TargetCallUpdate) (bool, error) {
+	query := `UPDATE campaign_targets SET
+		state = $1,
+		attempt_count = GREATEST(attempt_count, $2),
+		last_attempt_at = CASE WHEN $3::boolean THEN GREATEST(COALESCE(last_attempt_at, $4::timestamptz), $4::timestamptz) ELSE last_attempt_at END,
+		call_id = CASE WHEN $8::boolean THEN NULL ELSE COALESCE(call_id, $5) END,
+		state_reason = NULLIF($7, ''),
+		scheduled_at = COALESCE($9::timestamptz, scheduled_at)
+	WHERE campaign_id = $6`
+	args := []any{update.State, update.Attempt, update.Dialed, update.OccurredAt, update.CallID, update.CampaignID, update.Reason, update.Detach, update.ScheduledAt}
+	if update.TargetID != uuid.Nil {
+		query += ` AND id = $10`
+		args = append(args, update.TargetID)
+	} else {
+		query += ` AND call_id = $10`
+		args = append(args, update.CallID)
+	}
+	if len(update.OnlyFrom) > 0 {
+		query += ` AND state = ANY($11)`
+		args = append(args, update.OnlyFrom)
+	}
+
+	res, err := execFenced(ctx, r.db, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: apply call update: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
 // ListByCampaign lists targets filtered by state.
 func (r *CampaignTargetRepository) ListByCampaign(ctx context.Context, campaignID uuid.UUID, limit int, state string) ([]repository.CampaignTargetRecord, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 
-	query := `SELECT id, phone_number, payload, state, scheduled_at, last_attempt_at, attempt_count, created_at
+	query := `SELECT id, phone_number, payload, time_zone, state, state_reason, call_id, scheduled_at, last_attempt_at, attempt_count, created_at
 		FROM campaign_targets
 		WHERE campaign_id = $1`
 	args := []any{campaignID}
@@ -163,11 +309,277 @@ func (r *CampaignTargetRepository) ListByCampaign(ctx context.Context, campaignI
 	return results, nil
 }
 
+// Query lists targets ordered by creation time, starting after the cursor when provided.
+func (r *CampaignTargetRepository) Query(ctx context.Context, filter repository.TargetFilter, after *repository.TargetCursor, limit int) ([]repository.CampaignTargetRecord, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conds := []string{"campaign_id = " + arg(filter.CampaignID)}
+	if filter.State != "" {
+		conds = append(conds, "state = "+arg(filter.State))
+	}
+	if filter.PhoneNumber != "" {
+		conds = append(conds, "phone_number LIKE "+arg("%"+likeEscaper.Replace(filter.PhoneNumber)+"%"))
+	}
+	if after != nil {
+		conds = append(conds, fmt.Sprintf("(created_at, id) > (%s, %s)", arg(after.CreatedAt), arg(after.ID)))
+	}
+
+	query := `SELECT id, phone_number, payload, time_zone, state, state_reason, call_id, scheduled_at, last_attempt_at, attempt_count, created_at
+		FROM campaign_targets
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY created_at ASC, id ASC
+		LIMIT ` + arg(limit)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("campaign targets: query: %w", err)
+	}
+	defer rows.Close()
+
+	var results []repository.CampaignTargetRecord
+	for rows.Next() {
+		var rec targetRecord
+		if err := rows.StructScan(&rec); err != nil {
+			return nil, fmt.Errorf("campaign targets: scan: %w", err)
+		}
+		results = append(results, rec.toModel(filter.CampaignID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("campaign targets: rows err: %w", err)
+	}
+	return results, nil
+}
+
+// Get fetches a single target of the campaign.
+func (r *CampaignTargetRepository) Get(ctx context.Context, campaignID, targetID uuid.UUID) (*repository.CampaignTargetRecord, error) {
+	var rec targetRecord
+	err := r.db.GetContext(ctx, &rec, `SELECT id, phone_number, payload, time_zone, state, state_reason, call_id, scheduled_at, last_attempt_at, attempt_count, created_at
+		FROM campaign_targets
+		WHERE campaign_id = $1 AND id = $2`, campaignID, targetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("campaign targets: get: %w", err)
+	}
+	model := rec.toModel(campaignID)
+	return &model, nil
+}
+
+// FindIDByPhone resolves a number to its target using the (campaign_id, phone_number) unique index.
+func (r *CampaignTargetRepository) FindIDByPhone(ctx context.Context, campaignID uuid.UUID, phoneNumber string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.GetContext(ctx, &id, `SELECT id FROM campaign_targets WHERE campaign_id = $1 AND phone_number = $2`, campaignID, phoneNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, repository.ErrNotFound
+		}
+		return uuid.Nil, fmt.Errorf("campaign targets: find by phone: %w", err)
+	}
+	return id, nil
+}
+
+// UpdatePayload replaces the payload and time zone of a target still in one of fromStates.
+func (r *CampaignTargetRepository) UpdatePayload(ctx context.Context, campaignID, targetID uuid.UUID, payload map[string]any, timeZone string, fromStates []string) (bool, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: marshal payload: %w", err)
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE campaign_targets SET payload = $1, time_zone = $5
+		WHERE campaign_id = $2 AND id = $3 AND state = ANY($4)`, data, campaignID, targetID, fromStates, timeZone)
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: update payload: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Delete removes a target still in one of fromStates.
+func (r *CampaignTargetRepository) Delete(ctx context.Context, campaignID, targetID uuid.UUID, fromStates []string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM campaign_targets
+		WHERE campaign_id = $1 AND id = $2 AND state = ANY($3)`, campaignID, targetID, fromStates)
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: delete: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// likeEscaper escapes LIKE wildcards in user supplied search terms.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Stage moves up to limit pending targets, oldest first, to staged with release times spread
+// from releaseAt onwards, spacing apart. It returns how many were staged.
+func (r *CampaignTargetRepository) Stage(ctx context.Context, campaignID uuid.UUID, limit int, releaseAt time.Time, spacing time.Duration) (int64, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	res, err := execFenced(ctx, r.db, `UPDATE campaign_targets t SET
+			state = 'staged',
+			scheduled_at = $2::timestamptz + (ordered.rn - 1) * $3::bigint * INTERVAL '1 millisecond'
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS rn
+			FROM (
+				SELECT id, created_at FROM campaign_targets
+				WHERE campaign_id = $1 AND state = 'pending'
+				ORDER BY created_at ASC, id ASC
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			) locked
+		) ordered
+		WHERE t.campaign_id = $1 AND t.id = ordered.id`,
+		campaignID, releaseAt, spacing.Milliseconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: stage: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// ListStale returns targets of any campaign whose last update in one of states is older than olderThan.
+func (r *CampaignTargetRepository) ListStale(ctx context.Context, states []string, olderThan time.Time, limit int) ([]repository.CampaignTargetRecord, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := r.db.QueryxContext(ctx, `SELECT campaign_id, id, phone_number, payload, time_zone, state, state_reason, call_id, scheduled_at, last_attempt_at, attempt_count, created_at
+		FROM campaign_targets
+		WHERE state = ANY($1) AND updated_at < $2
+		ORDER BY updated_at ASC
+		LIMIT $3`, states, olderThan, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign targets: list stale: %w", err)
+	}
+	defer rows.Close()
+
+	var results []repository.CampaignTargetRecord
+	for rows.Next() {
+		var rec targetRecord
+		if err := rows.StructScan(&rec); err != nil {
+			return nil, fmt.Errorf("campaign targets: scan: %w", err)
+		}
+		results = append(results, rec.toModel(rec.CampaignID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("campaign targets: rows err: %w", err)
+	}
+	return results, nil
+}
+
+// Requeue returns queued targets to pending so they are claimed again.
+func (r *CampaignTargetRepository) Requeue(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, reason string) (int64, error) {
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+	res, err := execFenced(ctx, r.db, `UPDATE campaign_targets SET
+			state = 'pending',
+			state_reason = $1,
+			call_id = NULL,
+			claim_owner = NULL,
+			claim_expires_at = NULL
+		WHERE campaign_id = $2 AND id = ANY($3) AND state = 'queued'`, reason, campaignID, targetIDs)
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: requeue: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// Defer moves queued targets without a call to deferred so they are claimed again once until has passed.
+func (r *CampaignTargetRepository) Defer(ctx context.Context, campaignID uuid.UUID, targetIDs []uuid.UUID, until time.Time, reason string) (int64, error) {
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+	res, err := execFenced(ctx, r.db, `UPDATE campaign_targets SET
+			state = 'deferred',
+			state_reason = $1,
+			scheduled_at = $2,
+			claim_owner = NULL,
+			claim_expires_at = NULL
+		WHERE campaign_id = $3 AND id = ANY($4) AND state = 'queued' AND call_id IS NULL`, reason, until.UTC(), campaignID, targetIDs)
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: defer: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("campaign targets: rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// CountByState returns the number of targets per state for a campaign.
+func (r *CampaignTargetRepository) CountByState(ctx context.Context, campaignID uuid.UUID) (map[string]int64, error) {
+	rows, err := r.db.QueryxContext(ctx, `SELECT state, COUNT(*) FROM campaign_targets WHERE campaign_id = $1 GROUP BY state`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("campaign targets: count by state: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			state string
+			count int64
+		)
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("campaign targets: scan count: %w", err)
+		}
+		counts[state] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("campaign targets: rows err: %w", err)
+	}
+	return counts, nil
+}
+
+// CountInState counts a campaign's targets in a single state.
+func (r *CampaignTargetRepository) CountInState(ctx context.Context, campaignID uuid.UUID, state string) (int64, error) {
+	var count int64
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM campaign_targets WHERE campaign_id = $1 AND state = $2`, campaignID, state); err != nil {
+		return 0, fmt.Errorf("campaign targets: count in state: %w", err)
+	}
+	return count, nil
+}
+
+// ListTimeZones returns the distinct time zones of the campaign's targets in one of states.
+func (r *CampaignTargetRepository) ListTimeZones(ctx context.Context, campaignID uuid.UUID, states []string) ([]string, error) {
+	var zones []string
+	if err := r.db.SelectContext(ctx, &zones, `SELECT DISTINCT time_zone FROM campaign_targets
+		WHERE campaign_id = $1 AND state = ANY($2)`, campaignID, states); err != nil {
+		return nil, fmt.Errorf("campaign targets: list time zones: %w", err)
+	}
+	return zones, nil
+}
+
 type targetRecord struct {
+	CampaignID  uuid.UUID      `db:"campaign_id"`
 	ID          uuid.UUID      `db:"id"`
 	PhoneNumber string         `db:"phone_number"`
 	Payload     []byte         `db:"payload"`
+	TimeZone    string         `db:"time_zone"`
 	State       string         `db:"state"`
+	StateReason sql.NullString `db:"state_reason"`
+	CallID      uuid.NullUUID  `db:"call_id"`
 	ScheduledAt sql.NullTime   `db:"scheduled_at"`
 	LastAttempt sql.NullTime   `db:"last_attempt_at"`
 	AttemptCnt  int            `db:"attempt_count"`
@@ -183,10 +595,16 @@ func (r targetRecord) toModel(campaignID uuid.UUID) repository.CampaignTargetRec
 		CampaignID:   campaignID,
 		PhoneNumber:  r.PhoneNumber,
 		Payload:      payload,
+		TimeZone:     r.TimeZone,
 		State:        r.State,
+		StateReason:  r.StateReason.String,
 		AttemptCount: r.AttemptCnt,
 		CreatedAt:    r.CreatedAt,
 	}
+	if r.CallID.Valid {
+		id := r.CallID.UUID
+		record.CallID = &id
+	}
 	if r.ScheduledAt.Valid {
 		t := r.ScheduledAt.Time
 		record.ScheduledAt = &t
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// insertCampaign stores an in-progress campaign and returns its id.
+func insertCampaign(t *testing.T, db *sqlx.DB) uuid.UUID {
+	t.Helper()
+	campaignID := uuid.New()
+	if _, err := db.ExecContext(context.Background(), `INSERT INTO campaigns (id, name, time_zone, max_concurrent_calls, status, retry_max_attempts, retry_base_delay_ms, retry_max_delay_ms, retry_jitter)
+		VALUES ($1, $2, 'UTC', 5, 'in_progress', 3, 1000, 60000, 0)`, campaignID, "test-"+campaignID.String()); err != nil {
+		t.Fatalf("insert campaign: %v", err)
+	}
+	return campaignID
+}
+
+// insertTargets stores a campaign with one pending target per number, created a second apart in
+// the given order, and returns the campaign and target ids.
+func insertTargets(t *testing.T, db *sqlx.DB, numbers ...string) (uuid.UUID, []uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	campaignID := insertCampaign(t, db)
+
+	created := time.Now().UTC().Add(-time.Hour)
+	ids := make([]uuid.UUID, 0, len(numbers))
+	for i, number := range numbers {
+		id := uuid.New()
+		if _, err := db.ExecContext(ctx, `INSERT INTO campaign_targets (id, campaign_id, phone_number, created_at) VALUES ($1, $2, $3, $4)`,
+			id, campaignID, number, created.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("insert target: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return campaignID, ids
+}
+
+type targetSlot struct {
+	State       string     `db:"state"`
+	ScheduledAt *time.Time `db:"scheduled_at"`
+}
+
+func readTarget(t *testing.T, db *sqlx.DB, id uuid.UUID) targetSlot {
+	t.Helper()
+	var slot targetSlot
+	if err := db.GetContext(context.Background(), &slot, `SELECT state, scheduled_at FROM campaign_targets WHERE id = $1`, id); err != nil {
+		t.Fatalf("read target: %v", err)
+	}
+	return slot
+}
+
+func TestStageSpreadsOldestPendingTargets(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	targets := NewCampaignTargetRepository(db)
+	campaignID, ids := insertTargets(t, db, "+14155550100", "+14155550101", "+14155550102")
+
+	releaseAt := time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond)
+	n, err := targets.Stage(ctx, campaignID, 2, releaseAt, 10*time.Second)
+	if err != nil || n != 2 {
+		t.Fatalf("Stage = %d, %v; want 2, nil", n, err)
+	}
+	for i, want := range []time.Time{releaseAt, releaseAt.Add(10 * time.Second)} {
+		slot := readTarget(t, db, ids[i])
+		if slot.State != domain.TargetStateStaged || slot.ScheduledAt == nil || !slot.ScheduledAt.Equal(want) {
+			t.Errorf("target %d = %s at %v, want staged at %v", i, slot.State, slot.ScheduledAt, want)
+		}
+	}
+	if slot := readTarget(t, db, ids[2]); slot.State != domain.TargetStatePending {
+		t.Errorf("newest target = %s, want pending", slot.State)
+	}
+}
+
+func TestClaimBatchHoldsPendingWhileStaged(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	targets := NewCampaignTargetRepository(db)
+	campaignID, ids := insertTargets(t, db, "+14155550100", "+14155550101", "+14155550102")
+
+	// Staged ahead of an opening an hour away: nothing is claimable yet, not even the pending
+	// target, so the batch is not undercut by a burst of pending targets.
+	if _, err := targets.Stage(ctx, campaignID, 2, time.Now().UTC().Add(time.Hour), time.Second); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+	claimed, err := targets.ClaimBatch(ctx, campaignID, "a", 10, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("claim before release: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Fatalf("claimed %d targets before the staged release, want 0", len(claimed))
+	}
+
+	// Once released, the staged targets are claimed and the pending one still waits for them.
+	if _, err := db.ExecContext(ctx, `UPDATE campaign_targets SET scheduled_at = NOW() - INTERVAL '1 minute' WHERE state = 'staged'`); err != nil {
+		t.Fatalf("release staged targets: %v", err)
+	}
+	claimed, err = targets.ClaimBatch(ctx, campaignID, "a", 10, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("claim after release: %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].ID != ids[0] || claimed[1].ID != ids[1] {
+		t.Fatalf("claimed %+v, want the two staged targets", claimed)
+	}
+
+	claimed, err = targets.ClaimBatch(ctx, campaignID, "a", 10, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("claim pending: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != ids[2] {
+		t.Fatalf("claimed %+v, want the pending target", claimed)
+	}
+}
+
+func TestClaimBatchReclaimsExpiredClaims(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	targets := NewCampaignTargetRepository(db)
+	campaignID, ids := insertTargets(t, db, "+14155550100", "+14155550101")
+
+	// A negative TTL leaves the claims already expired, as if the scheduler had died holding them.
+	claimed, err := targets.ClaimBatch(ctx, campaignID, "a", 10, -time.Second, nil)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("first claim = %d targets, %v; want 2", len(claimed), err)
+	}
+	// The second target got its call before the claim expired, so it is no longer reclaimable.
+	if _, err := db.ExecContext(ctx, `UPDATE campaign_targets SET call_id = $1 WHERE id = $2`, uuid.New(), ids[1]); err != nil {
+		t.Fatalf("attach call: %v", err)
+	}
+
+	claimed, err = targets.ClaimBatch(ctx, campaignID, "b", 10, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("reclaim: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != ids[0] {
+		t.Fatalf("reclaimed %+v, want only the target without a call", claimed)
+	}
+
+	// The new claim has not expired, so nobody else takes it.
+	claimed, err = targets.ClaimBatch(ctx, campaignID, "c", 10, time.Minute, nil)
+	if err != nil || len(claimed) != 0 {
+		t.Fatalf("claim while held = %d targets, %v; want 0", len(claimed), err)
+	}
+}
+
+func TestAttachCallOnlyToCallableTargets(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	targets := NewCampaignTargetRepository(db)
+	campaignID, ids := insertTargets(t, db, "+14155550100", "+14155550101", "+14155550102")
+
+	// An unclaimed pending target takes an ad-hoc call, but only one.
+	if err := targets.AttachCall(ctx, campaignID, ids[0], uuid.New(), ""); err != nil {
+		t.Fatalf("attach to pending target: %v", err)
+	}
+	if err := targets.AttachCall(ctx, campaignID, ids[0], uuid.New(), ""); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("second attach = %v, want ErrConflict", err)
+	}
+
+	// A claimed target takes a call only from the replica holding the claim.
+	claimed, err := targets.ClaimBatch(ctx, campaignID, "a", 1, time.Minute, nil)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != ids[1] {
+		t.Fatalf("claim = %+v, %v; want the second target", claimed, err)
+	}
+	for _, owner := range []string{"", "b"} {
+		if err := targets.AttachCall(ctx, campaignID, ids[1], uuid.New(), owner); !errors.Is(err, repository.ErrConflict) {
+			t.Fatalf("attach by %q = %v, want ErrConflict", owner, err)
+		}
+	}
+	if err := targets.AttachCall(ctx, campaignID, ids[1], uuid.New(), "a"); err != nil {
+		t.Fatalf("attach by claim owner: %v", err)
+	}
+
+	// A finished target is not reopened.
+	if _, err := db.ExecContext(ctx, `UPDATE campaign_targets SET state = 'completed' WHERE id = $1`, ids[2]); err != nil {
+		t.Fatalf("complete target: %v", err)
+	}
+	if err := targets.AttachCall(ctx, campaignID, ids[2], uuid.New(), ""); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("attach to completed target = %v, want ErrConflict", err)
+	}
+	if slot := readTarget(t, db, ids[2]); slot.State != domain.TargetStateCompleted {
+		t.Fatalf("completed target moved to %s", slot.State)
+	}
+}
@@ -7,12 +7,18 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// withTx runs fn in a transaction. When ctx carries a scheduler fence the transaction first
+// checks that it still stands.
 func withTx(ctx context.Context, db *sqlx.DB, fn func(*sqlx.Tx) error) error {
 	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("tx begin: %w", err)
 	}
 
+	if err := checkFence(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 	if err := fn(tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("tx rollback: %v (original err: %w)", rbErr, err)
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
 // CallStore persists call records in Scylla.
@@ -107,7 +108,7 @@ func (s *CallStore) GetCall(ctx context.Context, callID uuid.UUID) (*domain.Call
 		if err := iter.Close(); err != nil {
 			return nil, fmt.Errorf("call store: fetch call close: %w", err)
 		}
-		return nil, fmt.Errorf("call store: call %s not found", callID)
+		return nil, fmt.Errorf("call store: call %s: %w", callID, repository.ErrNotFound)
 	}
 	iter.Close()
 
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClaimSize(t *testing.T) {
+	cases := []struct {
+		name        string
+		limit       int
+		free        int
+		headroom    int
+		queued      int64
+		outstanding int
+		want        int
+	}{
+		{name: "free slots", limit: 100, free: 10, want: 10},
+		{name: "headroom on top", limit: 100, free: 10, headroom: 5, want: 15},
+		{name: "negative headroom ignored", limit: 100, free: 10, headroom: -5, want: 10},
+		{name: "queued and retries taken off", limit: 100, free: 10, headroom: 5, queued: 4, outstanding: 3, want: 8},
+		{name: "capped at limit", limit: 20, free: 50, headroom: 10, want: 20},
+		{name: "unlimited campaign", limit: 100, free: math.MaxInt, headroom: 10, want: 100},
+		{name: "more in flight than free", limit: 100, free: 2, queued: 5, outstanding: 1, want: -4},
+	}
+	for _, tc := range cases {
+		if got := claimSize(tc.limit, tc.free, tc.headroom, tc.queued, tc.outstanding); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
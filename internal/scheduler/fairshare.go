@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// shareRequest is one campaign's demand for new dispatches in a tick.
+type shareRequest struct {
+	ID       uuid.UUID
+	Priority int
+	Weight   int
+	Demand   int
+}
+
+// shareGrant is the number of targets a campaign may claim this tick. Grants are returned in
+// dispatch order; Deficit is the credit the campaign carries into the next tick.
+type shareGrant struct {
+	ID       uuid.UUID
+	Priority int
+	Weight   int
+	Demand   int
+	Amount   int
+	Deficit  int
+}
+
+// fairShare splits a per-tick dispatch budget across campaigns with deficit round robin.
+// Priorities are served strictly, highest first; campaigns of equal priority earn
+// quantum*weight credit per round and spend it on their demand, so a large campaign cannot
+// crowd out small ones. Credit and the round-robin position carry over between ticks.
+type fairShare struct {
+	quantum  int
+	deficits map[uuid.UUID]int
+	visited  map[uuid.UUID]uint64
+	visits   uint64
+}
+
+func newFairShare(quantum int) *fairShare {
+	if quantum <= 0 {
+		quantum = 10
+	}
+	return &fairShare{
+		quantum:  quantum,
+		deficits: make(map[uuid.UUID]int),
+		visited:  make(map[uuid.UUID]uint64),
+	}
+}
+
+// allocate divides budget between requests. A budget of zero or less grants every demand in
+// full and only fixes the order.
+func (f *fairShare) allocate(requests []shareRequest, budget int) []shareGrant {
+	f.forgetMissing(requests)
+
+	order := make([]int, len(requests))
+	for i := range order {
+		order[i] = i
+	}
+	// Within a priority, campaigns that waited longest for their turn go first.
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := requests[order[a]], requests[order[b]]
+		if ra.Priority != rb.Priority {
+			return ra.Priority > rb.Priority
+		}
+		return f.visited[ra.ID] < f.visited[rb.ID]
+	})
+
+	grants := make([]shareGrant, len(order))
+	for i, idx := range order {
+		req := requests[idx]
+		grants[i] = shareGrant{ID: req.ID, Priority: req.Priority, Weight: max(req.Weight, 1), Demand: max(req.Demand, 0)}
+	}
+
+	if budget <= 0 {
+		for i := range grants {
+			grants[i].Amount = grants[i].Demand
+			f.settle(&grants[i])
+		}
+		return grants
+	}
+
+	for start := 0; start < len(grants); {
+		end := start
+		for end < len(grants) && grants[end].Priority == grants[start].Priority {
+			end++
+		}
+		budget = f.serve(grants[start:end], budget)
+		start = end
+	}
+
+	for i := range grants {
+		f.settle(&grants[i])
+	}
+	return grants
+}
+
+// serve runs rounds over one priority class until its demand or the budget is exhausted and
+// returns what is left of the budget.
+func (f *fairShare) serve(class []shareGrant, budget int) int {
+	for budget > 0 {
+		progressed := false
+		for i := range class {
+			g := &class[i]
+			if g.Amount >= g.Demand {
+				continue
+			}
+			if budget == 0 {
+				return 0
+			}
+			f.visits++
+			f.visited[g.ID] = f.visits
+
+			deficit := f.deficits[g.ID] + f.quantum*g.Weight
+			take := min(deficit, g.Demand-g.Amount, budget)
+			g.Amount += take
+			budget -= take
+			f.deficits[g.ID] = deficit - take
+			progressed = true
+		}
+		if !progressed {
+			break
+		}
+	}
+	return budget
+}
+
+// settle records the carried credit. A campaign whose demand was met keeps none, as in
+// classic DRR, so idle campaigns do not bank credit.
+func (f *fairShare) settle(g *shareGrant) {
+	if g.Amount >= g.Demand {
+		delete(f.deficits, g.ID)
+	}
+	g.Deficit = f.deficits[g.ID]
+}
+
+func (f *fairShare) forgetMissing(requests []shareRequest) {
+	present := make(map[uuid.UUID]struct{}, len(requests))
+	for _, r := range requests {
+		present[r.ID] = struct{}{}
+	}
+	for id := range f.visited {
+		if _, ok := present[id]; !ok {
+			delete(f.visited, id)
+			delete(f.deficits, id)
+		}
+	}
+}
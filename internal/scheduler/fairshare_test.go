@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func grantFor(grants []shareGrant, id uuid.UUID) shareGrant {
+	for _, g := range grants {
+		if g.ID == id {
+			return g
+		}
+	}
+	return shareGrant{}
+}
+
+func TestFairShareSplitsByWeight(t *testing.T) {
+	big, small := uuid.New(), uuid.New()
+	fs := newFairShare(10)
+
+	grants := fs.allocate([]shareRequest{
+		{ID: big, Weight: 3, Demand: 1000},
+		{ID: small, Weight: 1, Demand: 1000},
+	}, 80)
+
+	if got := grantFor(grants, big).Amount; got != 60 {
+		t.Fatalf("expected weight-3 campaign to get 60, got %d", got)
+	}
+	if got := grantFor(grants, small).Amount; got != 20 {
+		t.Fatalf("expected weight-1 campaign to get 20, got %d", got)
+	}
+}
+
+func TestFairShareSmallCampaignNotStarved(t *testing.T) {
+	big, small := uuid.New(), uuid.New()
+	fs := newFairShare(10)
+
+	grants := fs.allocate([]shareRequest{
+		{ID: big, Weight: 1, Demand: 5000},
+		{ID: small, Weight: 1, Demand: 5},
+	}, 100)
+
+	if got := grantFor(grants, small).Amount; got != 5 {
+		t.Fatalf("expected small campaign demand to be met, got %d", got)
+	}
+	if got := grantFor(grants, big).Amount; got != 95 {
+		t.Fatalf("expected big campaign to take the rest, got %d", got)
+	}
+	if d := grantFor(grants, small).Deficit; d != 0 {
+		t.Fatalf("expected satisfied campaign to carry no credit, got %d", d)
+	}
+}
+
+func TestFairShareServesHigherPriorityFirst(t *testing.T) {
+	urgent, normal := uuid.New(), uuid.New()
+	fs := newFairShare(10)
+
+	grants := fs.allocate([]shareRequest{
+		{ID: normal, Priority: 0, Weight: 5, Demand: 100},
+		{ID: urgent, Priority: 10, Weight: 1, Demand: 30},
+	}, 40)
+
+	if grants[0].ID != urgent {
+		t.Fatalf("expected urgent campaign to be dispatched first")
+	}
+	if got := grantFor(grants, urgent).Amount; got != 30 {
+		t.Fatalf("expected urgent campaign to be served in full, got %d", got)
+	}
+	if got := grantFor(grants, normal).Amount; got != 10 {
+		t.Fatalf("expected normal campaign to get the remainder, got %d", got)
+	}
+}
+
+func TestFairShareRotatesAcrossTicks(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	fs := newFairShare(10)
+	requests := []shareRequest{
+		{ID: a, Weight: 1, Demand: 100},
+		{ID: b, Weight: 1, Demand: 100},
+		{ID: c, Weight: 1, Demand: 100},
+	}
+
+	totals := make(map[uuid.UUID]int)
+	for tick := 0; tick < 3; tick++ {
+		for _, g := range fs.allocate(requests, 10) {
+			totals[g.ID] += g.Amount
+		}
+	}
+
+	for _, id := range []uuid.UUID{a, b, c} {
+		if totals[id] != 10 {
+			t.Fatalf("expected each campaign to get one turn over three ticks, got %v", totals)
+		}
+	}
+}
+
+func TestFairShareUnlimitedBudget(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	fs := newFairShare(10)
+
+	grants := fs.allocate([]shareRequest{
+		{ID: a, Demand: 500},
+		{ID: b, Priority: 1, Demand: 7},
+	}, 0)
+
+	if grants[0].ID != b || grants[0].Amount != 7 || grants[1].Amount != 500 {
+		t.Fatalf("unexpected grants %+v", grants)
+	}
+}
@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+// ErrNotLeader is returned by Elector.Confirm once this replica no longer holds the lease.
+var ErrNotLeader = errors.New("scheduler: leadership lost")
+
+// acquireScript takes the lease when it is free and renews it when already held by ARGV[1].
+// Each new holder gets the next value of the fencing counter in KEYS[2].
+// Returns {held, token, holder}.
+var acquireScript = redis.NewScript(`
+local holder = redis.call('HGET', KEYS[1], 'holder')
+if not holder then
+  local token = redis.call('INCR', KEYS[2])
+  redis.call('HSET', KEYS[1], 'holder', ARGV[1], 'token', token)
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  return {1, token, ARGV[1]}
+end
+local token = tonumber(redis.call('HGET', KEYS[1], 'token'))
+if holder == ARGV[1] then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  return {1, token, holder}
+end
+return {0, token, holder}
+`)
+
+// confirmScript reports whether ARGV[1] still holds the lease under fencing token ARGV[2].
+var confirmScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'holder') == ARGV[1] and redis.call('HGET', KEYS[1], 'token') == ARGV[2] then
+  return 1
+end
+return 0
+`)
+
+// releaseScript drops the lease if ARGV[1] still holds it so a standby can take over at once.
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'holder') == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Elector keeps a Redis lease that names the single scheduler replica allowed to dispatch.
+// The lease is renewed every third of its TTL; a replica that cannot renew before the TTL
+// runs out stops considering itself leader even if Redis is unreachable.
+type Elector struct {
+	client   *redis.Client
+	logger   *logger.Logger
+	identity string
+	key      string
+	tokenKey string
+	ttl      time.Duration
+
+	mu        sync.RWMutex
+	leading   bool
+	token     int64
+	leader    string
+	renewedAt time.Time
+
+	transitions metric.Int64Counter
+}
+
+// NewElector builds an elector for the lease stored under prefix. An empty identity defaults
+// to the host name and process id.
+func NewElector(client *redis.Client, lg *logger.Logger, prefix, identity string, ttl time.Duration) *Elector {
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	if prefix == "" {
+		prefix = "campaign-scheduler"
+	}
+	if identity == "" {
+		identity = defaultIdentity()
+	}
+
+	e := &Elector{
+		client:   client,
+		logger:   lg,
+		identity: identity,
+		key:      prefix + ":leader",
+		tokenKey: prefix + ":leader:token",
+		ttl:      ttl,
+	}
+	e.registerMetrics()
+	return e
+}
+
+// Identity names this replica in the lease.
+func (e *Elector) Identity() string {
+	return e.identity
+}
+
+// Lease returns the Redis key of the lease, which also names its fence in Postgres.
+func (e *Elector) Lease() string {
+	return e.key
+}
+
+// Leading reports whether this replica holds an unexpired lease and the fencing token it holds it under.
+func (e *Elector) Leading() (int64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.leading || time.Since(e.renewedAt) >= e.ttl {
+		return 0, false
+	}
+	return e.token, true
+}
+
+// Run campaigns for the lease until ctx is cancelled, then releases it.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Confirm checks with Redis that the lease has not passed to another replica since it was
+// last renewed. Callers run it before writes that must not be repeated by a second leader.
+func (e *Elector) Confirm(ctx context.Context) error {
+	token, ok := e.Leading()
+	if !ok {
+		return ErrNotLeader
+	}
+	held, err := confirmScript.Run(ctx, e.client, []string{e.key}, e.identity, token).Int()
+	if err != nil {
+		return fmt.Errorf("scheduler: confirm leadership: %w", err)
+	}
+	if held != 1 {
+		e.setState(false, token, "", time.Time{})
+		return ErrNotLeader
+	}
+	return nil
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	// The lease runs from when Redis renews it, which is no earlier than the request is sent,
+	// so the local view of it never outlasts the one in Redis.
+	sent := time.Now()
+	res, err := acquireScript.Run(ctx, e.client, []string{e.key, e.tokenKey}, e.identity, e.ttl.Milliseconds()).Slice()
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Warn("scheduler: leader lease renewal failed", zap.Error(err), zap.String("identity", e.identity))
+		}
+		// Leading() expires the lease locally once the TTL passes without a renewal.
+		return
+	}
+	if len(res) != 3 {
+		e.logger.Warn("scheduler: unexpected leader lease reply", zap.Any("reply", res))
+		return
+	}
+	held, _ := res[0].(int64)
+	token, _ := res[1].(int64)
+	holder, _ := res[2].(string)
+	e.setState(held == 1, token, holder, sent)
+}
+
+func (e *Elector) resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	token, ok := e.Leading()
+	if !ok {
+		return
+	}
+	if err := releaseScript.Run(ctx, e.client, []string{e.key}, e.identity).Err(); err != nil {
+		e.logger.Warn("scheduler: release leader lease", zap.Error(err), zap.String("identity", e.identity))
+	}
+	e.setState(false, token, "", time.Time{})
+}
+
+// setState records the lease as seen by Redis. A held lease counts as renewed at renewedAt.
+func (e *Elector) setState(leading bool, token int64, holder string, renewedAt time.Time) {
+	e.mu.Lock()
+	was, prevLeader := e.leading, e.leader
+	e.leading = leading
+	e.token = token
+	e.leader = holder
+	if leading {
+		e.renewedAt = renewedAt
+	}
+	e.mu.Unlock()
+
+	switch {
+	case leading && !was:
+		e.logger.Info("scheduler: acquired leadership",
+			zap.String("identity", e.identity),
+			zap.Int64("fencing_token", token),
+			zap.String("previous_leader", prevLeader))
+		e.transitions.Add(context.Background(), 1, metric.WithAttributes(attribute.String("event", "acquired")))
+	case !leading && was:
+		e.logger.Warn("scheduler: lost leadership",
+			zap.String("identity", e.identity),
+			zap.Int64("fencing_token", token),
+			zap.String("leader", holder))
+		e.transitions.Add(context.Background(), 1, metric.WithAttributes(attribute.String("event", "lost")))
+	case !leading && holder != prevLeader && holder != "":
+		e.logger.Info("scheduler: following leader",
+			zap.String("identity", e.identity),
+			zap.String("leader", holder),
+			zap.Int64("fencing_token", token))
+	}
+}
+
+func (e *Elector) registerMetrics() {
+	meter := otel.Meter("outbound.scheduler")
+
+	var err error
+	e.transitions, err = meter.Int64Counter("scheduler.leader.transitions",
+		metric.WithDescription("Leadership acquisitions and losses by this scheduler replica"))
+	if err != nil {
+		e.logger.Warn("scheduler: register leader transition counter", zap.Error(err))
+		e.transitions = noop.Int64Counter{}
+	}
+
+	// The leader's identity and token change with every takeover, so they are logged and the
+	// token is reported as a value rather than either becoming an attribute.
+	_, err = meter.Int64ObservableGauge("scheduler.leader",
+		metric.WithDescription("1 while this replica holds the scheduler lease"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			var value int64
+			if _, leading := e.Leading(); leading {
+				value = 1
+			}
+			o.Observe(value)
+			return nil
+		}))
+	if err != nil {
+		e.logger.Warn("scheduler: register leader gauge", zap.Error(err))
+	}
+
+	_, err = meter.Int64ObservableGauge("scheduler.leader.fencing_token",
+		metric.WithDescription("Fencing token of the lease while this replica holds it"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			if token, leading := e.Leading(); leading {
+				o.Observe(token)
+			}
+			return nil
+		}))
+	if err != nil {
+		e.logger.Warn("scheduler: register fencing token gauge", zap.Error(err))
+	}
+}
+
+func defaultIdentity() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+func testElectors(t *testing.T, ttl time.Duration, identities ...string) (*miniredis.Miniredis, []*Elector) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	lg := &logger.Logger{Logger: zap.NewNop()}
+	electors := make([]*Elector, 0, len(identities))
+	for _, id := range identities {
+		electors = append(electors, NewElector(client, lg, "test", id, ttl))
+	}
+	return server, electors
+}
+
+func TestElectorAcquireAndRenew(t *testing.T) {
+	ctx := context.Background()
+	server, electors := testElectors(t, 10*time.Second, "a", "b")
+	a, b := electors[0], electors[1]
+
+	a.campaign(ctx)
+	token, ok := a.Leading()
+	if !ok || token != 1 {
+		t.Fatalf("a: Leading() = %d, %v; want 1, true", token, ok)
+	}
+
+	b.campaign(ctx)
+	if _, ok := b.Leading(); ok {
+		t.Fatal("b took a lease held by a")
+	}
+	if b.leader != "a" {
+		t.Fatalf("b sees leader %q, want a", b.leader)
+	}
+
+	// Renewing keeps the token and pushes the expiry out again.
+	server.FastForward(8 * time.Second)
+	a.campaign(ctx)
+	if token, ok := a.Leading(); !ok || token != 1 {
+		t.Fatalf("a after renewal: Leading() = %d, %v; want 1, true", token, ok)
+	}
+	if ttl := server.TTL(a.Lease()); ttl != 10*time.Second {
+		t.Fatalf("lease TTL after renewal = %v, want 10s", ttl)
+	}
+	if err := a.Confirm(ctx); err != nil {
+		t.Fatalf("a.Confirm: %v", err)
+	}
+}
+
+func TestElectorTakeoverRaisesToken(t *testing.T) {
+	ctx := context.Background()
+	server, electors := testElectors(t, 10*time.Second, "a", "b")
+	a, b := electors[0], electors[1]
+
+	a.campaign(ctx)
+	server.FastForward(11 * time.Second)
+	b.campaign(ctx)
+
+	token, ok := b.Leading()
+	if !ok || token != 2 {
+		t.Fatalf("b: Leading() = %d, %v; want 2, true", token, ok)
+	}
+	// a has not renewed yet, so it still believes it leads until Confirm asks Redis.
+	if err := a.Confirm(ctx); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("a.Confirm after takeover = %v, want ErrNotLeader", err)
+	}
+	if _, ok := a.Leading(); ok {
+		t.Fatal("a still leading after a failed Confirm")
+	}
+}
+
+func TestElectorRelease(t *testing.T) {
+	ctx := context.Background()
+	server, electors := testElectors(t, 10*time.Second, "a", "b")
+	a, b := electors[0], electors[1]
+
+	a.campaign(ctx)
+	b.resign() // not the holder: must leave a's lease alone
+	if !server.Exists(a.Lease()) {
+		t.Fatal("b released a lease it did not hold")
+	}
+
+	a.resign()
+	if server.Exists(a.Lease()) {
+		t.Fatal("lease still present after its holder resigned")
+	}
+	if _, ok := a.Leading(); ok {
+		t.Fatal("a still leading after resigning")
+	}
+
+	b.campaign(ctx)
+	if token, ok := b.Leading(); !ok || token != 2 {
+		t.Fatalf("b after release: Leading() = %d, %v; want 2, true", token, ok)
+	}
+}
+
+// slowReplies delays every Redis reply, standing in for a slow network.
+type slowReplies time.Duration
+
+func (slowReplies) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (d slowReplies) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		time.Sleep(time.Duration(d))
+		return err
+	}
+}
+
+func (slowReplies) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+var _ redis.Hook = slowReplies(0)
+
+func TestElectorLeaseRunsFromRenewalRequest(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	client.AddHook(slowReplies(100 * time.Millisecond))
+	e := NewElector(client, &logger.Logger{Logger: zap.NewNop()}, "test", "a", 10*time.Second)
+
+	sent := time.Now()
+	e.campaign(context.Background())
+	if _, ok := e.Leading(); !ok {
+		t.Fatal("a did not take the free lease")
+	}
+	// A reply that arrives late must not push the local expiry past the one Redis set.
+	if e.renewedAt.Sub(sent) >= 100*time.Millisecond {
+		t.Fatalf("lease counted as renewed %v after the request was sent, want before the reply", e.renewedAt.Sub(sent))
+	}
+}
@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+)
+
+// Reasons recorded on targets the reconciler moves.
+const (
+	reasonNoCall      = "reconciler: no call created"
+	reasonCallMissing = "reconciler: call record missing"
+)
+
+// ReconcileReport summarises one reconciliation pass.
+type ReconcileReport struct {
+	Scanned  int
+	Requeued int
+	Failed   int
+	Synced   int
+	Errors   int
+}
+
+// Reconciler repairs targets left in queued or dialing because a dispatch, a call record or a
+// status report went missing. Targets without a call are put back to pending; calls stuck in
+// queued or dialing are failed through the status topic so the usual retry policy applies.
+type Reconciler struct {
+	container *app.Container
+	targets   repository.CampaignTargetRepository
+	calls     repository.CallStore
+	campaigns campaignGetter
+	status    statusPublisher
+	outcomes  metric.Int64Counter
+}
+
+// campaignGetter loads a campaign; *campaign.Service implements it.
+type campaignGetter interface {
+	Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error)
+}
+
+// statusPublisher reports call outcomes; *queue.StatusPublisher implements it.
+type statusPublisher interface {
+	PublishStatus(ctx context.Context, msg queue.StatusMessage) error
+}
+
+// NewReconciler constructs a reconciler.
+func NewReconciler(container *app.Container) *Reconciler {
+	r := &Reconciler{
+		container: container,
+		targets:   container.Repositories().Targets,
+		calls:     container.Repositories().CallStore,
+		campaigns: container.Services().Campaign,
+		status:    container.Dispatchers().StatusPublisher,
+	}
+	var err error
+	r.outcomes, err = otel.Meter("outbound.scheduler").Int64Counter("reconciler.targets",
+		metric.WithDescription("Stale targets handled by the reconciler, by outcome"))
+	if err != nil {
+		container.Logger.Warn("reconciler: register outcome counter", zap.Error(err))
+		r.outcomes = noop.Int64Counter{}
+	}
+	return r
+}
+
+// Run performs one pass over targets that have not moved for longer than staleAfter.
+func (r *Reconciler) Run(ctx context.Context, staleAfter time.Duration, limit int) (ReconcileReport, error) {
+	logger := r.container.Logger
+	ctx = common.WithActor(ctx, common.ActorScheduler)
+
+	var report ReconcileReport
+	now := time.Now().UTC()
+	stale, err := r.targets.ListStale(ctx, []string{domain.TargetStateQueued, domain.TargetStateDialing}, now.Add(-staleAfter), limit)
+	if err != nil {
+		return report, fmt.Errorf("reconciler: list stale targets: %w", err)
+	}
+	report.Scanned = len(stale)
+
+	campaigns := make(map[uuid.UUID]*domain.Campaign)
+	for _, target := range stale {
+		outcome, err := r.reconcile(ctx, target, campaigns, now)
+		if errors.Is(err, repository.ErrFenced) {
+			return report, fmt.Errorf("reconciler: %w", err)
+		}
+		if err != nil {
+			report.Errors++
+			outcome = "error"
+			logger.Error("reconciler: reconcile target", zap.Error(err),
+				zap.String("campaign_id", target.CampaignID.String()),
+				zap.String("target_id", target.ID.String()))
+		}
+		switch outcome {
+		case "requeued":
+			report.Requeued++
+		case "failed":
+			report.Failed++
+		case "synced":
+			report.Synced++
+		}
+		if outcome != "" {
+			r.outcomes.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+		}
+	}
+
+	logger.Info("reconciler: run finished",
+		zap.Int("scanned", report.Scanned),
+		zap.Int("requeued", report.Requeued),
+		zap.Int("failed", report.Failed),
+		zap.Int("synced", report.Synced),
+		zap.Int("errors", report.Errors),
+		zap.Duration("stale_after", staleAfter))
+	return report, nil
+}
+
+// reconcile handles a single stale target and returns the outcome label, or "" when the
+// target moved on by itself in the meantime.
+func (r *Reconciler) reconcile(ctx context.Context, target repository.CampaignTargetRecord, campaigns map[uuid.UUID]*domain.Campaign, now time.Time) (string, error) {
+	if target.CallID == nil {
+		return r.requeue(ctx, target, reasonNoCall)
+	}
+
+	call, err := r.calls.GetCall(ctx, *target.CallID)
+	if errors.Is(err, repository.ErrNotFound) {
+		if target.State == domain.TargetStateQueued {
+			return r.requeue(ctx, target, reasonCallMissing)
+		}
+		// The number may already have been dialled, so it is not offered again.
+		if err := r.targets.SetStateWithReason(ctx, target.CampaignID, []uuid.UUID{target.ID}, domain.TargetStateFailed, reasonCallMissing); err != nil {
+			return "", err
+		}
+		return "failed", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get call %s: %w", target.CallID, err)
+	}
+
+	switch call.Status {
+	case domain.CallStatusQueued, domain.CallStatusDialing, domain.CallStatusPending:
+		return r.failCall(ctx, target, call, campaigns, now)
+	}
+
+	// The call finished but its status report never reached the target.
+	state := domain.TargetStateForCall(call.Status, false)
+	if state == "" {
+		return "", nil
+	}
+	update := repository.TargetCallUpdate{
+		CampaignID: target.CampaignID,
+		TargetID:   target.ID,
+		CallID:     call.ID,
+		State:      state,
+		Attempt:    call.AttemptCount,
+		OccurredAt: call.UpdatedAt,
+		OnlyFrom:   []string{domain.TargetStateQueued, domain.TargetStateDialing},
+	}
+	ok, err := r.targets.ApplyCallUpdate(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", nil
+	}
+	return "synced", nil
+}
+
+func (r *Reconciler) requeue(ctx context.Context, target repository.CampaignTargetRecord, reason string) (string, error) {
+	n, err := r.targets.Requeue(ctx, target.CampaignID, []uuid.UUID{target.ID}, reason)
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", nil
+	}
+	return "requeued", nil
+}
+
+// failCall reports the stuck attempt as failed. The status worker then retries it or fails the
+// target according to the campaign's retry policy. The failure is first recorded on the stored
+// call, so a dispatch for the attempt that is still in flight is dropped by the call worker
+// instead of dialing the number a second time. Should the report then not go out, the next pass
+// finds the call failed and fails its target.
+func (r *Reconciler) failCall(ctx context.Context, target repository.CampaignTargetRecord, call *domain.Call, campaigns map[uuid.UUID]*domain.Campaign, now time.Time) (string, error) {
+	campaign, ok := campaigns[target.CampaignID]
+	if !ok {
+		var err error
+		campaign, err = r.campaigns.Get(ctx, target.CampaignID)
+		if err != nil {
+			return "", fmt.Errorf("get campaign: %w", err)
+		}
+		campaigns[target.CampaignID] = campaign
+	}
+
+	attempt := stuckAttempt(call)
+	retryable := attempt < campaign.RetryPolicy.MaxAttempts && !campaign.Status.IsTerminal()
+	reason := fmt.Sprintf("reconciler: call stuck in %s", call.Status)
+	if err := r.calls.UpdateCallStatus(ctx, call.ID, domain.CallStatusFailed, attempt, &reason); err != nil {
+		return "", fmt.Errorf("fail call %s: %w", call.ID, err)
+	}
+
+	msg := queue.StatusMessage{
+		CallID:        call.ID,
+		CampaignID:    target.CampaignID,
+		TargetID:      target.ID,
+		PhoneNumber:   call.PhoneNumber,
+		Status:        string(domain.CallStatusFailed),
+		Attempt:       attempt,
+		Retryable:     retryable,
+		ConfigVersion: campaign.ConfigVersion,
+		Error:         reason,
+		OccurredAt:    now,
+		Metadata:      target.Payload,
+	}
+	if retryable {
+		msg.NextAttempt = &now
+	}
+	if err := r.status.PublishStatus(ctx, msg); err != nil {
+		return "", err
+	}
+	if retryable {
+		return "requeued", nil
+	}
+	return "failed", nil
+}
+
+// stuckAttempt returns the attempt a stuck call was on. A queued call never started its
+// attempt; a dialing one is already counted.
+func stuckAttempt(call *domain.Call) int {
+	if call.Status == domain.CallStatusDialing {
+		return max(call.AttemptCount, 1)
+	}
+	return call.AttemptCount + 1
+}
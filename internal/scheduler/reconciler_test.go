@@ -0,0 +1,300 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+func TestStuckAttempt(t *testing.T) {
+	cases := []struct {
+		call domain.Call
+		want int
+	}{
+		{call: domain.Call{Status: domain.CallStatusQueued}, want: 1},
+		{call: domain.Call{Status: domain.CallStatusQueued, AttemptCount: 2}, want: 3},
+		{call: domain.Call{Status: domain.CallStatusPending, AttemptCount: 1}, want: 2},
+		{call: domain.Call{Status: domain.CallStatusDialing, AttemptCount: 2}, want: 2},
+		{call: domain.Call{Status: domain.CallStatusDialing}, want: 1},
+	}
+
+	for _, tc := range cases {
+		if got := stuckAttempt(&tc.call); got != tc.want {
+			t.Errorf("%s call with %d attempts: got attempt %d, want %d", tc.call.Status, tc.call.AttemptCount, got, tc.want)
+		}
+	}
+}
+
+// The fakes embed the repository interfaces so that only the methods the reconciler calls need
+// an implementation.
+
+type staleTargets struct {
+	repository.CampaignTargetRepository
+	stale     []repository.CampaignTargetRecord
+	requeued  map[uuid.UUID]string
+	failed    map[uuid.UUID]string
+	updates   []repository.TargetCallUpdate
+	applyMiss bool
+}
+
+func (f *staleTargets) ListStale(context.Context, []string, time.Time, int) ([]repository.CampaignTargetRecord, error) {
+	return f.stale, nil
+}
+
+func (f *staleTargets) Requeue(_ context.Context, _ uuid.UUID, ids []uuid.UUID, reason string) (int64, error) {
+	for _, id := range ids {
+		f.requeued[id] = reason
+	}
+	return int64(len(ids)), nil
+}
+
+func (f *staleTargets) SetStateWithReason(_ context.Context, _ uuid.UUID, ids []uuid.UUID, state, reason string) error {
+	for _, id := range ids {
+		f.failed[id] = state + ": " + reason
+	}
+	return nil
+}
+
+func (f *staleTargets) ApplyCallUpdate(_ context.Context, update repository.TargetCallUpdate) (bool, error) {
+	if f.applyMiss {
+		return false, nil
+	}
+	f.updates = append(f.updates, update)
+	return true, nil
+}
+
+type storedCalls struct {
+	repository.CallStore
+	calls map[uuid.UUID]*domain.Call
+}
+
+func (f *storedCalls) GetCall(_ context.Context, id uuid.UUID) (*domain.Call, error) {
+	call, ok := f.calls[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *call
+	return &copied, nil
+}
+
+func (f *storedCalls) UpdateCallStatus(_ context.Context, id uuid.UUID, status domain.CallStatus, attempt int, _ *string) error {
+	f.calls[id].Status, f.calls[id].AttemptCount = status, attempt
+	return nil
+}
+
+type campaignsByID map[uuid.UUID]*domain.Campaign
+
+func (f campaignsByID) Get(_ context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	campaign, ok := f[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return campaign, nil
+}
+
+type publishedStatuses []queue.StatusMessage
+
+func (f *publishedStatuses) PublishStatus(_ context.Context, msg queue.StatusMessage) error {
+	*f = append(*f, msg)
+	return nil
+}
+
+type testReconciler struct {
+	*Reconciler
+	targets   *staleTargets
+	calls     *storedCalls
+	campaigns campaignsByID
+	published *publishedStatuses
+}
+
+func newTestReconciler() *testReconciler {
+	tr := &testReconciler{
+		targets:   &staleTargets{requeued: make(map[uuid.UUID]string), failed: make(map[uuid.UUID]string)},
+		calls:     &storedCalls{calls: make(map[uuid.UUID]*domain.Call)},
+		campaigns: make(campaignsByID),
+		published: &publishedStatuses{},
+	}
+	tr.Reconciler = &Reconciler{
+		container: &app.Container{Logger: &logger.Logger{Logger: zap.NewNop()}},
+		targets:   tr.targets,
+		calls:     tr.calls,
+		campaigns: tr.campaigns,
+		status:    tr.published,
+		outcomes:  noop.Int64Counter{},
+	}
+	return tr
+}
+
+// addStale records a stale target of campaign in state, attached to call when it is not nil.
+func (tr *testReconciler) addStale(campaign *domain.Campaign, state string, call *domain.Call) repository.CampaignTargetRecord {
+	tr.campaigns[campaign.ID] = campaign
+	target := repository.CampaignTargetRecord{ID: uuid.New(), CampaignID: campaign.ID, State: state}
+	if call != nil {
+		call.ID = uuid.New()
+		tr.calls.calls[call.ID] = call
+		target.CallID = &call.ID
+	}
+	tr.targets.stale = append(tr.targets.stale, target)
+	return target
+}
+
+func TestReconcileRequeuesTargetsWithoutCall(t *testing.T) {
+	tr := newTestReconciler()
+	campaign := &domain.Campaign{ID: uuid.New(), Status: domain.CampaignStatusInProgress}
+	noCall := tr.addStale(campaign, domain.TargetStateQueued, nil)
+	missing := tr.addStale(campaign, domain.TargetStateQueued, &domain.Call{Status: domain.CallStatusQueued})
+	delete(tr.calls.calls, *missing.CallID)
+
+	report, err := tr.Run(context.Background(), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Scanned != 2 || report.Requeued != 2 || report.Failed != 0 {
+		t.Fatalf("report = %+v, want 2 scanned and requeued", report)
+	}
+	if got := tr.targets.requeued[noCall.ID]; got != reasonNoCall {
+		t.Errorf("target without call requeued with %q, want %q", got, reasonNoCall)
+	}
+	if got := tr.targets.requeued[missing.ID]; got != reasonCallMissing {
+		t.Errorf("target with missing call requeued with %q, want %q", got, reasonCallMissing)
+	}
+	if len(*tr.published) != 0 {
+		t.Errorf("published %d statuses for requeued targets, want none", len(*tr.published))
+	}
+}
+
+func TestReconcileFailsDialingTargetWithMissingCall(t *testing.T) {
+	tr := newTestReconciler()
+	campaign := &domain.Campaign{ID: uuid.New(), Status: domain.CampaignStatusInProgress}
+	target := tr.addStale(campaign, domain.TargetStateDialing, &domain.Call{Status: domain.CallStatusDialing})
+	delete(tr.calls.calls, *target.CallID)
+
+	report, err := tr.Run(context.Background(), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Failed != 1 || report.Requeued != 0 {
+		t.Fatalf("report = %+v, want the target failed", report)
+	}
+	if got, want := tr.targets.failed[target.ID], domain.TargetStateFailed+": "+reasonCallMissing; got != want {
+		t.Errorf("target set to %q, want %q", got, want)
+	}
+}
+
+func TestReconcileFailsStuckCalls(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    domain.CampaignStatus
+		call      domain.Call
+		attempt   int
+		retryable bool
+		outcome   string
+	}{
+		{name: "queued call with attempts left", status: domain.CampaignStatusInProgress, call: domain.Call{Status: domain.CallStatusQueued}, attempt: 1, retryable: true, outcome: "requeued"},
+		{name: "dialing call on its last attempt", status: domain.CampaignStatusInProgress, call: domain.Call{Status: domain.CallStatusDialing, AttemptCount: 3}, attempt: 3, outcome: "failed"},
+		{name: "queued call of a cancelled campaign", status: domain.CampaignStatusCancelled, call: domain.Call{Status: domain.CallStatusQueued}, attempt: 1, outcome: "failed"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := newTestReconciler()
+			campaign := &domain.Campaign{
+				ID:            uuid.New(),
+				Status:        tc.status,
+				ConfigVersion: 4,
+				RetryPolicy:   domain.RetryPolicy{MaxAttempts: 3},
+			}
+			call := tc.call
+			target := tr.addStale(campaign, domain.TargetStateQueued, &call)
+
+			report, err := tr.Run(context.Background(), time.Minute, 10)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if got := report.Requeued + report.Failed; got != 1 {
+				t.Fatalf("report = %+v, want one target handled", report)
+			}
+			if tc.outcome == "requeued" && report.Requeued != 1 || tc.outcome == "failed" && report.Failed != 1 {
+				t.Errorf("report = %+v, want the target %s", report, tc.outcome)
+			}
+
+			stored := tr.calls.calls[call.ID]
+			if stored.Status != domain.CallStatusFailed || stored.AttemptCount != tc.attempt {
+				t.Errorf("stored call is %s on attempt %d, want failed on attempt %d", stored.Status, stored.AttemptCount, tc.attempt)
+			}
+			if len(*tr.published) != 1 {
+				t.Fatalf("published %d statuses, want 1", len(*tr.published))
+			}
+			msg := (*tr.published)[0]
+			if msg.CallID != call.ID || msg.TargetID != target.ID || msg.Status != string(domain.CallStatusFailed) {
+				t.Errorf("published %+v, want call %s of target %s failed", msg, call.ID, target.ID)
+			}
+			if msg.Attempt != tc.attempt || msg.Retryable != tc.retryable || msg.ConfigVersion != campaign.ConfigVersion {
+				t.Errorf("published attempt %d retryable %v config version %d, want %d %v %d",
+					msg.Attempt, msg.Retryable, msg.ConfigVersion, tc.attempt, tc.retryable, campaign.ConfigVersion)
+			}
+			if (msg.NextAttempt != nil) != tc.retryable {
+				t.Errorf("next attempt = %v, want one only when retryable", msg.NextAttempt)
+			}
+			if len(tr.targets.requeued) != 0 || len(tr.targets.failed) != 0 {
+				t.Errorf("target moved directly; it should be left to the status worker")
+			}
+		})
+	}
+}
+
+func TestReconcileSyncsFinishedCalls(t *testing.T) {
+	tr := newTestReconciler()
+	campaign := &domain.Campaign{ID: uuid.New(), Status: domain.CampaignStatusInProgress}
+	finishedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	target := tr.addStale(campaign, domain.TargetStateDialing, &domain.Call{
+		Status:       domain.CallStatusCompleted,
+		AttemptCount: 2,
+		UpdatedAt:    finishedAt,
+	})
+
+	report, err := tr.Run(context.Background(), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Synced != 1 {
+		t.Fatalf("report = %+v, want the target synced", report)
+	}
+	if len(tr.targets.updates) != 1 {
+		t.Fatalf("applied %d updates, want 1", len(tr.targets.updates))
+	}
+	update := tr.targets.updates[0]
+	want := domain.TargetStateForCall(domain.CallStatusCompleted, false)
+	if update.TargetID != target.ID || update.CallID != *target.CallID || update.State != want {
+		t.Errorf("update = %+v, want target %s set to %s", update, target.ID, want)
+	}
+	if update.Attempt != 2 || !update.OccurredAt.Equal(finishedAt) {
+		t.Errorf("update attempt %d at %v, want 2 at %v", update.Attempt, update.OccurredAt, finishedAt)
+	}
+	if len(update.OnlyFrom) != 2 {
+		t.Errorf("update applies from %v, want only queued and dialing", update.OnlyFrom)
+	}
+	if len(*tr.published) != 0 {
+		t.Errorf("published %d statuses for a finished call, want none", len(*tr.published))
+	}
+
+	// A target that moved on by itself meanwhile is not counted.
+	tr.targets.applyMiss = true
+	report, err = tr.Run(context.Background(), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if report.Synced != 0 || report.Errors != 0 {
+		t.Errorf("report = %+v, want nothing synced", report)
+	}
+}
@@ -2,7 +2,7 @@ package scheduler
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,19 +12,49 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/compliance"
 	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 	callsvc "github.com/acme/outbound-call-campaign/internal/service/call"
-	"github.com/segmentio/kafka-go"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+	dncsvc "github.com/acme/outbound-call-campaign/internal/service/dnc"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
 )
 
-// Scheduler periodically schedules calls respecting business hours.
+// Scheduler periodically schedules calls respecting business hours. Only the replica holding
+// the leader lease dispatches; the others stand by.
 type Scheduler struct {
-	container *app.Container
+	container  *app.Container
+	elector    *Elector
+	reconciler *Reconciler
+	fairShare  *fairShare
+	// recipientWindow is the daily window applied in the recipient's time zone to
+	// recipient-local-time campaigns without business hours.
+	recipientWindow domain.DailyWindow
 }
 
 // New constructs a scheduler.
 func New(container *app.Container) *Scheduler {
-	return &Scheduler{container: container}
+	cfg := container.Config.Scheduler
+	elector := NewElector(container.Redis.Inner(), container.Logger, cfg.LockKeyPrefix, "", cfg.LockTTL)
+	return &Scheduler{
+		container:       container,
+		elector:         elector,
+		reconciler:      NewReconciler(container),
+		fairShare:       newFairShare(cfg.FairShareQuantum),
+		recipientWindow: recipientWindow(container.Logger, cfg.RecipientWindowStart, cfg.RecipientWindowEnd),
+	}
+}
+
+// recipientWindow parses the configured recipient calling window. An unset or invalid window
+// leaves recipient-local-time campaigns without business hours open all day.
+func recipientWindow(lg *logger.Logger, start, end string) domain.DailyWindow {
+	window, err := domain.ParseDailyWindow(start, end)
+	if err != nil {
+		lg.Warn("scheduler: invalid recipient window, ignoring", zap.Error(err))
+		return domain.DailyWindow{}
+	}
+	return window
 }
 
 // Run executes the scheduling loop until cancelled.
@@ -38,20 +68,61 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	reconcileInterval := cfg.Scheduler.ReconcileInterval
+	if reconcileInterval <= 0 {
+		reconcileInterval = time.Minute
+	}
+	reconcileTicker := time.NewTicker(reconcileInterval)
+	defer reconcileTicker.Stop()
+
+	s.container.Logger.Info("scheduler: starting", zap.String("identity", s.elector.Identity()))
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		s.elector.Run(ctx)
+	}()
+	defer func() { <-electorDone }()
+
 	for {
-		if err := s.tick(ctx); err != nil && ctx.Err() == nil {
-			s.container.Logger.Error("scheduler tick failed", zap.Error(err))
+		if token, ok := s.elector.Leading(); ok {
+			if err := s.tick(ctx, token); err != nil && ctx.Err() == nil {
+				s.container.Logger.Error("scheduler tick failed", zap.Error(err))
+			}
+		} else {
+			s.container.Logger.Debug("scheduler: standing by", zap.String("identity", s.elector.Identity()))
 		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
+		case <-reconcileTicker.C:
+			if token, ok := s.elector.Leading(); ok {
+				s.reconcile(ctx, token)
+			}
+			continue
 		}
 	}
 }
 
-func (s *Scheduler) tick(ctx context.Context) error {
+// reconcile repairs targets that have sat in queued or dialing for longer than the configured threshold.
+func (s *Scheduler) reconcile(ctx context.Context, fencingToken int64) {
+	cfg := s.container.Config.Scheduler
+	staleAfter := cfg.ReconcileStaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 15 * time.Minute
+	}
+	ctx, err := s.fence(ctx, fencingToken)
+	if err != nil {
+		s.container.Logger.Warn("scheduler: raise fence for reconcile", zap.Error(err), zap.Int64("fencing_token", fencingToken))
+		return
+	}
+	if _, err := s.reconciler.Run(ctx, staleAfter, cfg.MaxBatchSize); err != nil && ctx.Err() == nil {
+		s.container.Logger.Error("scheduler: reconcile failed", zap.Error(err))
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, fencingToken int64) error {
 	services := s.container.Services()
 	repos := s.container.Repositories()
 	callService := services.Call
@@ -61,24 +132,25 @@ func (s *Scheduler) tick(ctx context.Context) error {
 	logger.Info("scheduler: tick started")
 
 	tracer := otel.Tracer("outbound.scheduler")
-	sctx, span := tracer.Start(ctx, "scheduler.tick")
+	sctx, span := tracer.Start(common.WithActor(ctx, common.ActorScheduler), "scheduler.tick", trace.WithAttributes(
+		attribute.String("scheduler.identity", s.elector.Identity()),
+		attribute.Int64("scheduler.fencing_token", fencingToken),
+	))
 	defer span.End()
 
-	// Check for pending retries first - failed calls should be retried before new calls
-	hasPendingRetries, err := s.hasPendingRetries(sctx)
+	// Every Postgres write below carries the fencing token, so none lands once a newer leader
+	// has raised the fence.
+	sctx, err := s.fence(sctx, fencingToken)
 	if err != nil {
 		span.RecordError(err)
-		logger.Warn("scheduler: failed to check pending retries", zap.Error(err))
-		// Continue anyway, but log the issue
+		if errors.Is(err, repository.ErrFenced) {
+			logger.Warn("scheduler: fencing token superseded, standing by", zap.String("identity", s.elector.Identity()), zap.Int64("fencing_token", fencingToken))
+			return nil
+		}
+		return err
 	}
 
-	logger.Debug("scheduler: checked for pending retries", zap.Bool("has_pending", hasPendingRetries))
-
-	if hasPendingRetries {
-		span.SetAttributes(attribute.Bool("retries.pending", true))
-		logger.Info("scheduler: skipping new call dispatch due to pending retries - maintaining fairness")
-		return nil // Skip this tick to allow retries to be processed first
-	}
+	s.applyCampaignWindows(sctx, time.Now().UTC())
 
 	nowUTC := time.Now().UTC()
 	campaigns, err := services.Campaign.ListByStatus(sctx, domain.CampaignStatusInProgress, s.campaignFetchLimit())
@@ -89,57 +161,143 @@ func (s *Scheduler) tick(ctx context.Context) error {
 	span.SetAttributes(attribute.Int("campaign.count", len(campaigns)))
 	logger.Info("scheduler: found campaigns", zap.Int("count", len(campaigns)), zap.Time("now", nowUTC))
 
+	// Size every eligible campaign's demand first, then let the fair-share planner decide
+	// the order and how much of the tick budget each one gets.
+	byID := make(map[uuid.UUID]*domain.Campaign, len(campaigns))
+	zonesByID := make(map[uuid.UUID][]string)
+	requests := make([]shareRequest, 0, len(campaigns))
 	for _, campaign := range campaigns {
+		if campaign.EndAt != nil && !nowUTC.Before(*campaign.EndAt) {
+			logger.Debug("scheduler: campaign past scheduled end", zap.String("campaign_id", campaign.ID.String()))
+			continue
+		}
+
+		if campaign.RecipientLocalTime {
+			// Windows are read in each recipient's zone, so only targets in zones that are
+			// open now may be claimed. Nothing is staged ahead of an opening.
+			zones := s.openZones(sctx, campaign, nowUTC)
+			if len(zones) == 0 {
+				logger.Debug("scheduler: no recipient time zone open", zap.String("campaign_id", campaign.ID.String()))
+				s.completeIfDrained(sctx, campaign)
+				continue
+			}
+			zonesByID[campaign.ID] = zones
+		} else if !isWithinBusinessHours(nowUTC, campaign) {
+			logger.Debug("scheduler: campaign outside business hours", zap.String("campaign_id", campaign.ID.String()))
+			if opensAt, ok := stageOpening(campaign, nowUTC, s.container.Config.Scheduler.LookAhead); ok {
+				s.stage(sctx, campaign, opensAt)
+			}
+			s.completeIfDrained(sctx, campaign)
+			continue
+		}
+
+		// Only queue what the campaign's free slots can take; its outstanding retries go first.
+		demand := s.batchSize(sctx, campaign)
+		if demand <= 0 {
+			logger.Debug("scheduler: no capacity for new targets", zap.String("campaign_id", campaign.ID.String()))
+			continue
+		}
+		byID[campaign.ID] = campaign
+		requests = append(requests, shareRequest{ID: campaign.ID, Priority: campaign.Priority, Weight: campaign.Weight, Demand: demand})
+	}
+
+	budget := s.container.Config.Scheduler.TickBudget
+	grants := s.fairShare.allocate(requests, budget)
+	order := make([]string, 0, len(grants))
+	for _, g := range grants {
+		order = append(order, g.ID.String())
+	}
+	span.SetAttributes(
+		attribute.Int("scheduler.tick_budget", budget),
+		attribute.StringSlice("scheduler.order", order),
+	)
+
+	for position, grant := range grants {
+		campaign := byID[grant.ID]
 		cctx, cspan := tracer.Start(sctx, "scheduler.campaign", trace.WithAttributes(
 			attribute.String("campaign.id", campaign.ID.String()),
 			attribute.Int("max_concurrency", campaign.MaxConcurrentCalls),
+			attribute.Int("fair_share.position", position),
+			attribute.Int("fair_share.priority", grant.Priority),
+			attribute.Int("fair_share.weight", grant.Weight),
+			attribute.Int("fair_share.demand", grant.Demand),
+			attribute.Int("fair_share.granted", grant.Amount),
+			attribute.Int("fair_share.deficit", grant.Deficit),
 		))
 
-		logger.Debug("scheduler: processing campaign", zap.String("campaign_id", campaign.ID.String()), zap.String("status", string(campaign.Status)))
+		logger.Debug("scheduler: processing campaign", zap.String("campaign_id", campaign.ID.String()), zap.Int("position", position), zap.Int("granted", grant.Amount), zap.Int("demand", grant.Demand))
 
-		if !isWithinBusinessHours(nowUTC, campaign) {
-			logger.Debug("scheduler: campaign outside business hours", zap.String("campaign_id", campaign.ID.String()))
+		if grant.Amount <= 0 {
+			logger.Debug("scheduler: campaign deferred by fair share", zap.String("campaign_id", campaign.ID.String()), zap.Int("deficit", grant.Deficit))
 			cspan.End()
 			continue
 		}
 
-		targets, err := repos.Targets.NextBatchForScheduling(cctx, campaign.ID, s.container.Config.Scheduler.MaxBatchSize)
+		// Fence the batch: if another replica has taken the lease since it was last renewed,
+		// leave the campaign to it.
+		if err := s.elector.Confirm(cctx); err != nil {
+			cspan.RecordError(err)
+			cspan.End()
+			if errors.Is(err, ErrNotLeader) {
+				logger.Warn("scheduler: leadership lost mid-tick, stopping", zap.String("identity", s.elector.Identity()), zap.Int64("fencing_token", fencingToken))
+				return nil
+			}
+			return err
+		}
+
+		batchSize := grant.Amount
+		targets, err := repos.Targets.ClaimBatch(cctx, campaign.ID, s.elector.Identity(), batchSize, s.claimTTL(), zonesByID[campaign.ID])
+		if errors.Is(err, repository.ErrFenced) {
+			cspan.RecordError(err)
+			cspan.End()
+			logger.Warn("scheduler: fencing token superseded mid-tick, stopping", zap.String("identity", s.elector.Identity()), zap.Int64("fencing_token", fencingToken))
+			return nil
+		}
 		if err != nil {
 			cspan.RecordError(err)
-			logger.Error("scheduler: fetch targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+			logger.Error("scheduler: claim targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
 			cspan.End()
 			continue
 		}
-		cspan.SetAttributes(attribute.Int("targets.fetched", len(targets)))
-		logger.Info("scheduler: fetched targets for campaign", zap.String("campaign_id", campaign.ID.String()), zap.Int("target_count", len(targets)), zap.Int("max_batch_size", s.container.Config.Scheduler.MaxBatchSize))
+		cspan.SetAttributes(attribute.Int("targets.claimed", len(targets)))
+		logger.Info("scheduler: claimed targets for campaign", zap.String("campaign_id", campaign.ID.String()), zap.Int("target_count", len(targets)), zap.Int("batch_size", batchSize))
 		if len(targets) == 0 {
+			if s.completeIfDrained(cctx, campaign) {
+				cspan.SetAttributes(attribute.Bool("campaign.completed", true))
+			}
 			cspan.End()
 			continue
 		}
 
-		ids := make([]uuid.UUID, 0, len(targets))
-		for _, t := range targets {
-			ids = append(ids, t.ID)
-		}
-
-		scheduledAt := time.Now().UTC()
-		if err := repos.Targets.MarkScheduled(cctx, campaign.ID, ids, scheduledAt); err != nil {
-			cspan.RecordError(err)
-			logger.Error("scheduler: mark scheduled", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		targets = s.filterSuppressed(cctx, campaign, targets)
+		targets = s.filterQuietHours(cctx, campaign, targets, time.Now().UTC())
+		cspan.SetAttributes(attribute.Int("targets.dialable", len(targets)))
+		if len(targets) == 0 {
 			cspan.End()
 			continue
 		}
 
-		var failed []uuid.UUID
+		var failed, suppressed []uuid.UUID
 		logger.Info("scheduler: dispatching calls", zap.String("campaign_id", campaign.ID.String()), zap.Int("target_count", len(targets)))
 		for _, target := range targets {
 			input := callsvc.TriggerCallInput{
 				CampaignID:  campaign.ID,
+				TargetID:    target.ID,
+				ClaimOwner:  s.elector.Identity(),
 				PhoneNumber: target.PhoneNumber,
+				TimeZone:    recipientZone(campaign, target),
+				Attempt:     target.AttemptCount + 1,
 				Metadata:    target.Payload,
 			}
 			call, err := callService.TriggerCall(cctx, input)
-			if err != nil {
+			if errors.Is(err, callsvc.ErrSuppressed) {
+				suppressed = append(suppressed, target.ID)
+				logger.Info("scheduler: target suppressed at dispatch", zap.String("campaign_id", campaign.ID.String()), zap.String("phone", target.PhoneNumber))
+			} else if errors.Is(err, repository.ErrConflict) {
+				// The campaign stopped or the target was called elsewhere since it was claimed;
+				// it is left as it is rather than reset.
+				logger.Info("scheduler: target no longer dialable", zap.Error(err), zap.String("campaign_id", campaign.ID.String()), zap.String("target_id", target.ID.String()))
+			} else if err != nil {
 				failed = append(failed, target.ID)
 				cspan.RecordError(err)
 				logger.Error("scheduler: trigger call failed", zap.Error(err), zap.String("campaign_id", campaign.ID.String()), zap.String("phone", target.PhoneNumber))
@@ -149,111 +307,335 @@ func (s *Scheduler) tick(ctx context.Context) error {
 		}
 
 		if len(failed) > 0 {
-			if err := repos.Targets.SetState(cctx, campaign.ID, failed, "pending"); err != nil {
+			if err := repos.Targets.SetState(cctx, campaign.ID, failed, domain.TargetStatePending); err != nil {
 				cspan.RecordError(err)
 				logger.Error("scheduler: reset failed targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
 			}
 		}
+		if len(suppressed) > 0 {
+			if err := repos.Targets.SetStateWithReason(cctx, campaign.ID, suppressed, domain.TargetStateSuppressed, dncsvc.DefaultReason); err != nil {
+				cspan.RecordError(err)
+				logger.Error("scheduler: mark suppressed targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+			}
+		}
 		cspan.End()
 	}
 
 	return nil
 }
 
-// hasPendingRetries checks if any campaigns have recent failures that should be retried first.
-// This ensures failed calls are retried before new calls are dispatched, maintaining fairness.
-func (s *Scheduler) hasPendingRetries(ctx context.Context) (bool, error) {
-	cfg := s.container.Config
-	kafkaClient := s.container.Kafka
+// filterSuppressed moves targets on the do-not-call list to the suppressed state and returns the rest.
+// If the list cannot be consulted no target is returned, so nothing is dialled unchecked.
+func (s *Scheduler) filterSuppressed(ctx context.Context, campaign *domain.Campaign, targets []repository.CampaignTargetRecord) []repository.CampaignTargetRecord {
 	logger := s.container.Logger
+	phones := make([]string, 0, len(targets))
+	for _, t := range targets {
+		phones = append(phones, t.PhoneNumber)
+	}
+
+	matches, err := s.container.Services().DNC.Filter(ctx, campaign.ID, phones)
+	if err != nil {
+		logger.Error("scheduler: check suppression list", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return nil
+	}
+	if len(matches) == 0 {
+		return targets
+	}
+
+	dialable, byReason := splitSuppressed(targets, matches)
+	targetRepo := s.container.Repositories().Targets
+	for reason, ids := range byReason {
+		if err := targetRepo.SetStateWithReason(ctx, campaign.ID, ids, domain.TargetStateSuppressed, reason); err != nil {
+			logger.Error("scheduler: mark suppressed targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+			continue
+		}
+		logger.Info("scheduler: suppressed targets", zap.String("campaign_id", campaign.ID.String()), zap.Int("count", len(ids)), zap.String("reason", reason))
+	}
+	return dialable
+}
 
-	// Check each retry topic for pending messages
-	for idx, topic := range cfg.Kafka.RetryTopics {
-		// Create a temporary reader with a unique consumer group to avoid interfering with retry workers
-		// Set CommitInterval to 0 to prevent committing messages and removing them from the topic
-		reader := kafkaClient.NewReaderWithConfig(kafka.ReaderConfig{
-			Brokers:        cfg.Kafka.Brokers,
-			Topic:          topic,
-			GroupID:        fmt.Sprintf("scheduler-retry-check-%d", idx),
-			StartOffset:    kafka.FirstOffset,
-			CommitInterval: 0,  // IMPORTANT: Do not commit messages
-			MaxBytes:       10, // Read small chunks to quickly detect pending messages
-		})
-
-		// Try to fetch a message with a very short timeout
-		fetchCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
-		msg, err := reader.FetchMessage(fetchCtx)
-		cancel()
-
-		// Close reader immediately after use
-		reader.Close()
-
-		if err == nil {
-			// There is at least one message in this retry topic
-			logger.Debug("scheduler: found pending retry messages", zap.String("topic", topic), zap.String("message.key", string(msg.Key)), zap.Int("message.offset", int(msg.Offset)))
-			return true, nil
+// splitSuppressed separates the targets whose numbers appear in matches, grouping their ids by
+// suppression reason, from those that may be dialled. It reuses the backing array of targets.
+func splitSuppressed(targets []repository.CampaignTargetRecord, matches map[string]string) ([]repository.CampaignTargetRecord, map[string][]uuid.UUID) {
+	byReason := make(map[string][]uuid.UUID)
+	dialable := targets[:0]
+	for _, t := range targets {
+		if reason, ok := matches[t.PhoneNumber]; ok {
+			byReason[reason] = append(byReason[reason], t.ID)
+			continue
 		}
+		dialable = append(dialable, t)
+	}
+	return dialable, byReason
+}
 
-		// If error is not context timeout, there might be an issue
-		if err != context.DeadlineExceeded {
-			logger.Warn("scheduler: error checking retry topic", zap.String("topic", topic), zap.Error(err))
-		} else if err == context.DeadlineExceeded {
-			logger.Debug("scheduler: no pending messages in topic (timeout)", zap.String("topic", topic))
+// filterQuietHours defers targets whose recipients are inside regulatory quiet hours until the
+// next legal time and returns the rest. Targets that cannot be checked are not dialed either:
+// they are deferred for compliance.UnverifiedDelay and checked again then.
+func (s *Scheduler) filterQuietHours(ctx context.Context, campaign *domain.Campaign, targets []repository.CampaignTargetRecord, now time.Time) []repository.CampaignTargetRecord {
+	logger := s.container.Logger
+	guard := s.container.Compliance
+
+	type deferral struct {
+		until  time.Time
+		reason string
+	}
+	deferred := make(map[deferral][]uuid.UUID)
+	dialable := targets[:0]
+	for _, t := range targets {
+		decision, err := guard.Check(t.PhoneNumber, recipientZone(campaign, t), now)
+		if err != nil {
+			logger.Warn("scheduler: quiet hours check", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+			key := deferral{until: now.Add(compliance.UnverifiedDelay), reason: compliance.ReasonUnverified}
+			deferred[key] = append(deferred[key], t.ID)
+			continue
 		}
+		if !decision.Allowed {
+			key := deferral{until: decision.NextAllowed, reason: compliance.ReasonQuietHours}
+			deferred[key] = append(deferred[key], t.ID)
+			continue
+		}
+		dialable = append(dialable, t)
 	}
 
-	return false, nil
+	targetRepo := s.container.Repositories().Targets
+	for d, ids := range deferred {
+		n, err := targetRepo.Defer(ctx, campaign.ID, ids, d.until, d.reason)
+		if err != nil {
+			logger.Error("scheduler: defer targets for quiet hours", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+			continue
+		}
+		logger.Info("scheduler: deferred targets for quiet hours", zap.String("campaign_id", campaign.ID.String()), zap.Int64("count", n), zap.Time("until", d.until), zap.String("reason", d.reason))
+	}
+	return dialable
 }
 
-func (s *Scheduler) campaignFetchLimit() int {
-	cfg := s.container.Config
-	limit := cfg.Scheduler.WorkerCount * 10
+// recipientZone returns the target's time zone, or the campaign's when the target's is unknown.
+func recipientZone(campaign *domain.Campaign, target repository.CampaignTargetRecord) string {
+	if target.TimeZone != "" {
+		return target.TimeZone
+	}
+	return campaign.TimeZone
+}
+
+// applyCampaignWindows starts campaigns whose start_at has passed and ends those whose end_at has passed.
+func (s *Scheduler) applyCampaignWindows(ctx context.Context, now time.Time) {
+	campaignSvc := s.container.Services().Campaign
+	logger := s.container.Logger
+	limit := s.campaignFetchLimit()
+
+	started, err := campaignSvc.StartDue(ctx, now, limit)
+	if err != nil {
+		logger.Warn("scheduler: start scheduled campaigns", zap.Error(err))
+	}
+	for _, id := range started {
+		logger.Info("scheduler: campaign started at scheduled time", zap.String("campaign_id", id.String()))
+	}
+
+	ended, err := campaignSvc.EndExpired(ctx, now, limit)
+	if err != nil {
+		logger.Warn("scheduler: end expired campaigns", zap.Error(err))
+	}
+	for _, id := range ended {
+		logger.Info("scheduler: campaign reached scheduled end", zap.String("campaign_id", id.String()))
+	}
+}
+
+// completeIfDrained moves the campaign to completed once no targets, calls or retries remain outstanding.
+func (s *Scheduler) completeIfDrained(ctx context.Context, campaign *domain.Campaign) bool {
+	logger := s.container.Logger
+	summary, completed, err := s.container.Services().Campaign.CompleteIfDrained(ctx, campaign.ID)
+	if err != nil {
+		logger.Warn("scheduler: completion check failed", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return false
+	}
+	if !completed {
+		return false
+	}
+	logger.Info("scheduler: campaign completed",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.Int64("total_targets", summary.TotalTargets),
+		zap.Int64("total_calls", summary.TotalCalls),
+		zap.Int64("completed_calls", summary.CompletedCalls),
+		zap.Int64("failed_calls", summary.FailedCalls),
+		zap.Int64("retries_attempted", summary.RetriesAttempted),
+	)
+	return true
+}
+
+// batchSize returns how many new targets the campaign may claim this tick: its free concurrency
+// slots plus the configured headroom, less the targets already queued for a worker and the
+// retries it still has waiting, and never more than the configured batch size. Inputs that
+// cannot be read are left out rather than stalling the campaign.
+func (s *Scheduler) batchSize(ctx context.Context, campaign *domain.Campaign) int {
+	cfg := s.container.Config.Scheduler
+	logger := s.container.Logger
+	limit := cfg.MaxBatchSize
 	if limit <= 0 {
 		limit = 100
 	}
-	// Increase limit to ensure all campaigns are processed
-	// TODO: Consider prioritizing campaigns with pending targets
-	return limit * 2 // 80 campaigns
+
+	free, err := s.container.Limiters().Concurrency.Available(ctx, campaign.ID, campaign.MaxConcurrentCalls)
+	if err != nil {
+		logger.Warn("scheduler: read free concurrency slots", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return limit
+	}
+
+	queued, err := s.container.Repositories().Targets.CountInState(ctx, campaign.ID, domain.TargetStateQueued)
+	if err != nil {
+		logger.Warn("scheduler: count queued targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		queued = 0
+	}
+	outstanding, err := s.container.Limiters().RetryBacklog.Outstanding(ctx, campaign.ID)
+	if err != nil {
+		logger.Warn("scheduler: read retry backlog", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		outstanding = 0
+	}
+
+	size := claimSize(limit, free, cfg.DispatchHeadroom, queued, outstanding)
+	logger.Debug("scheduler: sized campaign batch",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.Int("free_slots", free),
+		zap.Int64("queued", queued),
+		zap.Int("retry_backlog", outstanding),
+		zap.Int("batch_size", size))
+	return size
 }
 
-func isWithinBusinessHours(nowUTC time.Time, campaign *domain.Campaign) bool {
-	if len(campaign.BusinessHours) == 0 {
-		return true
+// claimSize is the batch a campaign may claim given its free slots, the dispatch headroom, the
+// targets already queued and its outstanding retries, capped at limit. Free slots count up to
+// limit, so an unlimited campaign (math.MaxInt) cannot overflow. The result may be zero or
+// negative when the campaign already has more work in flight than it can take.
+func claimSize(limit, free, headroom int, queued int64, outstanding int) int {
+	return min(min(free, limit)+max(headroom, 0)-int(queued)-outstanding, limit)
+}
+
+// stage gives the campaign's next batch release times spread over the first moments of the
+// window opening at opensAt, so the batch is claimed gradually instead of in one burst on the
+// first tick inside the window. It tops up to a single batch and is a no-op once that is staged.
+func (s *Scheduler) stage(ctx context.Context, campaign *domain.Campaign, opensAt time.Time) {
+	logger := s.container.Logger
+	targetRepo := s.container.Repositories().Targets
+
+	staged, err := targetRepo.CountInState(ctx, campaign.ID, domain.TargetStateStaged)
+	if err != nil {
+		logger.Warn("scheduler: count staged targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return
+	}
+	spread := s.container.Config.Scheduler.StageSpread
+	if spread <= 0 {
+		spread = 30 * time.Second
+	}
+	plan := planStage(s.batchSize(ctx, campaign), staged, opensAt, spread)
+	if plan.missing <= 0 {
+		return
 	}
 
-	loc, err := time.LoadLocation(campaign.TimeZone)
+	n, err := targetRepo.Stage(ctx, campaign.ID, plan.missing, plan.releaseAt, plan.spacing)
 	if err != nil {
-		return true
+		logger.Error("scheduler: stage targets", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return
+	}
+	if n > 0 {
+		logger.Info("scheduler: staged targets ahead of business window",
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.Int64("count", n),
+			zap.Time("opens_at", opensAt),
+			zap.Duration("spread", spread))
 	}
+}
 
-	local := nowUTC.In(loc)
-	minuteOfDay := local.Hour()*60 + local.Minute()
-	weekday := local.Weekday()
+// stageOpening returns when the campaign's calling window next opens, if that is within
+// lookAhead of now so its first batch should be staged.
+func stageOpening(campaign *domain.Campaign, now time.Time, lookAhead time.Duration) (time.Time, bool) {
+	opensAt, ok, err := campaign.NextOpen(now)
+	if err != nil || !ok || opensAt.Sub(now) > lookAhead {
+		return time.Time{}, false
+	}
+	return opensAt, true
+}
 
-	for _, window := range campaign.BusinessHours {
-		start := window.Start.Hour()*60 + window.Start.Minute()
-		end := window.End.Hour()*60 + window.End.Minute()
+// stagePlan is how many more targets to stage, when the first of them is released and how far
+// apart the rest follow.
+type stagePlan struct {
+	missing   int
+	releaseAt time.Time
+	spacing   time.Duration
+}
 
-		if end <= start {
-			// window spans midnight
-			nextDay := (int(window.DayOfWeek) + 1) % 7
-			if window.DayOfWeek == weekday && minuteOfDay >= start {
-				return true
-			}
-			if time.Weekday(nextDay) == weekday && minuteOfDay < end {
-				return true
-			}
-			continue
-		}
+// planStage spreads a batch of size targets evenly over spread from opensAt. The staged targets
+// already hold the first release slots, so the missing ones continue after them.
+func planStage(size int, staged int64, opensAt time.Time, spread time.Duration) stagePlan {
+	missing := size - int(staged)
+	if missing <= 0 {
+		return stagePlan{}
+	}
+	spacing := spread / time.Duration(size)
+	return stagePlan{
+		missing:   missing,
+		releaseAt: opensAt.Add(spacing * time.Duration(staged)),
+		spacing:   spacing,
+	}
+}
+
+// fence raises the lease's fence in Postgres to token and returns ctx carrying it.
+func (s *Scheduler) fence(ctx context.Context, token int64) (context.Context, error) {
+	lease := s.elector.Lease()
+	if err := s.container.Repositories().Fences.Advance(ctx, lease, s.elector.Identity(), token); err != nil {
+		return ctx, err
+	}
+	return repository.WithFence(ctx, repository.Fence{Name: lease, Token: token}), nil
+}
+
+// claimTTL bounds how long claimed targets wait for a call before another scheduler may take them.
+func (s *Scheduler) claimTTL() time.Duration {
+	if ttl := s.container.Config.Scheduler.ClaimTTL; ttl > 0 {
+		return ttl
+	}
+	return 2 * time.Minute
+}
 
-		if window.DayOfWeek != weekday {
+func (s *Scheduler) campaignFetchLimit() int {
+	cfg := s.container.Config
+	limit := cfg.Scheduler.WorkerCount * 10
+	if limit <= 0 {
+		limit = 100
+	}
+	// Campaigns are listed highest priority first, so any cut-off drops the least urgent ones.
+	return limit * 2 // 80 campaigns
+}
+
+// openZones returns the time zones of the campaign's claimable targets whose local calling
+// window is open at nowUTC. Targets without a known zone ("") follow the campaign's time zone.
+func (s *Scheduler) openZones(ctx context.Context, campaign *domain.Campaign, nowUTC time.Time) []string {
+	logger := s.container.Logger
+	zones, err := s.container.Repositories().Targets.ListTimeZones(ctx, campaign.ID,
+		[]string{domain.TargetStatePending, domain.TargetStateStaged, domain.TargetStateDeferred, domain.TargetStateQueued})
+	if err != nil {
+		logger.Error("scheduler: list target time zones", zap.Error(err), zap.String("campaign_id", campaign.ID.String()))
+		return nil
+	}
+
+	open := zones[:0]
+	for _, zone := range zones {
+		ok, err := campaign.ForRecipient(zone, s.recipientWindow).IsOpenAt(nowUTC)
+		if err != nil {
+			logger.Warn("scheduler: skip unusable target time zone", zap.Error(err), zap.String("campaign_id", campaign.ID.String()), zap.String("time_zone", zone))
 			continue
 		}
-
-		if minuteOfDay >= start && minuteOfDay < end {
-			return true
+		if ok {
+			open = append(open, zone)
 		}
 	}
+	return open
+}
 
-	return false
+// isWithinBusinessHours reports whether the campaign may dial at nowUTC. A campaign whose time
+// zone cannot be loaded is treated as open, as it was accepted before the zone went missing.
+func isWithinBusinessHours(nowUTC time.Time, campaign *domain.Campaign) bool {
+	open, err := campaign.IsOpenAt(nowUTC)
+	if err != nil {
+		return true
+	}
+	return open
 }
@@ -1,10 +1,14 @@
 package scheduler
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
 func TestIsWithinBusinessHours(t *testing.T) {
@@ -57,3 +61,110 @@ func TestIsWithinBusinessHoursSpanningMidnight(t *testing.T) {
 		t.Fatalf("expected %v to be within cross-midnight window", earlyMorning)
 	}
 }
+
+func TestNextWindowOpen(t *testing.T) {
+	campaign := &domain.Campaign{
+		TimeZone: "America/New_York",
+		BusinessHours: []domain.BusinessHourWindow{
+			{
+				DayOfWeek: time.Monday,
+				Start:     time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+				End:       time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC),
+			},
+			{
+				DayOfWeek: time.Wednesday,
+				Start:     time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC),
+				End:       time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	week := 7 * 24 * time.Hour
+
+	// Monday 08:55 in New York.
+	now := time.Date(2024, 1, 1, 13, 55, 0, 0, time.UTC)
+	opens, ok := stageOpening(campaign, now, week)
+	if !ok || !opens.Equal(time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Monday 09:00 EST, got %v (ok=%v)", opens, ok)
+	}
+	if _, ok := stageOpening(campaign, now, time.Minute); ok {
+		t.Fatalf("expected an opening five minutes away to be outside a one-minute look-ahead")
+	}
+
+	// Monday 17:30 in New York: the Monday window has closed.
+	now = time.Date(2024, 1, 1, 22, 30, 0, 0, time.UTC)
+	opens, ok = stageOpening(campaign, now, week)
+	if !ok || !opens.Equal(time.Date(2024, 1, 3, 15, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected Wednesday 10:30 EST, got %v (ok=%v)", opens, ok)
+	}
+
+	// Wednesday evening wraps to the following Monday.
+	now = time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC)
+	opens, ok = stageOpening(campaign, now, week)
+	if !ok || !opens.Equal(time.Date(2024, 1, 8, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected next Monday 09:00 EST, got %v (ok=%v)", opens, ok)
+	}
+
+	// The campaign ends before its window opens again.
+	endAt := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	ending := *campaign
+	ending.EndAt = &endAt
+	if opens, ok := stageOpening(&ending, now, week); ok {
+		t.Fatalf("expected no opening before the campaign ends, got %v", opens)
+	}
+}
+
+func TestPlanStage(t *testing.T) {
+	opensAt := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		size   int
+		staged int64
+		want   stagePlan
+	}{
+		{name: "nothing staged", size: 10, staged: 0, want: stagePlan{missing: 10, releaseAt: opensAt, spacing: 3 * time.Second}},
+		{name: "tops up after staged", size: 10, staged: 4, want: stagePlan{missing: 6, releaseAt: opensAt.Add(12 * time.Second), spacing: 3 * time.Second}},
+		{name: "batch already staged", size: 10, staged: 10},
+		{name: "more staged than a batch", size: 5, staged: 8},
+		{name: "no capacity", size: 0, staged: 0},
+		{name: "negative capacity", size: -3, staged: 0},
+	}
+	for _, tc := range cases {
+		got := planStage(tc.size, tc.staged, opensAt, 30*time.Second)
+		if got.missing != tc.want.missing || !got.releaseAt.Equal(tc.want.releaseAt) || got.spacing != tc.want.spacing {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSplitSuppressed(t *testing.T) {
+	targets := []repository.CampaignTargetRecord{
+		{ID: uuid.New(), PhoneNumber: "+14155550100"},
+		{ID: uuid.New(), PhoneNumber: "+14155550101"},
+		{ID: uuid.New(), PhoneNumber: "+14155550102"},
+		{ID: uuid.New(), PhoneNumber: "+14155550103"},
+	}
+	matches := map[string]string{
+		"+14155550101": "do-not-call",
+		"+14155550102": "litigator",
+		"+14155550103": "do-not-call",
+		"+14155550199": "not a target",
+	}
+	ids := []uuid.UUID{targets[0].ID, targets[1].ID, targets[2].ID, targets[3].ID}
+
+	dialable, byReason := splitSuppressed(targets, matches)
+	if len(dialable) != 1 || dialable[0].ID != ids[0] {
+		t.Fatalf("dialable = %+v, want only the first target", dialable)
+	}
+	want := map[string][]uuid.UUID{
+		"do-not-call": {ids[1], ids[3]},
+		"litigator":   {ids[2]},
+	}
+	if !reflect.DeepEqual(byReason, want) {
+		t.Fatalf("byReason = %v, want %v", byReason, want)
+	}
+
+	dialable, byReason = splitSuppressed(targets[:1], nil)
+	if len(dialable) != 1 || len(byReason) != 0 {
+		t.Fatalf("without matches got %d dialable and %v suppressed", len(dialable), byReason)
+	}
+}
@@ -0,0 +1,211 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// maxEventDays bounds how many dates a single multi-day event may expand to.
+const maxEventDays = 366
+
+// icalResult is what parseICal found in a file.
+type icalResult struct {
+	Events   int
+	Skipped  int
+	Holidays []domain.Holiday
+}
+
+// icalEvent holds the VEVENT properties the import cares about.
+type icalEvent struct {
+	line      int
+	start     string
+	startDate bool
+	end       string
+	duration  string
+	summary   string
+	recurring bool
+	cancelled bool
+}
+
+// parseICal reads the VEVENTs of an iCalendar stream. All-day events become one holiday per
+// date they cover (DTEND is exclusive); timed, recurring and cancelled events are counted as
+// skipped rather than guessed at.
+func parseICal(r io.Reader) (*icalResult, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &icalResult{}
+	var (
+		seenCalendar bool
+		event        *icalEvent
+	)
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		name, params, value, ok := splitICalLine(line)
+		if !ok {
+			return nil, fmt.Errorf("%w: calendar line %d is malformed", apperrors.ErrValidation, i+1)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			seenCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = &icalEvent{line: i + 1}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event == nil {
+				continue
+			}
+			result.Events++
+			holidays, err := event.holidays()
+			if err != nil {
+				return nil, err
+			}
+			if holidays == nil {
+				result.Skipped++
+			}
+			result.Holidays = append(result.Holidays, holidays...)
+			event = nil
+		case event == nil:
+			continue
+		case name == "DTSTART":
+			event.start = value
+			event.startDate = strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102")
+		case name == "DTEND":
+			event.end = value
+		case name == "DURATION":
+			event.duration = value
+		case name == "SUMMARY":
+			event.summary = unescapeICalText(value)
+		case name == "RRULE" || name == "RDATE":
+			event.recurring = true
+		case name == "STATUS":
+			event.cancelled = strings.EqualFold(value, "CANCELLED")
+		}
+	}
+	if !seenCalendar {
+		return nil, fmt.Errorf("%w: file is not an iCalendar (missing BEGIN:VCALENDAR)", apperrors.ErrValidation)
+	}
+	return result, nil
+}
+
+// holidays expands the event into dates, or returns nil when the event is not importable.
+func (e *icalEvent) holidays() ([]domain.Holiday, error) {
+	if e.start == "" || !e.startDate || e.recurring || e.cancelled {
+		return nil, nil
+	}
+	start, err := time.Parse("20060102", e.start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: event at line %d: invalid DTSTART %q", apperrors.ErrValidation, e.line, e.start)
+	}
+
+	days := 1
+	switch {
+	case e.end != "":
+		end, err := time.Parse("20060102", e.end)
+		if err != nil {
+			return nil, fmt.Errorf("%w: event at line %d: invalid DTEND %q", apperrors.ErrValidation, e.line, e.end)
+		}
+		days = int(end.Sub(start).Hours() / 24)
+	case e.duration != "":
+		if days, err = parseDayDuration(e.duration); err != nil {
+			return nil, fmt.Errorf("%w: event at line %d: %v", apperrors.ErrValidation, e.line, err)
+		}
+	}
+	if days < 1 {
+		days = 1
+	}
+	if days > maxEventDays {
+		return nil, fmt.Errorf("%w: event at line %d spans more than %d days", apperrors.ErrValidation, e.line, maxEventDays)
+	}
+
+	holidays := make([]domain.Holiday, 0, days)
+	for d := 0; d < days; d++ {
+		holidays = append(holidays, domain.Holiday{Date: start.AddDate(0, 0, d), Name: e.summary})
+	}
+	return holidays, nil
+}
+
+// unfoldICal splits the stream into logical lines, joining continuation lines that start with
+// a space or tab.
+func unfoldICal(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: read calendar: %v", apperrors.ErrValidation, err)
+	}
+	return lines, nil
+}
+
+// splitICalLine splits "NAME;PARAM=VALUE:value" into its parts. Colons inside quoted
+// parameter values do not end the parameters.
+func splitICalLine(line string) (string, map[string]string, string, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseDayDuration reads whole-day durations such as P1D or P2W.
+func parseDayDuration(value string) (int, error) {
+	v := strings.TrimPrefix(strings.ToUpper(value), "+")
+	if !strings.HasPrefix(v, "P") || len(v) < 3 {
+		return 0, fmt.Errorf("invalid DURATION %q", value)
+	}
+	unit := v[len(v)-1]
+	n, err := strconv.Atoi(v[1 : len(v)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid DURATION %q", value)
+	}
+	switch unit {
+	case 'D':
+		return n, nil
+	case 'W':
+		return n * 7, nil
+	}
+	return 0, fmt.Errorf("unsupported DURATION %q", value)
+}
+
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
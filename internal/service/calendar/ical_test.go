@@ -0,0 +1,80 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+)
+
+const sampleICal = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20251225\r\n" +
+	"DTEND;VALUE=DATE:20251227\r\n" +
+	"SUMMARY:Christmas\\, Boxing\r\n" +
+	"  Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20260101\r\n" +
+	"SUMMARY:New Year\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=\"Europe/London\":20260105T090000\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260214\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:Valentine\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	result, err := parseICal(strings.NewReader(sampleICal))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Events != 4 || result.Skipped != 2 {
+		t.Fatalf("expected 4 events with 2 skipped, got %d and %d", result.Events, result.Skipped)
+	}
+
+	want := []struct{ date, name string }{
+		{"2025-12-25", "Christmas, Boxing Day"},
+		{"2025-12-26", "Christmas, Boxing Day"},
+		{"2026-01-01", "New Year"},
+	}
+	if len(result.Holidays) != len(want) {
+		t.Fatalf("expected %d holidays, got %+v", len(want), result.Holidays)
+	}
+	for i, w := range want {
+		got := result.Holidays[i]
+		if domain.DateKey(got.Date) != w.date || got.Name != w.name {
+			t.Errorf("holiday %d: expected %s %q, got %s %q", i, w.date, w.name, domain.DateKey(got.Date), got.Name)
+		}
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	input := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20260406\nDURATION:P3D\nEND:VEVENT\nEND:VCALENDAR\n"
+	result, err := parseICal(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Holidays) != 3 || domain.DateKey(result.Holidays[2].Date) != "2026-04-08" {
+		t.Fatalf("expected three days ending 2026-04-08, got %+v", result.Holidays)
+	}
+}
+
+func TestParseICalRejectsInvalidInput(t *testing.T) {
+	cases := map[string]string{
+		"not a calendar": "hello world\n",
+		"bad date":       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:2026-01-01\nEND:VEVENT\nEND:VCALENDAR\n",
+		"no calendar":    "BEGIN:VEVENT\nDTSTART:20260101\nEND:VEVENT\n",
+	}
+	for name, input := range cases {
+		if _, err := parseICal(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// MaxHolidays bounds the number of dates accepted by a single create, update or import.
+const MaxHolidays = 5000
+
+// Service manages holiday calendars shared between campaigns.
+type Service struct {
+	repo repository.HolidayCalendarRepository
+}
+
+// NewService constructs the holiday calendar service.
+func NewService(repo repository.HolidayCalendarRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// CreateInput captures holiday calendar creation parameters.
+type CreateInput struct {
+	Name        string
+	Description string
+	Holidays    []domain.Holiday
+}
+
+// UpdateInput captures updatable calendar properties. Holidays, when set, replaces every date.
+type UpdateInput struct {
+	ID          uuid.UUID
+	Name        *string
+	Description *string
+	Holidays    *[]domain.Holiday
+}
+
+// ImportResult summarises an iCal import.
+type ImportResult struct {
+	Events   int   `json:"events"`
+	Dates    int   `json:"dates"`
+	Inserted int64 `json:"inserted"`
+	Updated  int64 `json:"updated"`
+	Skipped  int   `json:"skipped"`
+}
+
+// Create stores a new calendar.
+func (s *Service) Create(ctx context.Context, input CreateInput) (*domain.HolidayCalendar, error) {
+	name, err := validateName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := normalizeHolidays(input.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &domain.HolidayCalendar{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: input.Description,
+		Holidays:    holidays,
+	}
+	if err := s.repo.Create(ctx, calendar); err != nil {
+		return nil, fmt.Errorf("calendar service: create: %w", err)
+	}
+	return calendar, nil
+}
+
+// Get returns a calendar with its holidays.
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.HolidayCalendar, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns every calendar without its holidays.
+func (s *Service) List(ctx context.Context) ([]domain.HolidayCalendar, error) {
+	return s.repo.List(ctx)
+}
+
+// Update modifies a calendar.
+func (s *Service) Update(ctx context.Context, input UpdateInput) (*domain.HolidayCalendar, error) {
+	calendar, err := s.repo.Get(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []domain.Holiday
+	if input.Holidays != nil {
+		if holidays, err = normalizeHolidays(*input.Holidays); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Name != nil || input.Description != nil {
+		if input.Name != nil {
+			if calendar.Name, err = validateName(*input.Name); err != nil {
+				return nil, err
+			}
+		}
+		if input.Description != nil {
+			calendar.Description = *input.Description
+		}
+		if err := s.repo.Update(ctx, calendar); err != nil {
+			return nil, fmt.Errorf("calendar service: update: %w", err)
+		}
+	}
+
+	if input.Holidays != nil {
+		if err := s.repo.ReplaceHolidays(ctx, calendar.ID, holidays); err != nil {
+			return nil, fmt.Errorf("calendar service: replace holidays: %w", err)
+		}
+		calendar.Holidays = holidays
+	}
+	return calendar, nil
+}
+
+// Delete removes a calendar. Campaigns linked to it stop observing its holidays.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ImportICal merges the all-day events of an iCalendar (RFC 5545) file into the calendar.
+// Dates already on the calendar take the imported name; recurring events are skipped.
+func (s *Service) ImportICal(ctx context.Context, id uuid.UUID, r io.Reader) (*ImportResult, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseICal(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Holidays) == 0 {
+		return nil, fmt.Errorf("%w: calendar file contains no importable events", apperrors.ErrValidation)
+	}
+	holidays, err := normalizeHolidays(parsed.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := s.repo.MergeHolidays(ctx, id, holidays)
+	if err != nil {
+		return nil, fmt.Errorf("calendar service: import: %w", err)
+	}
+	return &ImportResult{
+		Events:   parsed.Events,
+		Dates:    len(holidays),
+		Inserted: inserted,
+		Updated:  int64(len(holidays)) - inserted,
+		Skipped:  parsed.Skipped,
+	}, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: calendar name is required", apperrors.ErrValidation)
+	}
+	return name, nil
+}
+
+// normalizeHolidays moves every date to midnight UTC and drops repeated dates, keeping the
+// first name given for each, in date order.
+func normalizeHolidays(holidays []domain.Holiday) ([]domain.Holiday, error) {
+	if len(holidays) > MaxHolidays {
+		return nil, fmt.Errorf("%w: calendar exceeds %d dates", apperrors.ErrValidation, MaxHolidays)
+	}
+	seen := make(map[string]struct{}, len(holidays))
+	out := make([]domain.Holiday, 0, len(holidays))
+	for i, h := range holidays {
+		if h.Date.IsZero() {
+			return nil, fmt.Errorf("%w: holidays[%d]: date is required", apperrors.ErrValidation, i)
+		}
+		date := time.Date(h.Date.Year(), h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, time.UTC)
+		key := domain.DateKey(date)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, domain.Holiday{Date: date, Name: strings.TrimSpace(h.Name)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
@@ -2,31 +2,36 @@ package call
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 
 	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/queue"
 	"github.com/acme/outbound-call-campaign/internal/repository"
 	"github.com/acme/outbound-call-campaign/internal/service/common"
 	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+	"github.com/acme/outbound-call-campaign/pkg/phone"
 )
 
-// Dispatcher is responsible for pushing call dispatch events.
-type Dispatcher interface {
-	DispatchCall(ctx context.Context, msg queue.DispatchMessage) error
-}
+// ErrSuppressed is returned by TriggerCall when the number is on the do-not-call list.
+var ErrSuppressed = fmt.Errorf("%w: number suppressed", apperrors.ErrValidation)
 
 // Service coordinates call lifecycle operations.
 type Service struct {
 	calls              repository.CallStore
 	campaigns          repository.CampaignRepository
 	targets            repository.CampaignTargetRepository
-	stats              repository.CampaignStatisticsRepository
-	dispatcher         Dispatcher
+	suppression        repository.SuppressionRepository
+	outbox             repository.OutboxRepository
+	callTopic          string
+	logger             *logger.Logger
 	defaultRetry       domain.RetryPolicy
 	defaultConcurrency int
 }
@@ -36,8 +41,10 @@ func NewService(
 	store repository.CallStore,
 	campaignRepo repository.CampaignRepository,
 	targetRepo repository.CampaignTargetRepository,
-	statsRepo repository.CampaignStatisticsRepository,
-	dispatcher Dispatcher,
+	suppressionRepo repository.SuppressionRepository,
+	outbox repository.OutboxRepository,
+	callTopic string,
+	lg *logger.Logger,
 	defaultRetry domain.RetryPolicy,
 	defaultConcurrency int,
 ) *Service {
@@ -45,8 +52,10 @@ func NewService(
 		calls:              store,
 		campaigns:          campaignRepo,
 		targets:            targetRepo,
-		stats:              statsRepo,
-		dispatcher:         dispatcher,
+		suppression:        suppressionRepo,
+		outbox:             outbox,
+		callTopic:          callTopic,
+		logger:             lg,
 		defaultRetry:       defaultRetry,
 		defaultConcurrency: defaultConcurrency,
 	}
@@ -54,12 +63,30 @@ func NewService(
 
 // TriggerCallInput encapsulates the arguments for triggering a call.
 type TriggerCallInput struct {
-	CampaignID  uuid.UUID
+	CampaignID uuid.UUID
+	// TargetID identifies the campaign target being called; when nil the target is looked up by phone number.
+	TargetID uuid.UUID
+	// ClaimOwner is the scheduler replica that claimed the target; empty for ad-hoc calls, which
+	// may only call a pending target.
+	ClaimOwner  string
 	PhoneNumber string
-	Metadata    map[string]any
+	// TimeZone is the recipient's time zone; when empty it is derived from the number, falling
+	// back to the campaign's.
+	TimeZone string
+	// Attempt is the attempt the call starts at, so a target claimed again after an attempt was
+	// deferred or skipped keeps counting its attempts; zero means the first.
+	Attempt  int
+	Metadata map[string]any
 }
 
-// TriggerCall creates and enqueues a call.
+// TriggerCall creates and enqueues a call. The call is stored first; linking it to its target,
+// counting it and recording its dispatch message then happen in one Postgres transaction, and
+// the outbox relay publishes the message to Kafka.
+//
+// The two stores cannot be written atomically. If the process stops after the call is stored
+// but before the transaction commits, the call stays queued in ScyllaDB with nothing referring
+// to it: the target was never linked, so its claim expires and it is called again under a new
+// call, and the orphan is never dialed or counted in the campaign's statistics.
 func (s *Service) TriggerCall(ctx context.Context, input TriggerCallInput) (*domain.Call, error) {
 	log.Printf("DEBUG: TriggerCall called for campaign %s, phone %s", input.CampaignID, input.PhoneNumber)
 	if input.PhoneNumber == "" {
@@ -73,25 +100,48 @@ func (s *Service) TriggerCall(ctx context.Context, input TriggerCallInput) (*dom
 		return nil, fmt.Errorf("call service: lookup campaign: %w", err)
 	}
 	log.Printf("DEBUG: Got campaign %s", campaign.Name)
+	if campaign.Status != domain.CampaignStatusInProgress {
+		return nil, fmt.Errorf("%w: campaign is %s, not in progress", apperrors.ErrConflict, campaign.Status)
+	}
 
-	// Validate that the phone number is part of the campaign's registered targets
-	if err := s.validatePhoneInCampaignTargets(ctx, campaignID, input.PhoneNumber); err != nil {
+	number, err := phone.Normalize(input.PhoneNumber, campaign.DefaultCountry)
+	if err != nil {
 		return nil, err
 	}
+	input.PhoneNumber = number
+	if input.TimeZone == "" {
+		input.TimeZone, _ = phone.TimeZone(number)
+	}
+	if input.TimeZone == "" {
+		input.TimeZone = campaign.TimeZone
+	}
 
-	policy := campaign.RetryPolicy
-	concurrencyLimit := s.defaultConcurrency
-	if campaign.MaxConcurrentCalls > 0 {
-		concurrencyLimit = campaign.MaxConcurrentCalls
+	// Validate that the phone number is part of the campaign's registered targets; the scheduler
+	// already knows the target it is calling.
+	targetID := input.TargetID
+	if targetID == uuid.Nil {
+		targetID, err = s.validatePhoneInCampaignTargets(ctx, campaignID, input.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	suppressed, err := s.suppression.Match(ctx, campaignID, []string{input.PhoneNumber})
+	if err != nil {
+		return nil, fmt.Errorf("call service: check suppression list: %w", err)
+	}
+	if _, ok := suppressed[input.PhoneNumber]; ok {
+		return nil, fmt.Errorf("%w: phone number %s is on the do-not-call list", ErrSuppressed, input.PhoneNumber)
+	}
+
+	attempt := max(input.Attempt, 1)
 	now := time.Now().UTC()
 	call := &domain.Call{
 		ID:           uuid.New(),
 		CampaignID:   campaignID,
 		PhoneNumber:  input.PhoneNumber,
 		Status:       domain.CallStatusQueued,
-		AttemptCount: 0,
+		AttemptCount: attempt - 1,
 		ScheduledAt:  now,
 		CreatedAt:    now,
 		UpdatedAt:    now,
@@ -104,58 +154,78 @@ func (s *Service) TriggerCall(ctx context.Context, input TriggerCallInput) (*dom
 	}
 	log.Printf("DEBUG: Call created successfully: %s", call.ID)
 
-	delta := repository.StatsDelta{TotalCallsDelta: 1, PendingCallsDelta: 1}
-	if err := s.stats.ApplyDelta(ctx, campaignID, delta); err != nil {
-		log.Printf("DEBUG: Failed to update stats: %v", err)
-		return nil, fmt.Errorf("call service: update stats: %w", err)
+	payload := queue.DispatchMessage{
+		CallID:        call.ID,
+		CampaignID:    call.CampaignID,
+		TargetID:      targetID,
+		PhoneNumber:   call.PhoneNumber,
+		TimeZone:      input.TimeZone,
+		Attempt:       attempt,
+		ConfigVersion: campaign.ConfigVersion,
+		Metadata:      input.Metadata,
+		EnqueuedAt:    now,
+		EndAt:         campaign.EndAt,
 	}
-	log.Printf("DEBUG: Stats updated successfully")
 
-	payload := queue.DispatchMessage{
-		CallID:           call.ID,
-		CampaignID:       call.CampaignID,
-		PhoneNumber:      call.PhoneNumber,
-		Attempt:          1,
-		MaxAttempts:      policy.MaxAttempts,
-		RetryBaseMs:      policy.BaseDelay.Milliseconds(),
-		RetryMaxMs:       policy.MaxDelay.Milliseconds(),
-		RetryJitter:      policy.Jitter,
-		ConcurrencyLimit: concurrencyLimit,
-		Metadata:         input.Metadata,
-		EnqueuedAt:       now,
-	}
-
-	if err := s.dispatcher.DispatchCall(ctx, payload); err != nil {
-		if campaignID != uuid.Nil {
-			_ = s.stats.ApplyDelta(ctx, campaignID, repository.StatsDelta{PendingCallsDelta: -1})
-		}
-		return nil, fmt.Errorf("call service: dispatch call: %w", err)
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("call service: marshal dispatch: %w", err)
+	}
+
+	enqueue := repository.CallEnqueue{
+		CampaignID: campaignID,
+		TargetID:   targetID,
+		ClaimOwner: input.ClaimOwner,
+		CallID:     call.ID,
+		Delta:      repository.StatsDelta{TotalCallsDelta: 1, PendingCallsDelta: 1},
+		Message:    repository.OutboxMessage{Topic: s.callTopic, Key: call.ID[:], Payload: value},
+	}
+	if err := s.outbox.EnqueueCall(ctx, enqueue); err != nil {
+		s.abandonCall(ctx, call, err)
+		return nil, fmt.Errorf("call service: enqueue call: %w", err)
 	}
 
 	return call, nil
 }
 
-// validatePhoneInCampaignTargets checks if a phone number is part of the campaign's registered targets.
-func (s *Service) validatePhoneInCampaignTargets(ctx context.Context, campaignID uuid.UUID, phoneNumber string) error {
-	// Get all existing targets for this campaign to validate against
-	existingTargets, err := s.targets.ListByCampaign(ctx, campaignID, 10000, "") // Get all targets, no state filter
-	if err != nil {
-		return fmt.Errorf("call service: get campaign targets: %w", err)
-	}
-
-	// If this campaign has no registered targets, reject the call
-	if len(existingTargets) == 0 {
-		return fmt.Errorf("%w: campaign has no registered targets", apperrors.ErrValidation)
+// abandonCall marks a stored call that could not be enqueued as failed, so it is not left
+// queued forever. Nothing else refers to it, so a failure here is only logged.
+func (s *Service) abandonCall(ctx context.Context, call *domain.Call, cause error) {
+	reason := fmt.Sprintf("enqueue failed: %v", cause)
+	if err := s.calls.UpdateCallStatus(ctx, call.ID, domain.CallStatusFailed, 0, &reason); err != nil {
+		s.logger.Warn("call service: abandon call",
+			zap.Error(err),
+			zap.String("call_id", call.ID.String()),
+			zap.NamedError("cause", cause))
 	}
+}
 
-	// Check if the phone number is in the registered targets
-	for _, target := range existingTargets {
-		if target.PhoneNumber == phoneNumber {
-			return nil // Phone number is valid
+// Settings resolves the limits currently in force for the campaign's calls, filling those the
+// campaign leaves unset from the service defaults. On error the defaults are returned with it,
+// so callers can log the failure and carry on.
+func (s *Service) Settings(ctx context.Context, campaignID uuid.UUID) (domain.CampaignSettings, error) {
+	settings := domain.CampaignSettings{CampaignID: campaignID}
+	if campaignID != uuid.Nil {
+		current, err := s.campaigns.GetSettings(ctx, campaignID)
+		if err != nil {
+			return settings.WithDefaults(s.defaultConcurrency, s.defaultRetry), fmt.Errorf("call service: campaign settings: %w", err)
 		}
+		settings = *current
 	}
+	return settings.WithDefaults(s.defaultConcurrency, s.defaultRetry), nil
+}
 
-	return fmt.Errorf("%w: phone number %s is not part of this campaign's registered target list", apperrors.ErrValidation, phoneNumber)
+// validatePhoneInCampaignTargets checks if a phone number is part of the campaign's registered targets
+// and returns the matching target id.
+func (s *Service) validatePhoneInCampaignTargets(ctx context.Context, campaignID uuid.UUID, phoneNumber string) (uuid.UUID, error) {
+	targetID, err := s.targets.FindIDByPhone(ctx, campaignID, phoneNumber)
+	if errors.Is(err, repository.ErrNotFound) {
+		return uuid.Nil, fmt.Errorf("%w: phone number %s is not part of this campaign's registered target list", apperrors.ErrValidation, phoneNumber)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("call service: lookup campaign target: %w", err)
+	}
+	return targetID, nil
 }
 
 // GetCall retrieves a call by id.
@@ -0,0 +1,44 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+type fakeCampaigns struct {
+	repository.CampaignRepository
+	campaign domain.Campaign
+}
+
+func (f *fakeCampaigns) Get(_ context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	if id != f.campaign.ID {
+		return nil, repository.ErrNotFound
+	}
+	campaign := f.campaign
+	return &campaign, nil
+}
+
+func TestTriggerCallRequiresRunningCampaign(t *testing.T) {
+	for _, status := range []domain.CampaignStatus{
+		domain.CampaignStatusPending,
+		domain.CampaignStatusPaused,
+		domain.CampaignStatusCompleted,
+		domain.CampaignStatusCancelled,
+	} {
+		campaigns := &fakeCampaigns{campaign: domain.Campaign{ID: uuid.New(), Status: status, DefaultCountry: "US"}}
+		// Only the campaign lookup is wired: nothing past the status check may run.
+		svc := NewService(nil, campaigns, nil, nil, nil, "calls", nil, domain.RetryPolicy{}, 1)
+
+		_, err := svc.TriggerCall(context.Background(), TriggerCallInput{CampaignID: campaigns.campaign.ID, PhoneNumber: "+14155550100"})
+		if !errors.Is(err, apperrors.ErrConflict) {
+			t.Errorf("TriggerCall on %s campaign = %v, want conflict", status, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package campaign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+)
+
+// campaignSnapshot is the audit representation of campaign settings.
+type campaignSnapshot struct {
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	TimeZone           string        `json:"time_zone"`
+	DefaultCountry     string        `json:"default_country,omitempty"`
+	RecipientLocalTime bool          `json:"recipient_local_time"`
+	Status             string        `json:"status"`
+	MaxConcurrentCalls int           `json:"max_concurrent_calls"`
+	Priority           int           `json:"priority"`
+	Weight             int           `json:"weight"`
+	RetryPolicy        retrySnapshot `json:"retry_policy"`
+	StartAt            *time.Time    `json:"start_at,omitempty"`
+	EndAt              *time.Time    `json:"end_at,omitempty"`
+}
+
+// createdSnapshot extends campaignSnapshot with the initial business hours, date rules and
+// target count.
+type createdSnapshot struct {
+	campaignSnapshot
+	BusinessHours []businessHourSnapshot `json:"business_hours"`
+	Calendar      calendarSnapshot       `json:"calendar"`
+	Targets       int                    `json:"targets"`
+}
+
+type retrySnapshot struct {
+	MaxAttempts int     `json:"max_attempts"`
+	BaseDelay   string  `json:"base_delay"`
+	MaxDelay    string  `json:"max_delay"`
+	Jitter      float64 `json:"jitter"`
+}
+
+type statusSnapshot struct {
+	Status  string                    `json:"status"`
+	Summary *domain.CompletionSummary `json:"completion_summary,omitempty"`
+}
+
+type businessHourSnapshot struct {
+	DayOfWeek int    `json:"day_of_week"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+// calendarSnapshot records the holiday calendars and date exceptions of a campaign.
+type calendarSnapshot struct {
+	HolidayCalendarIDs []uuid.UUID              `json:"holiday_calendar_ids"`
+	Blackouts          []blackoutSnapshot       `json:"blackout_dates"`
+	OverrideWindows    []overrideWindowSnapshot `json:"override_windows"`
+}
+
+type blackoutSnapshot struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type overrideWindowSnapshot struct {
+	Date  string `json:"date"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type targetsAddedSnapshot struct {
+	Count int `json:"count"`
+	Reset int `json:"reset,omitempty"`
+}
+
+type targetSnapshot struct {
+	ID          uuid.UUID      `json:"id"`
+	PhoneNumber string         `json:"phone_number"`
+	State       string         `json:"state"`
+	TimeZone    string         `json:"time_zone,omitempty"`
+	Payload     map[string]any `json:"payload,omitempty"`
+}
+
+func snapshotCampaign(c *domain.Campaign) campaignSnapshot {
+	return campaignSnapshot{
+		Name:               c.Name,
+		Description:        c.Description,
+		TimeZone:           c.TimeZone,
+		DefaultCountry:     c.DefaultCountry,
+		RecipientLocalTime: c.RecipientLocalTime,
+		Status:             string(c.Status),
+		MaxConcurrentCalls: c.MaxConcurrentCalls,
+		Priority:           c.Priority,
+		Weight:             c.Weight,
+		RetryPolicy: retrySnapshot{
+			MaxAttempts: c.RetryPolicy.MaxAttempts,
+			BaseDelay:   c.RetryPolicy.BaseDelay.String(),
+			MaxDelay:    c.RetryPolicy.MaxDelay.String(),
+			Jitter:      c.RetryPolicy.Jitter,
+		},
+		StartAt: c.StartAt,
+		EndAt:   c.EndAt,
+	}
+}
+
+func snapshotTarget(t *repository.CampaignTargetRecord) targetSnapshot {
+	return targetSnapshot{ID: t.ID, PhoneNumber: t.PhoneNumber, State: t.State, TimeZone: t.TimeZone, Payload: t.Payload}
+}
+
+func snapshotBusinessHours(windows []domain.BusinessHourWindow) []businessHourSnapshot {
+	out := make([]businessHourSnapshot, 0, len(windows))
+	for _, w := range windows {
+		out = append(out, businessHourSnapshot{
+			DayOfWeek: int(w.DayOfWeek),
+			Start:     w.Start.Format("15:04"),
+			End:       w.End.Format("15:04"),
+		})
+	}
+	return out
+}
+
+func snapshotCalendar(c *domain.Campaign) calendarSnapshot {
+	out := calendarSnapshot{
+		HolidayCalendarIDs: append([]uuid.UUID{}, c.HolidayCalendarIDs...),
+		Blackouts:          make([]blackoutSnapshot, 0, len(c.Blackouts)),
+		OverrideWindows:    make([]overrideWindowSnapshot, 0, len(c.OverrideWindows)),
+	}
+	for _, b := range c.Blackouts {
+		out.Blackouts = append(out.Blackouts, blackoutSnapshot{Date: domain.DateKey(b.Date), Reason: b.Reason})
+	}
+	for _, o := range c.OverrideWindows {
+		out.OverrideWindows = append(out.OverrideWindows, overrideWindowSnapshot{
+			Date:  domain.DateKey(o.Date),
+			Start: o.Start.Format("15:04"),
+			End:   o.End.Format("15:04"),
+		})
+	}
+	return out
+}
+
+// recordEvent appends an audit event attributed to the actor carried on ctx. A nil before or
+// after is stored as absent. The change it describes is already stored, so a failure is logged
+// rather than returned.
+func (s *Service) recordEvent(ctx context.Context, campaignID uuid.UUID, eventType domain.CampaignEventType, before, after any) {
+	event := &domain.CampaignEvent{
+		CampaignID: campaignID,
+		Type:       eventType,
+		Actor:      common.ActorFromContext(ctx),
+	}
+	var errBefore, errAfter error
+	event.Before, errBefore = marshalSnapshot(before)
+	event.After, errAfter = marshalSnapshot(after)
+	if err := errors.Join(errBefore, errAfter); err != nil {
+		s.eventFailed(ctx, campaignID, eventType, err)
+		return
+	}
+	if err := s.eventsRepo.Append(ctx, event); err != nil {
+		s.eventFailed(ctx, campaignID, eventType, err)
+	}
+}
+
+// recordChange records an event only when before and after differ.
+func (s *Service) recordChange(ctx context.Context, campaignID uuid.UUID, eventType domain.CampaignEventType, before, after any) {
+	b, errBefore := marshalSnapshot(before)
+	a, errAfter := marshalSnapshot(after)
+	if err := errors.Join(errBefore, errAfter); err != nil {
+		s.eventFailed(ctx, campaignID, eventType, err)
+		return
+	}
+	if bytes.Equal(b, a) {
+		return
+	}
+	s.recordEvent(ctx, campaignID, eventType, json.RawMessage(b), json.RawMessage(a))
+}
+
+func (s *Service) eventFailed(ctx context.Context, campaignID uuid.UUID, eventType domain.CampaignEventType, err error) {
+	s.logger.Error("campaign service: record event",
+		zap.Error(err),
+		zap.String("campaign_id", campaignID.String()),
+		zap.String("event_type", string(eventType)),
+		zap.String("actor", common.ActorFromContext(ctx)))
+}
+
+func (s *Service) recordStatusChange(ctx context.Context, campaign *domain.Campaign, eventType domain.CampaignEventType, from domain.CampaignStatus) {
+	s.recordEvent(ctx, campaign.ID, eventType,
+		statusSnapshot{Status: string(from)},
+		statusSnapshot{Status: string(campaign.Status), Summary: campaign.CompletionSummary},
+	)
+}
+
+func marshalSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// ListEvents returns the campaign audit trail newest first. Pass the id of the last event
+// seen as beforeID to fetch the next page.
+func (s *Service) ListEvents(ctx context.Context, campaignID uuid.UUID, beforeID *int64, limit int) ([]domain.CampaignEvent, error) {
+	if _, err := s.repo.Get(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	events, err := s.eventsRepo.List(ctx, campaignID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign service: list events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,262 @@
+package campaign
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+// The fakes embed the repository interfaces so that only the methods a test exercises need an
+// implementation; calling any other panics.
+
+type fakeCampaigns struct {
+	repository.CampaignRepository
+	campaigns map[uuid.UUID]*domain.Campaign
+	targets   *fakeTargets
+	// afterGet runs once Get has read the campaign, standing in for a concurrent change.
+	afterGet func()
+}
+
+func (f *fakeCampaigns) add(c domain.Campaign) *domain.Campaign {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	f.campaigns[c.ID] = &c
+	return &c
+}
+
+func (f *fakeCampaigns) Get(_ context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	c, ok := f.campaigns[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *c
+	if f.afterGet != nil {
+		f.afterGet()
+	}
+	return &copied, nil
+}
+
+// Update stores c but, like the Postgres repository, keeps the stored status and its timestamps.
+func (f *fakeCampaigns) Update(_ context.Context, c *domain.Campaign) error {
+	stored, ok := f.campaigns[c.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	copied := *c
+	copied.Status, copied.StartedAt = stored.Status, stored.StartedAt
+	copied.CompletedAt, copied.CompletionSummary = stored.CompletedAt, stored.CompletionSummary
+	f.campaigns[c.ID] = &copied
+	return nil
+}
+
+func (f *fakeCampaigns) Transition(_ context.Context, t repository.CampaignTransition) error {
+	stored, ok := f.campaigns[t.Campaign.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if stored.Status != t.From {
+		return repository.ErrConflict
+	}
+	f.targets.move(t.Campaign.ID, t.TargetsFrom, t.TargetsTo)
+	copied := *stored
+	copied.Status, copied.StartedAt = t.Campaign.Status, t.Campaign.StartedAt
+	copied.CompletedAt, copied.CompletionSummary = t.Campaign.CompletedAt, t.Campaign.CompletionSummary
+	f.campaigns[t.Campaign.ID] = &copied
+	return nil
+}
+
+func (f *fakeCampaigns) ListDueToStart(_ context.Context, now time.Time, _ int) ([]*domain.Campaign, error) {
+	var out []*domain.Campaign
+	for _, c := range f.campaigns {
+		if c.Status == domain.CampaignStatusPending && c.StartAt != nil && !c.StartAt.After(now) && (c.EndAt == nil || c.EndAt.After(now)) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCampaigns) ListByStatus(_ context.Context, status domain.CampaignStatus, _ int) ([]*domain.Campaign, error) {
+	var out []*domain.Campaign
+	for _, c := range f.campaigns {
+		if c.Status == status {
+			copied := *c
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCampaigns) ListPastEnd(_ context.Context, now time.Time, _ int) ([]*domain.Campaign, error) {
+	running := []domain.CampaignStatus{domain.CampaignStatusPending, domain.CampaignStatusInProgress, domain.CampaignStatusPaused}
+	var out []*domain.Campaign
+	for _, c := range f.campaigns {
+		if slices.Contains(running, c.Status) && c.EndAt != nil && !c.EndAt.After(now) {
+			copied := *c
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+// fakeTargets keeps only the number of targets in each state per campaign.
+type fakeTargets struct {
+	repository.CampaignTargetRepository
+	counts map[uuid.UUID]map[string]int64
+}
+
+// move moves the campaign's targets in the from states to the to state, as Transition does.
+func (f *fakeTargets) move(campaignID uuid.UUID, from []string, to string) {
+	counts := f.counts[campaignID]
+	var moved int64
+	for _, state := range from {
+		moved += counts[state]
+		delete(counts, state)
+	}
+	if moved > 0 {
+		counts[to] += moved
+	}
+}
+
+func (f *fakeTargets) CountByState(_ context.Context, campaignID uuid.UUID) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for state, n := range f.counts[campaignID] {
+		out[state] = n
+	}
+	return out, nil
+}
+
+// fakeHours and fakeCalendars hold schedules per campaign and count the queries made for them.
+type fakeHours struct {
+	repository.BusinessHourRepository
+	windows    map[uuid.UUID][]domain.BusinessHourWindow
+	exceptions map[uuid.UUID]repository.DateExceptions
+	queries    int
+}
+
+func (f *fakeHours) ListByCampaigns(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]domain.BusinessHourWindow, error) {
+	f.queries++
+	out := make(map[uuid.UUID][]domain.BusinessHourWindow)
+	for _, id := range ids {
+		if windows, ok := f.windows[id]; ok {
+			out[id] = windows
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeHours) ListExceptionsByCampaigns(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]repository.DateExceptions, error) {
+	f.queries++
+	out := make(map[uuid.UUID]repository.DateExceptions)
+	for _, id := range ids {
+		if exceptions, ok := f.exceptions[id]; ok {
+			out[id] = exceptions
+		}
+	}
+	return out, nil
+}
+
+type fakeCalendars struct {
+	repository.HolidayCalendarRepository
+	calendars map[uuid.UUID][]uuid.UUID
+	holidays  map[uuid.UUID][]domain.Holiday
+	queries   int
+}
+
+func (f *fakeCalendars) ListCalendarsByCampaigns(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	f.queries++
+	out := make(map[uuid.UUID][]uuid.UUID)
+	for _, id := range ids {
+		if calendars, ok := f.calendars[id]; ok {
+			out[id] = calendars
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCalendars) ListHolidaysByCampaigns(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]domain.Holiday, error) {
+	f.queries++
+	out := make(map[uuid.UUID][]domain.Holiday)
+	for _, id := range ids {
+		if holidays, ok := f.holidays[id]; ok {
+			out[id] = holidays
+		}
+	}
+	return out, nil
+}
+
+type fakeStats struct {
+	repository.CampaignStatisticsRepository
+	stats map[uuid.UUID]domain.CampaignStats
+	// onGet runs before the stats are read, to interleave a concurrent change.
+	onGet func()
+}
+
+func (f *fakeStats) Get(_ context.Context, campaignID uuid.UUID) (*domain.CampaignStats, error) {
+	if f.onGet != nil {
+		f.onGet()
+	}
+	stats := f.stats[campaignID]
+	return &stats, nil
+}
+
+type fakeEvents struct {
+	repository.CampaignEventRepository
+	events []domain.CampaignEvent
+	err    error
+}
+
+func (f *fakeEvents) Append(_ context.Context, event *domain.CampaignEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, *event)
+	return nil
+}
+
+type testService struct {
+	*Service
+	campaigns *fakeCampaigns
+	hours     *fakeHours
+	calendars *fakeCalendars
+	targets   *fakeTargets
+	stats     *fakeStats
+	events    *fakeEvents
+}
+
+func newTestService() *testService {
+	targets := &fakeTargets{counts: make(map[uuid.UUID]map[string]int64)}
+	ts := &testService{
+		campaigns: &fakeCampaigns{campaigns: make(map[uuid.UUID]*domain.Campaign), targets: targets},
+		hours: &fakeHours{
+			windows:    make(map[uuid.UUID][]domain.BusinessHourWindow),
+			exceptions: make(map[uuid.UUID]repository.DateExceptions),
+		},
+		calendars: &fakeCalendars{
+			calendars: make(map[uuid.UUID][]uuid.UUID),
+			holidays:  make(map[uuid.UUID][]domain.Holiday),
+		},
+		targets: targets,
+		stats:   &fakeStats{stats: make(map[uuid.UUID]domain.CampaignStats)},
+		events:  &fakeEvents{},
+	}
+	ts.Service = NewService(ts.campaigns, ts.hours, ts.calendars, ts.targets, ts.stats, ts.events, &logger.Logger{Logger: zap.NewNop()}, 1)
+	return ts
+}
+
+// addCampaign stores c with targets counted by state.
+func (ts *testService) addCampaign(c domain.Campaign, targets map[string]int64) *domain.Campaign {
+	stored := ts.campaigns.add(c)
+	if targets == nil {
+		targets = make(map[string]int64)
+	}
+	ts.targets.counts[stored.ID] = targets
+	return stored
+}
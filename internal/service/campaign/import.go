@@ -0,0 +1,339 @@
+package campaign
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// ImportFormat identifies the encoding of a target upload.
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+const (
+	// importChunkSize is the number of targets written per BulkInsert call.
+	importChunkSize = 1000
+	// maxReportedRowErrors caps the per-row errors returned in an ImportReport.
+	maxReportedRowErrors = 100
+	// maxNDJSONLine bounds a single NDJSON record.
+	maxNDJSONLine = 1 << 20
+)
+
+// RowError describes a rejected input row. Line is the 1-based line number in the upload.
+type RowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarises a target upload.
+type ImportReport struct {
+	RowsRead        int        `json:"rows_read"`
+	Imported        int        `json:"imported"`
+	Reset           int        `json:"reset"`
+	Skipped         int        `json:"skipped"`
+	Rejected        int        `json:"rejected"`
+	Errors          []RowError `json:"errors"`
+	ErrorsTruncated bool       `json:"errors_truncated,omitempty"`
+}
+
+func (r *ImportReport) reject(line int, err error) {
+	r.Rejected++
+	if len(r.Errors) >= maxReportedRowErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, RowError{Line: line, Error: err.Error()})
+}
+
+// rowError marks a problem confined to one input row; reading may continue.
+type rowError struct {
+	line int
+	err  error
+}
+
+func (e *rowError) Error() string { return fmt.Sprintf("line %d: %v", e.line, e.err) }
+
+// targetReader yields targets one row at a time with their line number, returning io.EOF when exhausted.
+type targetReader interface {
+	Next() (TargetInput, int, error)
+}
+
+// ParseImportFormat maps a user supplied format name or file extension to an ImportFormat.
+func ParseImportFormat(value string) (ImportFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), ".")) {
+	case "", "csv", "text/csv":
+		return ImportFormatCSV, nil
+	case "ndjson", "jsonl", "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return ImportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported import format %q", apperrors.ErrValidation, value)
+	}
+}
+
+// ImportTargets streams targets from r into the campaign in chunks. Malformed rows are
+// skipped and reported, and numbers already in the campaign are handled according to policy;
+// the import only fails outright if the upload cannot be read or stored.
+func (s *Service) ImportTargets(ctx context.Context, campaignID uuid.UUID, format ImportFormat, policy domain.DuplicatePolicy, r io.Reader) (*ImportReport, error) {
+	campaign, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status.IsTerminal() {
+		return nil, fmt.Errorf("%w: cannot import targets into a %s campaign", apperrors.ErrConflict, campaign.Status)
+	}
+
+	reader, err := newTargetReader(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Errors: []RowError{}}
+	duplicate := func(line int, phone string) {
+		if policy == domain.DuplicatePolicyError {
+			report.reject(line, fmt.Errorf("%s is already in the campaign", phone))
+			return
+		}
+		report.Skipped++
+	}
+
+	chunk := make([]repository.CampaignTargetRecord, 0, importChunkSize)
+	lines := make(map[string]int, importChunkSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		result, err := s.targetRepo.BulkInsert(ctx, campaignID, chunk, policy)
+		if err != nil {
+			return fmt.Errorf("campaign service: import targets: %w", err)
+		}
+		report.Imported += result.Inserted
+		report.Reset += result.Reset
+		for _, phone := range result.Existing {
+			duplicate(lines[phone], phone)
+		}
+		chunk = chunk[:0]
+		clear(lines)
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		target, line, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			report.RowsRead++
+			report.reject(rowErr.line, rowErr.err)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		report.RowsRead++
+
+		record, err := newTargetRecord(campaign, target, time.Now().UTC())
+		if err != nil {
+			report.reject(line, err)
+			continue
+		}
+		if _, seen := lines[record.PhoneNumber]; seen {
+			duplicate(line, record.PhoneNumber)
+			continue
+		}
+		lines[record.PhoneNumber] = line
+		chunk = append(chunk, record)
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	if report.Imported > 0 || report.Reset > 0 {
+		added := targetsAddedSnapshot{Count: report.Imported, Reset: report.Reset}
+		s.recordEvent(ctx, campaignID, domain.CampaignEventTargetsAdded, nil, added)
+	}
+	return report, nil
+}
+
+func newTargetReader(format ImportFormat, r io.Reader) (targetReader, error) {
+	switch format {
+	case ImportFormatCSV:
+		return newCSVTargetReader(r)
+	case ImportFormatNDJSON:
+		return newNDJSONTargetReader(r), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported import format %q", apperrors.ErrValidation, format)
+	}
+}
+
+// csvTargetReader reads CSV with a header row. The phone_number (or phone) column is
+// required; every other non-empty column is copied into the target payload.
+type csvTargetReader struct {
+	r        *csv.Reader
+	header   []string
+	phoneIdx int
+}
+
+func newCSVTargetReader(r io.Reader) (*csvTargetReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: upload is empty", apperrors.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: read csv header: %v", apperrors.ErrValidation, err)
+	}
+
+	phoneIdx := -1
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		header[i] = name
+		if phoneIdx < 0 && (name == "phone_number" || name == "phone") {
+			phoneIdx = i
+		}
+	}
+	if phoneIdx < 0 {
+		return nil, fmt.Errorf("%w: csv header must include a phone_number column", apperrors.ErrValidation)
+	}
+
+	return &csvTargetReader{r: cr, header: header, phoneIdx: phoneIdx}, nil
+}
+
+func (c *csvTargetReader) Next() (TargetInput, int, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return TargetInput{}, parseErr.Line, &rowError{line: parseErr.Line, err: parseErr.Err}
+		}
+		if errors.Is(err, io.EOF) {
+			return TargetInput{}, 0, io.EOF
+		}
+		return TargetInput{}, 0, fmt.Errorf("campaign service: read csv: %w", err)
+	}
+	line, _ := c.r.FieldPos(0)
+
+	if c.phoneIdx >= len(record) {
+		return TargetInput{}, line, &rowError{line: line, err: errors.New("missing phone_number")}
+	}
+	phone := strings.TrimSpace(record[c.phoneIdx])
+	if phone == "" {
+		return TargetInput{}, line, &rowError{line: line, err: errors.New("missing phone_number")}
+	}
+	if len(record) > len(c.header) {
+		return TargetInput{}, line, &rowError{line: line, err: fmt.Errorf("row has %d columns, header has %d", len(record), len(c.header))}
+	}
+
+	var payload map[string]any
+	for i, value := range record {
+		if i == c.phoneIdx || c.header[i] == "" || value == "" {
+			continue
+		}
+		if payload == nil {
+			payload = make(map[string]any, len(record)-1)
+		}
+		payload[c.header[i]] = value
+	}
+	return TargetInput{PhoneNumber: phone, Payload: payload}, line, nil
+}
+
+// ndjsonTargetReader reads one JSON object per line. phone_number is required; every other
+// key is copied into the target payload. Blank lines are ignored.
+type ndjsonTargetReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func newNDJSONTargetReader(r io.Reader) *ndjsonTargetReader {
+	return &ndjsonTargetReader{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+func (n *ndjsonTargetReader) Next() (TargetInput, int, error) {
+	for {
+		raw, err := n.readLine()
+		if err != nil {
+			return TargetInput{}, n.line, err
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+
+		var row map[string]any
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return TargetInput{}, n.line, &rowError{line: n.line, err: fmt.Errorf("invalid json: %v", err)}
+		}
+		phone, _ := row["phone_number"].(string)
+		phone = strings.TrimSpace(phone)
+		if phone == "" {
+			return TargetInput{}, n.line, &rowError{line: n.line, err: errors.New("missing phone_number")}
+		}
+		delete(row, "phone_number")
+		if len(row) == 0 {
+			row = nil
+		}
+		return TargetInput{PhoneNumber: phone, Payload: row}, n.line, nil
+	}
+}
+
+// readLine returns the next line without its terminator. Lines longer than maxNDJSONLine are
+// discarded and reported as a row error.
+func (n *ndjsonTargetReader) readLine() ([]byte, error) {
+	var buf []byte
+	tooLong := false
+	for {
+		chunk, isPrefix, err := n.r.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if len(buf) > 0 || tooLong {
+					break
+				}
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("campaign service: read ndjson: %w", err)
+		}
+		if !tooLong {
+			buf = append(buf, chunk...)
+			if len(buf) > maxNDJSONLine {
+				tooLong = true
+				buf = nil
+			}
+		}
+		if !isPrefix {
+			break
+		}
+	}
+	n.line++
+	if tooLong {
+		return nil, &rowError{line: n.line, err: fmt.Errorf("line exceeds %d bytes", maxNDJSONLine)}
+	}
+	return buf, nil
+}
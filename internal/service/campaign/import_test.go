@@ -0,0 +1,90 @@
+package campaign
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, r targetReader) ([]TargetInput, []int) {
+	t.Helper()
+	var (
+		targets []TargetInput
+		bad     []int
+	)
+	for {
+		target, _, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return targets, bad
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			bad = append(bad, rowErr.line)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		targets = append(targets, target)
+	}
+}
+
+func TestCSVTargetReader(t *testing.T) {
+	input := "Phone_Number,first_name,plan\n" +
+		"+14155550100,Ada,gold\n" +
+		",Missing,silver\n" +
+		"+14155550101,,\n" +
+		"+14155550102,Bob,gold,extra\n"
+
+	reader, err := newCSVTargetReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	targets, bad := readAll(t, reader)
+
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].PhoneNumber != "+14155550100" || targets[0].Payload["first_name"] != "Ada" || targets[0].Payload["plan"] != "gold" {
+		t.Errorf("unexpected first target %+v", targets[0])
+	}
+	if targets[1].Payload != nil {
+		t.Errorf("expected empty columns to be dropped, got %+v", targets[1].Payload)
+	}
+	if len(bad) != 2 || bad[0] != 3 || bad[1] != 5 {
+		t.Errorf("expected rejected lines [3 5], got %v", bad)
+	}
+}
+
+func TestCSVTargetReaderRequiresPhoneColumn(t *testing.T) {
+	if _, err := newCSVTargetReader(strings.NewReader("name,plan\nAda,gold\n")); err == nil {
+		t.Fatal("expected error for header without phone_number")
+	}
+	if _, err := newCSVTargetReader(strings.NewReader("")); err == nil {
+		t.Fatal("expected error for empty upload")
+	}
+}
+
+func TestNDJSONTargetReader(t *testing.T) {
+	input := `{"phone_number":"+14155550100","first_name":"Ada","tier":2}` + "\n" +
+		"\n" +
+		`{"first_name":"NoPhone"}` + "\n" +
+		`not json` + "\n" +
+		`{"phone_number":"+14155550101"}`
+
+	targets, bad := readAll(t, newNDJSONTargetReader(strings.NewReader(input)))
+
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].Payload["first_name"] != "Ada" || targets[0].Payload["tier"] != float64(2) {
+		t.Errorf("unexpected payload %+v", targets[0].Payload)
+	}
+	if _, ok := targets[0].Payload["phone_number"]; ok {
+		t.Error("phone_number should not be copied into the payload")
+	}
+	if len(bad) != 2 || bad[0] != 3 || bad[1] != 4 {
+		t.Errorf("expected rejected lines [3 4], got %v", bad)
+	}
+}
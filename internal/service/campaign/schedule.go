@@ -0,0 +1,71 @@
+package campaign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// maxScheduleRange bounds a schedule preview.
+const maxScheduleRange = 31 * 24 * time.Hour
+
+// Schedule lists the UTC intervals in which a campaign may dial within a requested range.
+type Schedule struct {
+	Campaign  *domain.Campaign
+	From      time.Time
+	To        time.Time
+	Intervals []domain.Interval
+	NextOpen  *time.Time
+}
+
+// Schedule previews when the campaign will dial between from and to. Business hours are
+// further limited by the campaign's scheduled start (until it has started) and end; finished
+// campaigns have no intervals.
+func (s *Service) Schedule(ctx context.Context, id uuid.UUID, from, to time.Time) (*Schedule, error) {
+	from, to = from.UTC(), to.UTC()
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", apperrors.ErrValidation)
+	}
+	if to.Sub(from) > maxScheduleRange {
+		return nil, fmt.Errorf("%w: schedule range may not exceed %d days", apperrors.ErrValidation, int(maxScheduleRange.Hours()/24))
+	}
+
+	campaign, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &Schedule{Campaign: campaign, From: from, To: to, Intervals: []domain.Interval{}}
+	if campaign.Status.IsTerminal() {
+		return schedule, nil
+	}
+
+	start, end := from, to
+	if campaign.StartedAt == nil && campaign.StartAt != nil && campaign.StartAt.After(start) {
+		start = campaign.StartAt.UTC()
+	}
+	if campaign.EndAt != nil && campaign.EndAt.Before(end) {
+		end = campaign.EndAt.UTC()
+	}
+
+	intervals, err := campaign.OpenIntervals(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(intervals) > 0 {
+		schedule.Intervals = intervals
+	}
+
+	next, ok, err := campaign.NextOpen(start)
+	if err != nil {
+		return nil, err
+	}
+	if ok && (campaign.EndAt == nil || next.Before(*campaign.EndAt)) {
+		schedule.NextOpen = &next
+	}
+	return schedule, nil
+}
@@ -2,7 +2,10 @@ package campaign
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,14 +13,19 @@ import (
 	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/repository"
 	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+	"github.com/acme/outbound-call-campaign/pkg/phone"
 )
 
 // Service orchestrates campaign lifecycle operations.
 type Service struct {
 	repo          repository.CampaignRepository
 	hoursRepo     repository.BusinessHourRepository
+	calendarRepo  repository.HolidayCalendarRepository
 	targetRepo    repository.CampaignTargetRepository
 	statsRepo     repository.CampaignStatisticsRepository
+	eventsRepo    repository.CampaignEventRepository
+	logger        *logger.Logger
 	defaultConcurrency int
 }
 
@@ -25,15 +33,21 @@ type Service struct {
 func NewService(
 	repo repository.CampaignRepository,
 	hours repository.BusinessHourRepository,
+	calendars repository.HolidayCalendarRepository,
 	targets repository.CampaignTargetRepository,
 	stats repository.CampaignStatisticsRepository,
+	events repository.CampaignEventRepository,
+	lg *logger.Logger,
 	defaultConcurrency int,
 ) *Service {
 	return &Service{
 		repo: repo,
 		hoursRepo: hours,
+		calendarRepo: calendars,
 		targetRepo: targets,
 		statsRepo: stats,
+		eventsRepo: events,
+		logger: lg,
 		defaultConcurrency: defaultConcurrency,
 	}
 }
@@ -43,10 +57,19 @@ type CreateCampaignInput struct {
 	Name               string
 	Description        string
 	TimeZone           string
+	DefaultCountry     string
+	RecipientLocalTime bool
 	MaxConcurrentCalls int
+	Priority           int
+	Weight             int
 	RetryPolicy        domain.RetryPolicy
 	BusinessHours      []BusinessHourInput
+	HolidayCalendarIDs []uuid.UUID
+	Blackouts          []domain.BlackoutDate
+	OverrideWindows    []domain.OverrideWindow
 	Targets            []TargetInput
+	StartAt            *time.Time
+	EndAt              *time.Time
 }
 
 // BusinessHourInput expresses a business hour window.
@@ -56,6 +79,10 @@ type BusinessHourInput struct {
 	End       time.Time
 }
 
+// TargetTimeZoneField is the payload field that sets a target's time zone explicitly, overriding
+// the zone derived from its number.
+const TargetTimeZoneField = "time_zone"
+
 // TargetInput expresses a campaign target phone number.
 type TargetInput struct {
 	PhoneNumber string
@@ -67,9 +94,18 @@ type UpdateCampaignInput struct {
 	ID                 uuid.UUID
 	Name               *string
 	Description        *string
+	DefaultCountry     *string
+	RecipientLocalTime *bool
 	MaxConcurrentCalls *int
+	Priority           *int
+	Weight             *int
 	RetryPolicy        *domain.RetryPolicy
 	BusinessHours      *[]BusinessHourInput
+	HolidayCalendarIDs *[]uuid.UUID
+	Blackouts          *[]domain.BlackoutDate
+	OverrideWindows    *[]domain.OverrideWindow
+	StartAt            *time.Time
+	EndAt              *time.Time
 }
 
 // Create provisions a new campaign.
@@ -84,13 +120,29 @@ func (s *Service) Create(ctx context.Context, input CreateCampaignInput) (*domai
 		Name:               input.Name,
 		Description:        input.Description,
 		TimeZone:           input.TimeZone,
+		DefaultCountry:     strings.ToUpper(input.DefaultCountry),
+		RecipientLocalTime: input.RecipientLocalTime,
 		MaxConcurrentCalls: s.resolveConcurrency(input.MaxConcurrentCalls),
+		Priority:           input.Priority,
+		Weight:             resolveWeight(input.Weight),
 		RetryPolicy:        normalizeRetry(input.RetryPolicy),
 		Status:             domain.CampaignStatusPending,
+		StartAt:            utcPtr(input.StartAt),
+		EndAt:              utcPtr(input.EndAt),
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
 
+	records, err := newTargetRecords(campaign, input.Targets, now)
+	if err != nil {
+		return nil, err
+	}
+	calendarIDs, err := s.validateCalendars(ctx, input.HolidayCalendarIDs)
+	if err != nil {
+		return nil, err
+	}
+	blackouts, overrides := normalizeBlackouts(input.Blackouts), normalizeOverrides(input.OverrideWindows)
+
 	if err := s.repo.Create(ctx, campaign); err != nil {
 		return nil, fmt.Errorf("campaign service: create campaign: %w", err)
 	}
@@ -99,41 +151,51 @@ func (s *Service) Create(ctx context.Context, input CreateCampaignInput) (*domai
 		return nil, fmt.Errorf("campaign service: store business hours: %w", err)
 	}
 
+	if err := s.hoursRepo.ReplaceExceptions(ctx, campaign.ID, blackouts, overrides); err != nil {
+		return nil, fmt.Errorf("campaign service: store date exceptions: %w", err)
+	}
+
+	if err := s.calendarRepo.SetCampaignCalendars(ctx, campaign.ID, calendarIDs); err != nil {
+		return nil, fmt.Errorf("campaign service: store holiday calendars: %w", err)
+	}
+
 	if err := s.statsRepo.Ensure(ctx, campaign.ID); err != nil {
 		return nil, fmt.Errorf("campaign service: ensure stats: %w", err)
 	}
 
-	if len(input.Targets) > 0 {
-		records := make([]repository.CampaignTargetRecord, 0, len(input.Targets))
-		for _, t := range input.Targets {
-			records = append(records, repository.CampaignTargetRecord{
-				ID:          uuid.New(),
-				CampaignID:  campaign.ID,
-				PhoneNumber: t.PhoneNumber,
-				Payload:     t.Payload,
-				State:       "pending",
-				CreatedAt:   now,
-			})
-		}
-		if err := s.targetRepo.BulkInsert(ctx, campaign.ID, records); err != nil {
+	if len(records) > 0 {
+		if _, err := s.targetRepo.BulkInsert(ctx, campaign.ID, dedupeTargetRecords(records), domain.DuplicatePolicySkip); err != nil {
 			return nil, fmt.Errorf("campaign service: store targets: %w", err)
 		}
 	}
 
+	campaign.BusinessHours = toDomainBusinessHours(input.BusinessHours)
+	campaign.HolidayCalendarIDs = calendarIDs
+	campaign.Blackouts = blackouts
+	campaign.OverrideWindows = overrides
+	if campaign.Holidays, err = s.calendarRepo.ListCampaignHolidays(ctx, campaign.ID); err != nil {
+		return nil, fmt.Errorf("campaign service: list holidays: %w", err)
+	}
+	created := createdSnapshot{
+		campaignSnapshot: snapshotCampaign(campaign),
+		BusinessHours:    snapshotBusinessHours(campaign.BusinessHours),
+		Calendar:         snapshotCalendar(campaign),
+		Targets:          len(input.Targets),
+	}
+	s.recordEvent(ctx, campaign.ID, domain.CampaignEventCreated, nil, created)
+
 	return campaign, nil
 }
 
-// Get retrieves a campaign by id including business hours.
+// Get retrieves a campaign by id including its business hours and date rules.
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
 	campaign, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	windows, err := s.hoursRepo.List(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("campaign service: list business hours: %w", err)
+	if err := s.loadSchedule(ctx, campaign); err != nil {
+		return nil, err
 	}
-	campaign.BusinessHours = windows
 	return campaign, nil
 }
 
@@ -146,28 +208,71 @@ func (s *Service) List(ctx context.Context, afterID *uuid.UUID, limit int) ([]*d
 	return campaigns, nil
 }
 
-// ListByStatus returns campaigns filtered by status with business hours populated.
+// ListByStatus returns campaigns filtered by status with business hours and date rules populated.
 func (s *Service) ListByStatus(ctx context.Context, status domain.CampaignStatus, limit int) ([]*domain.Campaign, error) {
 	campaigns, err := s.repo.ListByStatus(ctx, status, limit)
 	if err != nil {
 		return nil, err
 	}
-	for _, c := range campaigns {
-		windows, err := s.hoursRepo.List(ctx, c.ID)
-		if err != nil {
-			return nil, fmt.Errorf("campaign service: list business hours: %w", err)
-		}
-		c.BusinessHours = windows
+	if err := s.loadSchedules(ctx, campaigns...); err != nil {
+		return nil, err
 	}
 	return campaigns, nil
 }
 
+// loadSchedule populates the business hours, holiday calendars and date exceptions that decide
+// when the campaign may dial.
+func (s *Service) loadSchedule(ctx context.Context, campaign *domain.Campaign) error {
+	return s.loadSchedules(ctx, campaign)
+}
+
+// loadSchedules populates the schedules of several campaigns with one query per kind of rule,
+// however many campaigns there are.
+func (s *Service) loadSchedules(ctx context.Context, campaigns ...*domain.Campaign) error {
+	if len(campaigns) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(campaigns))
+	for _, c := range campaigns {
+		ids = append(ids, c.ID)
+	}
+
+	windows, err := s.hoursRepo.ListByCampaigns(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("campaign service: list business hours: %w", err)
+	}
+	exceptions, err := s.hoursRepo.ListExceptionsByCampaigns(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("campaign service: list date exceptions: %w", err)
+	}
+	calendarIDs, err := s.calendarRepo.ListCalendarsByCampaigns(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("campaign service: list holiday calendars: %w", err)
+	}
+	holidays, err := s.calendarRepo.ListHolidaysByCampaigns(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("campaign service: list holidays: %w", err)
+	}
+	for _, c := range campaigns {
+		c.BusinessHours = windows[c.ID]
+		c.Blackouts = exceptions[c.ID].Blackouts
+		c.OverrideWindows = exceptions[c.ID].Overrides
+		c.HolidayCalendarIDs = calendarIDs[c.ID]
+		c.Holidays = holidays[c.ID]
+	}
+	return nil
+}
+
 // Update modifies campaign metadata.
 func (s *Service) Update(ctx context.Context, input UpdateCampaignInput) (*domain.Campaign, error) {
 	campaign, err := s.repo.Get(ctx, input.ID)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDateRuleUpdate(input); err != nil {
+		return nil, err
+	}
+	before := snapshotCampaign(campaign)
 
 	if input.Name != nil {
 		campaign.Name = *input.Name
@@ -175,29 +280,131 @@ func (s *Service) Update(ctx context.Context, input UpdateCampaignInput) (*domai
 	if input.Description != nil {
 		campaign.Description = *input.Description
 	}
+	if input.DefaultCountry != nil {
+		if err := validateDefaultCountry(*input.DefaultCountry); err != nil {
+			return nil, err
+		}
+		campaign.DefaultCountry = strings.ToUpper(*input.DefaultCountry)
+	}
+	if input.RecipientLocalTime != nil {
+		campaign.RecipientLocalTime = *input.RecipientLocalTime
+	}
 	if input.MaxConcurrentCalls != nil {
 		campaign.MaxConcurrentCalls = s.resolveConcurrency(*input.MaxConcurrentCalls)
 	}
+	if input.Priority != nil {
+		campaign.Priority = *input.Priority
+	}
+	if input.Weight != nil {
+		if err := validateWeight(*input.Weight); err != nil {
+			return nil, err
+		}
+		campaign.Weight = resolveWeight(*input.Weight)
+	}
 	if input.RetryPolicy != nil {
 		campaign.RetryPolicy = normalizeRetry(*input.RetryPolicy)
 	}
+	if input.StartAt != nil {
+		campaign.StartAt = utcPtr(input.StartAt)
+	}
+	if input.EndAt != nil {
+		campaign.EndAt = utcPtr(input.EndAt)
+	}
+	if err := validateSchedule(campaign.StartAt, campaign.EndAt, time.Now().UTC(), input.EndAt != nil); err != nil {
+		return nil, err
+	}
 
 	campaign.UpdatedAt = time.Now().UTC()
 
 	if err := s.repo.Update(ctx, campaign); err != nil {
 		return nil, err
 	}
+	s.recordChange(ctx, campaign.ID, domain.CampaignEventUpdated, before, snapshotCampaign(campaign))
 
 	if input.BusinessHours != nil {
-		if err := s.hoursRepo.Replace(ctx, campaign.ID, toDomainBusinessHours(*input.BusinessHours)); err != nil {
+		previous, err := s.hoursRepo.List(ctx, campaign.ID)
+		if err != nil {
+			return nil, fmt.Errorf("campaign service: list business hours: %w", err)
+		}
+		windows := toDomainBusinessHours(*input.BusinessHours)
+		if err := s.hoursRepo.Replace(ctx, campaign.ID, windows); err != nil {
 			return nil, fmt.Errorf("campaign service: update business hours: %w", err)
 		}
+		s.recordChange(ctx, campaign.ID, domain.CampaignEventBusinessHoursChanged, snapshotBusinessHours(previous), snapshotBusinessHours(windows))
+	}
+
+	if input.HolidayCalendarIDs != nil || input.Blackouts != nil || input.OverrideWindows != nil {
+		if err := s.updateDateRules(ctx, campaign, input); err != nil {
+			return nil, err
+		}
 	}
 
 	return campaign, nil
 }
 
-// Start transitions a campaign into in-progress state.
+// updateDateRules applies the calendar, blackout and override changes in input, keeping the
+// parts it does not set.
+func (s *Service) updateDateRules(ctx context.Context, campaign *domain.Campaign, input UpdateCampaignInput) error {
+	if err := s.loadSchedule(ctx, campaign); err != nil {
+		return err
+	}
+	before := snapshotCalendar(campaign)
+
+	if input.HolidayCalendarIDs != nil {
+		ids, err := s.validateCalendars(ctx, *input.HolidayCalendarIDs)
+		if err != nil {
+			return err
+		}
+		if err := s.calendarRepo.SetCampaignCalendars(ctx, campaign.ID, ids); err != nil {
+			return fmt.Errorf("campaign service: update holiday calendars: %w", err)
+		}
+		campaign.HolidayCalendarIDs = ids
+		if campaign.Holidays, err = s.calendarRepo.ListCampaignHolidays(ctx, campaign.ID); err != nil {
+			return fmt.Errorf("campaign service: list holidays: %w", err)
+		}
+	}
+
+	if input.Blackouts != nil || input.OverrideWindows != nil {
+		blackouts, overrides := campaign.Blackouts, campaign.OverrideWindows
+		if input.Blackouts != nil {
+			blackouts = normalizeBlackouts(*input.Blackouts)
+		}
+		if input.OverrideWindows != nil {
+			overrides = normalizeOverrides(*input.OverrideWindows)
+		}
+		if err := validateDateExceptions(blackouts, overrides); err != nil {
+			return err
+		}
+		if err := s.hoursRepo.ReplaceExceptions(ctx, campaign.ID, blackouts, overrides); err != nil {
+			return fmt.Errorf("campaign service: update date exceptions: %w", err)
+		}
+		campaign.Blackouts, campaign.OverrideWindows = blackouts, overrides
+	}
+
+	s.recordChange(ctx, campaign.ID, domain.CampaignEventCalendarChanged, before, snapshotCalendar(campaign))
+	return nil
+}
+
+// validateCalendars checks that every calendar exists and returns the ids without repeats.
+func (s *Service) validateCalendars(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if slices.Contains(unique, id) {
+			continue
+		}
+		if _, err := s.calendarRepo.Get(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("%w: holiday calendar %s not found", apperrors.ErrValidation, id)
+			}
+			return nil, fmt.Errorf("campaign service: lookup holiday calendar: %w", err)
+		}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// Start transitions a pending campaign into in-progress state. Starting a paused campaign
+// resumes it.
 func (s *Service) Start(ctx context.Context, id uuid.UUID) error {
 	campaign, err := s.repo.Get(ctx, id)
 	if err != nil {
@@ -207,29 +414,63 @@ func (s *Service) Start(ctx context.Context, id uuid.UUID) error {
 	if campaign.Status == domain.CampaignStatusInProgress {
 		return nil
 	}
-	if campaign.Status == domain.CampaignStatusCompleted {
-		return fmt.Errorf("campaign service: cannot start completed campaign")
+	if campaign.Status == domain.CampaignStatusPaused {
+		return s.Resume(ctx, id)
+	}
+	if err := campaign.Status.ValidateTransition(domain.CampaignStatusInProgress); err != nil {
+		return err
 	}
 
 	now := time.Now().UTC()
+	from := campaign.Status
 	campaign.Status = domain.CampaignStatusInProgress
 	campaign.StartedAt = &now
-	if err := s.repo.Update(ctx, campaign); err != nil {
-		return err
+	if err := s.repo.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: from}); err != nil {
+		return fmt.Errorf("campaign service: start: %w", err)
 	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventStarted, from)
 	return nil
 }
 
-// Pause transitions a campaign to paused state.
+// Pause transitions an in-progress campaign to paused state.
 func (s *Service) Pause(ctx context.Context, id uuid.UUID) error {
 	campaign, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return err
 	}
+	if campaign.Status == domain.CampaignStatusPaused {
+		return nil
+	}
+	if err := campaign.Status.ValidateTransition(domain.CampaignStatusPaused); err != nil {
+		return err
+	}
+	from := campaign.Status
 	campaign.Status = domain.CampaignStatusPaused
-	if err := s.repo.Update(ctx, campaign); err != nil {
+	if err := s.repo.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: from}); err != nil {
+		return fmt.Errorf("campaign service: pause: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventPaused, from)
+	return nil
+}
+
+// Resume transitions a paused campaign back to in-progress state.
+func (s *Service) Resume(ctx context.Context, id uuid.UUID) error {
+	campaign, err := s.repo.Get(ctx, id)
+	if err != nil {
 		return err
 	}
+	if campaign.Status == domain.CampaignStatusInProgress {
+		return nil
+	}
+	if campaign.Status != domain.CampaignStatusPaused {
+		return fmt.Errorf("%w: only paused campaigns can be resumed (status %s)", apperrors.ErrConflict, campaign.Status)
+	}
+	from := campaign.Status
+	campaign.Status = domain.CampaignStatusInProgress
+	if err := s.repo.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: from}); err != nil {
+		return fmt.Errorf("campaign service: resume: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventResumed, from)
 	return nil
 }
 
@@ -239,15 +480,189 @@ func (s *Service) Complete(ctx context.Context, id uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	if campaign.Status == domain.CampaignStatusCompleted {
+		return nil
+	}
+	if err := campaign.Status.ValidateTransition(domain.CampaignStatusCompleted); err != nil {
+		return err
+	}
+	counts, stats, err := s.progress(ctx, id)
+	if err != nil {
+		return err
+	}
 	now := time.Now().UTC()
+	from := campaign.Status
 	campaign.Status = domain.CampaignStatusCompleted
 	campaign.CompletedAt = &now
-	if err := s.repo.Update(ctx, campaign); err != nil {
+	campaign.CompletionSummary = buildCompletionSummary(counts, stats, now, false)
+	if err := s.repo.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: from}); err != nil {
+		return fmt.Errorf("campaign service: complete: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventCompleted, from)
+	return nil
+}
+
+// StartDue starts pending campaigns whose scheduled start time has passed and returns their ids.
+func (s *Service) StartDue(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	due, err := s.repo.ListDueToStart(ctx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign service: list due campaigns: %w", err)
+	}
+
+	var errs []error
+	started := make([]uuid.UUID, 0, len(due))
+	for _, campaign := range due {
+		if err := s.Start(ctx, campaign.ID); err != nil {
+			errs = append(errs, fmt.Errorf("campaign service: start scheduled campaign %s: %w", campaign.ID, err))
+			continue
+		}
+		started = append(started, campaign.ID)
+	}
+	return started, errors.Join(errs...)
+}
+
+// EndExpired completes campaigns whose scheduled end time has passed, expiring any targets
+// that were never dialed, and returns their ids. Pending campaigns whose window closed before
+// they were started end the same way. Dispatches already queued carry the end time and are
+// skipped by the call worker.
+func (s *Service) EndExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	expired, err := s.repo.ListPastEnd(ctx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign service: list expired campaigns: %w", err)
+	}
+
+	var errs []error
+	ended := make([]uuid.UUID, 0, len(expired))
+	for _, campaign := range expired {
+		err := s.expire(ctx, campaign)
+		if errors.Is(err, repository.ErrConflict) {
+			// Cancelled or completed since it was listed.
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("campaign service: end campaign %s: %w", campaign.ID, err))
+			continue
+		}
+		ended = append(ended, campaign.ID)
+	}
+	return ended, errors.Join(errs...)
+}
+
+// expire completes the campaign and expires its remaining targets together. The completion is
+// decided on the status ListPastEnd read, so a campaign cancelled or completed since is left as it
+// is.
+func (s *Service) expire(ctx context.Context, campaign *domain.Campaign) error {
+	if err := campaign.Status.ValidateTransition(domain.CampaignStatusCompleted); err != nil {
+		return err
+	}
+	counts, stats, err := s.progress(ctx, campaign.ID)
+	if err != nil {
+		return err
+	}
+	// The summary counts the targets as the transition leaves them.
+	for _, state := range domain.ActiveTargetStates {
+		if n, ok := counts[state]; ok {
+			counts[domain.TargetStateExpired] += n
+			delete(counts, state)
+		}
+	}
+
+	now := time.Now().UTC()
+	from := campaign.Status
+	campaign.Status = domain.CampaignStatusCompleted
+	campaign.CompletedAt = &now
+	campaign.CompletionSummary = buildCompletionSummary(counts, stats, now, true)
+	err = s.repo.Transition(ctx, repository.CampaignTransition{
+		Campaign:    campaign,
+		From:        from,
+		TargetsFrom: domain.ActiveTargetStates,
+		TargetsTo:   domain.TargetStateExpired,
+	})
+	if err != nil {
+		return fmt.Errorf("campaign service: complete expired campaign: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventCompleted, from)
+	return nil
+}
+
+// Cancel stops a campaign permanently and marks its remaining targets as cancelled.
+// Dispatch and retry messages already queued are dropped by the workers when they read them.
+func (s *Service) Cancel(ctx context.Context, id uuid.UUID) error {
+	campaign, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if campaign.Status == domain.CampaignStatusCancelled {
+		return nil
+	}
+	if err := campaign.Status.ValidateTransition(domain.CampaignStatusCancelled); err != nil {
 		return err
 	}
+
+	from := campaign.Status
+	campaign.Status = domain.CampaignStatusCancelled
+	err = s.repo.Transition(ctx, repository.CampaignTransition{
+		Campaign:    campaign,
+		From:        from,
+		TargetsFrom: domain.ActiveTargetStates,
+		TargetsTo:   domain.TargetStateCancelled,
+	})
+	if err != nil {
+		return fmt.Errorf("campaign service: cancel: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventCancelled, from)
 	return nil
 }
 
+// CompleteIfDrained completes an in-progress campaign once every target has reached a terminal
+// state and no calls or retries are outstanding. The returned flag reports whether it completed.
+func (s *Service) CompleteIfDrained(ctx context.Context, id uuid.UUID) (*domain.CompletionSummary, bool, error) {
+	campaign, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if campaign.Status != domain.CampaignStatusInProgress {
+		return nil, false, nil
+	}
+
+	counts, stats, err := s.progress(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isDrained(counts, stats) {
+		return nil, false, nil
+	}
+
+	// The campaign may be paused or cancelled while its progress is read; completing it only
+	// from in_progress leaves such a change in place.
+	now := time.Now().UTC()
+	summary := buildCompletionSummary(counts, stats, now, true)
+	campaign.Status = domain.CampaignStatusCompleted
+	campaign.CompletedAt = &now
+	campaign.CompletionSummary = summary
+	err = s.repo.Transition(ctx, repository.CampaignTransition{Campaign: campaign, From: domain.CampaignStatusInProgress})
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("campaign service: complete drained campaign: %w", err)
+	}
+	s.recordStatusChange(ctx, campaign, domain.CampaignEventCompleted, domain.CampaignStatusInProgress)
+	return summary, true, nil
+}
+
+func (s *Service) progress(ctx context.Context, id uuid.UUID) (map[string]int64, *domain.CampaignStats, error) {
+	counts, err := s.targetRepo.CountByState(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("campaign service: count targets: %w", err)
+	}
+	stats, err := s.statsRepo.Get(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("campaign service: get stats: %w", err)
+	}
+	return counts, stats, nil
+}
+
 // Stats retrieves aggregated statistics.
 func (s *Service) Stats(ctx context.Context, id uuid.UUID) (*domain.CampaignStats, error) {
 	stats, err := s.statsRepo.Get(ctx, id)
@@ -257,53 +672,179 @@ func (s *Service) Stats(ctx context.Context, id uuid.UUID) (*domain.CampaignStat
 	return stats, nil
 }
 
-// AddTargets appends targets to a campaign, validating they are part of the campaign's registered targets.
-func (s *Service) AddTargets(ctx context.Context, campaignID uuid.UUID, targets []TargetInput) error {
-	if len(targets) == 0 {
-		return nil
+// TargetError describes a target rejected from an AddTargets request. Index is its position in the request.
+type TargetError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// AddTargetsResult reports what AddTargets did with each submitted target.
+type AddTargetsResult struct {
+	Inserted int           `json:"inserted"`
+	Reset    int           `json:"reset"`
+	Skipped  int           `json:"skipped"`
+	Rejected int           `json:"rejected"`
+	Errors   []TargetError `json:"errors"`
+}
+
+func (r *AddTargetsResult) reject(index int, err error) {
+	r.Rejected++
+	r.Errors = append(r.Errors, TargetError{Index: index, Error: err.Error()})
+}
+
+// AddTargets appends targets to a campaign. Invalid numbers are rejected individually; numbers
+// already in the campaign are handled according to policy.
+func (s *Service) AddTargets(ctx context.Context, campaignID uuid.UUID, targets []TargetInput, policy domain.DuplicatePolicy) (*AddTargetsResult, error) {
+	campaign, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status.IsTerminal() {
+		return nil, fmt.Errorf("%w: cannot add targets to a %s campaign", apperrors.ErrConflict, campaign.Status)
+	}
+
+	result := &AddTargetsResult{Errors: []TargetError{}}
+	duplicate := func(index int, phone string) {
+		if policy == domain.DuplicatePolicyError {
+			result.reject(index, fmt.Errorf("%s is already in the campaign", phone))
+			return
+		}
+		result.Skipped++
 	}
 
-	// Get all existing targets for this campaign to validate against
-	existingTargets, err := s.targetRepo.ListByCampaign(ctx, campaignID, 10000, "") // Get all targets, no state filter
+	now := time.Now().UTC()
+	records := make([]repository.CampaignTargetRecord, 0, len(targets))
+	indexes := make(map[string]int, len(targets))
+	for i, t := range targets {
+		record, err := newTargetRecord(campaign, t, now)
+		if err != nil {
+			result.reject(i, err)
+			continue
+		}
+		if _, seen := indexes[record.PhoneNumber]; seen {
+			duplicate(i, record.PhoneNumber)
+			continue
+		}
+		indexes[record.PhoneNumber] = i
+		records = append(records, record)
+	}
+
+	inserted, err := s.targetRepo.BulkInsert(ctx, campaignID, records, policy)
 	if err != nil {
-		return fmt.Errorf("campaign service: get existing targets: %w", err)
+		return nil, fmt.Errorf("campaign service: add targets: %w", err)
+	}
+	result.Inserted = inserted.Inserted
+	result.Reset = inserted.Reset
+	for _, phone := range inserted.Existing {
+		duplicate(indexes[phone], phone)
 	}
+	slices.SortFunc(result.Errors, func(a, b TargetError) int { return a.Index - b.Index })
 
-	// Campaign must have registered targets to allow adding more targets
-	if len(existingTargets) == 0 {
-		return fmt.Errorf("%w: cannot add targets to a campaign that has no registered target list", apperrors.ErrValidation)
+	if result.Inserted == 0 && result.Reset == 0 {
+		return result, nil
 	}
+	added := targetsAddedSnapshot{Count: result.Inserted, Reset: result.Reset}
+	s.recordEvent(ctx, campaignID, domain.CampaignEventTargetsAdded, nil, added)
+	return result, nil
+}
 
-	// Create a set of allowed phone numbers from existing campaign targets
-	allowedPhones := make(map[string]bool)
-	for _, target := range existingTargets {
-		allowedPhones[target.PhoneNumber] = true
+// newTargetRecords normalises each target number to E.164 using the campaign's default country.
+func newTargetRecords(campaign *domain.Campaign, targets []TargetInput, now time.Time) ([]repository.CampaignTargetRecord, error) {
+	records := make([]repository.CampaignTargetRecord, 0, len(targets))
+	for i, t := range targets {
+		record, err := newTargetRecord(campaign, t, now)
+		if err != nil {
+			return nil, fmt.Errorf("targets[%d]: %w", i, err)
+		}
+		records = append(records, record)
 	}
+	return records, nil
+}
 
-	// Validate that all targets being added are from the registered campaign target list
-	for _, t := range targets {
-		if !allowedPhones[t.PhoneNumber] {
-			return fmt.Errorf("%w: phone number %s is not part of this campaign's registered target list", apperrors.ErrValidation, t.PhoneNumber)
+// dedupeTargetRecords drops repeated numbers, keeping the first occurrence.
+func dedupeTargetRecords(records []repository.CampaignTargetRecord) []repository.CampaignTargetRecord {
+	seen := make(map[string]struct{}, len(records))
+	unique := records[:0]
+	for _, r := range records {
+		if _, ok := seen[r.PhoneNumber]; ok {
+			continue
 		}
+		seen[r.PhoneNumber] = struct{}{}
+		unique = append(unique, r)
 	}
+	return unique
+}
 
-	now := time.Now().UTC()
-	records := make([]repository.CampaignTargetRecord, 0, len(targets))
-	for _, t := range targets {
-		records = append(records, repository.CampaignTargetRecord{
-			ID:          uuid.New(),
-			CampaignID:  campaignID,
-			PhoneNumber: t.PhoneNumber,
-			Payload:     t.Payload,
-			State:       "pending",
-			CreatedAt:   now,
-		})
+func newTargetRecord(campaign *domain.Campaign, target TargetInput, now time.Time) (repository.CampaignTargetRecord, error) {
+	number, err := phone.Normalize(target.PhoneNumber, campaign.DefaultCountry)
+	if err != nil {
+		return repository.CampaignTargetRecord{}, err
+	}
+	zone, err := targetTimeZone(number, target.Payload)
+	if err != nil {
+		return repository.CampaignTargetRecord{}, err
+	}
+	return repository.CampaignTargetRecord{
+		ID:          uuid.New(),
+		CampaignID:  campaign.ID,
+		PhoneNumber: number,
+		Payload:     target.Payload,
+		TimeZone:    zone,
+		State:       domain.TargetStatePending,
+		CreatedAt:   now,
+	}, nil
+}
+
+// targetTimeZone returns the recipient's time zone: the payload's time_zone field when set,
+// otherwise the zone of the number's prefix, or "" when neither is known.
+func targetTimeZone(number string, payload map[string]any) (string, error) {
+	if raw, ok := payload[TargetTimeZoneField]; ok && raw != nil && raw != "" {
+		zone, isString := raw.(string)
+		if !isString {
+			return "", fmt.Errorf("%w: %s must be a time zone name", apperrors.ErrValidation, TargetTimeZoneField)
+		}
+		zone = strings.TrimSpace(zone)
+		if _, err := time.LoadLocation(zone); err != nil || zone == "" || zone == "Local" {
+			return "", fmt.Errorf("%w: invalid %s %q", apperrors.ErrValidation, TargetTimeZoneField, zone)
+		}
+		return zone, nil
 	}
+	zone, _ := phone.TimeZone(number)
+	return zone, nil
+}
 
-	if err := s.targetRepo.BulkInsert(ctx, campaignID, records); err != nil {
-		return fmt.Errorf("campaign service: add targets: %w", err)
+// isDrained reports whether a campaign has targets and none of them, nor any call or retry, is still outstanding.
+func isDrained(counts map[string]int64, stats *domain.CampaignStats) bool {
+	var total int64
+	for _, n := range counts {
+		total += n
 	}
-	return nil
+	if total == 0 {
+		return false
+	}
+	for _, state := range domain.ActiveTargetStates {
+		if counts[state] > 0 {
+			return false
+		}
+	}
+	return stats.PendingCalls <= 0 && stats.InProgressCalls <= 0
+}
+
+func buildCompletionSummary(counts map[string]int64, stats *domain.CampaignStats, completedAt time.Time, automatic bool) *domain.CompletionSummary {
+	summary := &domain.CompletionSummary{
+		TargetsByState:   make(map[string]int64, len(counts)),
+		TotalCalls:       stats.TotalCalls,
+		CompletedCalls:   stats.CompletedCalls,
+		FailedCalls:      stats.FailedCalls,
+		RetriesAttempted: stats.RetriesAttempted,
+		Automatic:        automatic,
+		CompletedAt:      completedAt,
+	}
+	for state, n := range counts {
+		summary.TargetsByState[state] = n
+		summary.TotalTargets += n
+	}
+	return summary
 }
 
 func (s *Service) resolveConcurrency(value int) int {
@@ -313,6 +854,25 @@ func (s *Service) resolveConcurrency(value int) int {
 	return value
 }
 
+// resolveWeight gives campaigns created without a weight an equal share.
+func resolveWeight(value int) int {
+	if value <= 0 {
+		return 1
+	}
+	return value
+}
+
+// maxCampaignWeight bounds weights so a single campaign's quantum stays reasonable.
+const maxCampaignWeight = 1000
+
+// validateWeight accepts zero, meaning the default weight of 1.
+func validateWeight(weight int) error {
+	if weight < 0 || weight > maxCampaignWeight {
+		return fmt.Errorf("%w: weight must be between 1 and %d", apperrors.ErrValidation, maxCampaignWeight)
+	}
+	return nil
+}
+
 func normalizeRetry(policy domain.RetryPolicy) domain.RetryPolicy {
 	if policy.BaseDelay <= 0 {
 		policy.BaseDelay = 2 * time.Second
@@ -329,6 +889,33 @@ func normalizeRetry(policy domain.RetryPolicy) domain.RetryPolicy {
 	return policy
 }
 
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
+
+// validateSchedule checks the optional start/end window. A newly supplied end time must lie in the future.
+func validateSchedule(startAt, endAt *time.Time, now time.Time, endChanged bool) error {
+	if startAt != nil && endAt != nil && !endAt.After(*startAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", apperrors.ErrValidation)
+	}
+	if endChanged && endAt != nil && !endAt.After(now) {
+		return fmt.Errorf("%w: end_at must be in the future", apperrors.ErrValidation)
+	}
+	return nil
+}
+
+// validateDefaultCountry accepts an empty value, meaning targets must use international format.
+func validateDefaultCountry(region string) error {
+	if region != "" && !phone.SupportedRegion(region) {
+		return fmt.Errorf("%w: unsupported default country %q", apperrors.ErrValidation, region)
+	}
+	return nil
+}
+
 func toDomainBusinessHours(inputs []BusinessHourInput) []domain.BusinessHourWindow {
 	windows := make([]domain.BusinessHourWindow, 0, len(inputs))
 	for _, in := range inputs {
@@ -351,6 +938,15 @@ func validateCreateInput(input CreateCampaignInput) error {
 	if _, err := time.LoadLocation(input.TimeZone); err != nil {
 		return fmt.Errorf("%w: invalid time zone %s: %v", apperrors.ErrValidation, input.TimeZone, err)
 	}
+	if err := validateDefaultCountry(input.DefaultCountry); err != nil {
+		return err
+	}
+	if err := validateWeight(input.Weight); err != nil {
+		return err
+	}
+	if err := validateSchedule(input.StartAt, input.EndAt, time.Now().UTC(), true); err != nil {
+		return err
+	}
 	for _, bh := range input.BusinessHours {
 		startMinutes := bh.Start.Hour()*60 + bh.Start.Minute()
 		endMinutes := bh.End.Hour()*60 + bh.End.Minute()
@@ -358,5 +954,68 @@ func validateCreateInput(input CreateCampaignInput) error {
 			return fmt.Errorf("%w: business hour window must have positive duration", apperrors.ErrValidation)
 		}
 	}
+	return validateDateExceptions(input.Blackouts, input.OverrideWindows)
+}
+
+func validateDateRuleUpdate(input UpdateCampaignInput) error {
+	var blackouts []domain.BlackoutDate
+	var overrides []domain.OverrideWindow
+	if input.Blackouts != nil {
+		blackouts = *input.Blackouts
+	}
+	if input.OverrideWindows != nil {
+		overrides = *input.OverrideWindows
+	}
+	return validateDateExceptions(blackouts, overrides)
+}
+
+// validateDateExceptions rejects undated entries, empty override windows and dates that are
+// both blacked out and given override windows.
+func validateDateExceptions(blackouts []domain.BlackoutDate, overrides []domain.OverrideWindow) error {
+	closed := make(map[string]bool, len(blackouts))
+	for i, b := range blackouts {
+		if b.Date.IsZero() {
+			return fmt.Errorf("%w: blackout_dates[%d]: date is required", apperrors.ErrValidation, i)
+		}
+		closed[domain.DateKey(b.Date)] = true
+	}
+	for i, o := range overrides {
+		if o.Date.IsZero() {
+			return fmt.Errorf("%w: override_windows[%d]: date is required", apperrors.ErrValidation, i)
+		}
+		if o.Start.Hour()*60+o.Start.Minute() == o.End.Hour()*60+o.End.Minute() {
+			return fmt.Errorf("%w: override_windows[%d]: window must have positive duration", apperrors.ErrValidation, i)
+		}
+		if closed[domain.DateKey(o.Date)] {
+			return fmt.Errorf("%w: %s has both a blackout and override windows", apperrors.ErrValidation, domain.DateKey(o.Date))
+		}
+	}
 	return nil
 }
+
+// normalizeBlackouts moves dates to midnight UTC and drops repeats.
+func normalizeBlackouts(blackouts []domain.BlackoutDate) []domain.BlackoutDate {
+	seen := make(map[string]bool, len(blackouts))
+	out := make([]domain.BlackoutDate, 0, len(blackouts))
+	for _, b := range blackouts {
+		b.Date = calendarDate(b.Date)
+		if key := domain.DateKey(b.Date); !seen[key] {
+			seen[key] = true
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func normalizeOverrides(overrides []domain.OverrideWindow) []domain.OverrideWindow {
+	out := make([]domain.OverrideWindow, 0, len(overrides))
+	for _, o := range overrides {
+		o.Date = calendarDate(o.Date)
+		out = append(out, o)
+	}
+	return out
+}
+
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -1,8 +1,16 @@
 package campaign
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
 )
 
 func TestValidateCreateInputFailures(t *testing.T) {
@@ -10,6 +18,7 @@ func TestValidateCreateInputFailures(t *testing.T) {
 		{Name: "", TimeZone: "UTC"},
 		{Name: "test", TimeZone: ""},
 		{Name: "test", TimeZone: "invalid"},
+		{Name: "test", TimeZone: "UTC", DefaultCountry: "ZZ"},
 	}
 
 	for _, tc := range cases {
@@ -89,3 +98,363 @@ func TestValidateBusinessHours(t *testing.T) {
 		t.Fatalf("expected zero-duration business hours to fail validation")
 	}
 }
+
+func TestIsDrained(t *testing.T) {
+	idle := &domain.CampaignStats{TotalCalls: 3, CompletedCalls: 2, FailedCalls: 1}
+
+	cases := []struct {
+		name   string
+		counts map[string]int64
+		stats  *domain.CampaignStats
+		want   bool
+	}{
+		{name: "no targets", counts: map[string]int64{}, stats: idle, want: false},
+		{name: "pending targets", counts: map[string]int64{"pending": 1, "completed": 2}, stats: idle, want: false},
+		{name: "queued targets", counts: map[string]int64{"queued": 1}, stats: idle, want: false},
+		{name: "outstanding calls", counts: map[string]int64{"completed": 3}, stats: &domain.CampaignStats{PendingCalls: 1}, want: false},
+		{name: "drained", counts: map[string]int64{"completed": 2, "failed": 1}, stats: idle, want: true},
+	}
+
+	for _, tc := range cases {
+		if got := isDrained(tc.counts, tc.stats); got != tc.want {
+			t.Errorf("%s: isDrained() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTargetTimeZone(t *testing.T) {
+	cases := []struct {
+		name    string
+		number  string
+		payload map[string]any
+		want    string
+		wantErr bool
+	}{
+		{name: "derived from number", number: "+13125550100", want: "America/Chicago"},
+		{name: "payload wins", number: "+13125550100", payload: map[string]any{"time_zone": "America/Denver"}, want: "America/Denver"},
+		{name: "empty payload field", number: "+13125550100", payload: map[string]any{"time_zone": ""}, want: "America/Chicago"},
+		{name: "unknown prefix", number: "+18505550100", want: ""},
+		{name: "invalid zone", number: "+13125550100", payload: map[string]any{"time_zone": "Mars/Olympus"}, wantErr: true},
+		{name: "not a string", number: "+13125550100", payload: map[string]any{"time_zone": 5}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := targetTimeZone(tc.number, tc.payload)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: unexpected error state: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: targetTimeZone() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	past, future, later := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+
+	cases := []struct {
+		name       string
+		start, end *time.Time
+		endChanged bool
+		wantErr    bool
+	}{
+		{name: "unbounded"},
+		{name: "start only", start: &past},
+		{name: "window", start: &future, end: &later, endChanged: true},
+		{name: "end before start", start: &later, end: &future, endChanged: true, wantErr: true},
+		{name: "end equals start", start: &future, end: &future, wantErr: true},
+		{name: "new end in the past", end: &past, endChanged: true, wantErr: true},
+		{name: "new end now", end: &now, endChanged: true, wantErr: true},
+		{name: "unchanged end in the past", start: &past, end: &now},
+	}
+
+	for _, tc := range cases {
+		err := validateSchedule(tc.start, tc.end, now, tc.endChanged)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: validateSchedule() error = %v, want error %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("%s: error %v is not a validation error", tc.name, err)
+		}
+	}
+}
+
+func TestStartDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	ts := newTestService()
+
+	due := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPending, StartAt: &past, EndAt: &future}, nil)
+	early := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPending, StartAt: &future}, nil)
+	unscheduled := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPending}, nil)
+
+	started, err := ts.StartDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("StartDue: %v", err)
+	}
+	if len(started) != 1 || started[0] != due.ID {
+		t.Fatalf("started = %v, want [%s]", started, due.ID)
+	}
+	if got := ts.campaigns.campaigns[due.ID]; got.Status != domain.CampaignStatusInProgress || got.StartedAt == nil {
+		t.Fatalf("due campaign status %s, started_at %v", got.Status, got.StartedAt)
+	}
+	for _, c := range []*domain.Campaign{early, unscheduled} {
+		if got := ts.campaigns.campaigns[c.ID].Status; got != domain.CampaignStatusPending {
+			t.Fatalf("campaign not yet due moved to %s", got)
+		}
+	}
+	if len(ts.events.events) != 1 || ts.events.events[0].Type != domain.CampaignEventStarted {
+		t.Fatalf("events = %+v, want one started event", ts.events.events)
+	}
+}
+
+func TestEndExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	ts := newTestService()
+
+	running := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress, EndAt: &past},
+		map[string]int64{domain.TargetStatePending: 2, domain.TargetStateQueued: 1, domain.TargetStateCompleted: 3})
+	paused := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPaused, EndAt: &past},
+		map[string]int64{domain.TargetStateDeferred: 1})
+	neverStarted := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPending, StartAt: &past, EndAt: &past},
+		map[string]int64{domain.TargetStatePending: 4})
+	ongoing := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress, EndAt: &future},
+		map[string]int64{domain.TargetStatePending: 1})
+	cancelled := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusCancelled, EndAt: &past}, nil)
+
+	ended, err := ts.EndExpired(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("EndExpired: %v", err)
+	}
+	if len(ended) != 3 {
+		t.Fatalf("ended %d campaigns, want 3", len(ended))
+	}
+
+	for _, c := range []*domain.Campaign{running, paused, neverStarted} {
+		got := ts.campaigns.campaigns[c.ID]
+		if got.Status != domain.CampaignStatusCompleted || got.CompletedAt == nil || got.CompletionSummary == nil {
+			t.Fatalf("expired campaign: status %s, completed_at %v, summary %v", got.Status, got.CompletedAt, got.CompletionSummary)
+		}
+		for state := range ts.targets.counts[c.ID] {
+			if !domain.IsTerminalTargetState(state) {
+				t.Fatalf("campaign left targets in %s", state)
+			}
+		}
+	}
+	if got := ts.targets.counts[running.ID]; got[domain.TargetStateExpired] != 3 || got[domain.TargetStateCompleted] != 3 {
+		t.Fatalf("running campaign targets = %v, want 3 expired and 3 completed", got)
+	}
+	if got := ts.campaigns.campaigns[running.ID].CompletionSummary.TargetsByState; got[domain.TargetStateExpired] != 3 || got[domain.TargetStatePending] != 0 {
+		t.Fatalf("running campaign summary = %v, want the targets counted as expired", got)
+	}
+	if got := ts.campaigns.campaigns[ongoing.ID].Status; got != domain.CampaignStatusInProgress {
+		t.Fatalf("campaign before its end moved to %s", got)
+	}
+	if got := ts.campaigns.campaigns[cancelled.ID].Status; got != domain.CampaignStatusCancelled {
+		t.Fatalf("cancelled campaign moved to %s", got)
+	}
+	if len(ts.events.events) != 3 {
+		t.Fatalf("recorded %d events, want 3", len(ts.events.events))
+	}
+}
+
+func TestEndExpiredLeavesCampaignCancelledMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	past := now.Add(-time.Minute)
+	ts := newTestService()
+	campaign := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress, EndAt: &past},
+		map[string]int64{domain.TargetStatePending: 2})
+
+	// The campaign is cancelled after it was listed as past its end.
+	ts.stats.onGet = func() { ts.campaigns.campaigns[campaign.ID].Status = domain.CampaignStatusCancelled }
+
+	ended, err := ts.EndExpired(ctx, now, 10)
+	if err != nil || len(ended) != 0 {
+		t.Fatalf("EndExpired = %v, %v; want nothing ended", ended, err)
+	}
+	if got := ts.campaigns.campaigns[campaign.ID].Status; got != domain.CampaignStatusCancelled {
+		t.Fatalf("status = %s, want cancelled", got)
+	}
+	if got := ts.targets.counts[campaign.ID]; got[domain.TargetStatePending] != 2 {
+		t.Fatalf("targets = %v, want the pending targets left to the cancellation", got)
+	}
+}
+
+func TestStartResumesPausedCampaign(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	paused := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPaused}, nil)
+
+	if err := ts.Start(ctx, paused.ID); err != nil {
+		t.Fatalf("Start on paused campaign: %v", err)
+	}
+	if got := ts.campaigns.campaigns[paused.ID].Status; got != domain.CampaignStatusInProgress {
+		t.Fatalf("status = %s, want in_progress", got)
+	}
+	if len(ts.events.events) != 1 || ts.events.events[0].Type != domain.CampaignEventResumed {
+		t.Fatalf("events = %+v, want one resumed event", ts.events.events)
+	}
+}
+
+func TestCancelMovesTargetsWithCampaign(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	running := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress},
+		map[string]int64{domain.TargetStatePending: 2, domain.TargetStateRetrying: 1, domain.TargetStateCompleted: 4})
+
+	if err := ts.Cancel(ctx, running.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if got := ts.campaigns.campaigns[running.ID].Status; got != domain.CampaignStatusCancelled {
+		t.Fatalf("status = %s, want cancelled", got)
+	}
+	if got := ts.targets.counts[running.ID]; got[domain.TargetStateCancelled] != 3 || got[domain.TargetStateCompleted] != 4 || len(got) != 2 {
+		t.Fatalf("targets = %v, want 3 cancelled and 4 completed", got)
+	}
+
+	completed := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusCompleted}, nil)
+	if err := ts.Cancel(ctx, completed.ID); !errors.Is(err, apperrors.ErrConflict) {
+		t.Fatalf("Cancel on completed campaign = %v, want conflict", err)
+	}
+}
+
+func TestLifecycleChangeLosesToConcurrentCancel(t *testing.T) {
+	ctx := context.Background()
+	for name, change := range map[string]func(*testService, uuid.UUID) error{
+		"pause":    func(ts *testService, id uuid.UUID) error { return ts.Pause(ctx, id) },
+		"complete": func(ts *testService, id uuid.UUID) error { return ts.Complete(ctx, id) },
+	} {
+		ts := newTestService()
+		campaign := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+		ts.campaigns.afterGet = func() { ts.campaigns.campaigns[campaign.ID].Status = domain.CampaignStatusCancelled }
+
+		if err := change(ts, campaign.ID); !errors.Is(err, apperrors.ErrConflict) {
+			t.Fatalf("%s after cancel = %v, want conflict", name, err)
+		}
+		if got := ts.campaigns.campaigns[campaign.ID].Status; got != domain.CampaignStatusCancelled {
+			t.Fatalf("%s brought the cancelled campaign back to %s", name, got)
+		}
+		if len(ts.events.events) != 0 {
+			t.Fatalf("%s recorded %d events for a change that did not happen", name, len(ts.events.events))
+		}
+	}
+}
+
+func TestUpdateKeepsStatus(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	campaign := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+	ts.campaigns.afterGet = func() { ts.campaigns.campaigns[campaign.ID].Status = domain.CampaignStatusCancelled }
+
+	name := "renamed"
+	if _, err := ts.Update(ctx, UpdateCampaignInput{ID: campaign.ID, Name: &name}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got := ts.campaigns.campaigns[campaign.ID]
+	if got.Name != name || got.Status != domain.CampaignStatusCancelled {
+		t.Fatalf("campaign = %q %s, want renamed and still cancelled", got.Name, got.Status)
+	}
+}
+
+func TestEventFailureKeepsChange(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	ts.events.err = errors.New("events table unavailable")
+	pending := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPending}, nil)
+
+	if err := ts.Start(ctx, pending.ID); err != nil {
+		t.Fatalf("Start with failing audit trail: %v", err)
+	}
+	if got := ts.campaigns.campaigns[pending.ID].Status; got != domain.CampaignStatusInProgress {
+		t.Fatalf("status = %s, want in_progress", got)
+	}
+}
+
+func TestListByStatusLoadsSchedulesTogether(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	open := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+	closed := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+	ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+	ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusPaused}, nil)
+
+	monday := domain.BusinessHourWindow{DayOfWeek: time.Monday, Start: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), End: time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)}
+	christmas := domain.Holiday{Date: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"}
+	calendarID := uuid.New()
+	ts.hours.windows[open.ID] = []domain.BusinessHourWindow{monday}
+	ts.hours.exceptions[closed.ID] = repository.DateExceptions{Blackouts: []domain.BlackoutDate{{Date: christmas.Date}}}
+	ts.calendars.calendars[closed.ID] = []uuid.UUID{calendarID}
+	ts.calendars.holidays[closed.ID] = []domain.Holiday{christmas}
+
+	campaigns, err := ts.ListByStatus(ctx, domain.CampaignStatusInProgress, 10)
+	if err != nil {
+		t.Fatalf("ListByStatus: %v", err)
+	}
+	if len(campaigns) != 3 {
+		t.Fatalf("ListByStatus returned %d campaigns, want 3", len(campaigns))
+	}
+	if ts.hours.queries != 2 || ts.calendars.queries != 2 {
+		t.Fatalf("schedule queries = %d hours, %d calendars; want 2 each", ts.hours.queries, ts.calendars.queries)
+	}
+	for _, c := range campaigns {
+		switch c.ID {
+		case open.ID:
+			if len(c.BusinessHours) != 1 || len(c.Blackouts) != 0 || len(c.Holidays) != 0 {
+				t.Errorf("open campaign schedule = %+v", c)
+			}
+		case closed.ID:
+			if len(c.BusinessHours) != 0 || len(c.Blackouts) != 1 || len(c.HolidayCalendarIDs) != 1 || len(c.Holidays) != 1 {
+				t.Errorf("closed campaign schedule = %+v", c)
+			}
+		default:
+			if len(c.BusinessHours) != 0 || len(c.Blackouts) != 0 || len(c.Holidays) != 0 {
+				t.Errorf("campaign without rules got schedule %+v", c)
+			}
+		}
+	}
+}
+
+func TestCompleteIfDrained(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	drained := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, map[string]int64{domain.TargetStateCompleted: 2})
+	busy := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, map[string]int64{domain.TargetStatePending: 1})
+
+	summary, completed, err := ts.CompleteIfDrained(ctx, drained.ID)
+	if err != nil || !completed || summary == nil {
+		t.Fatalf("CompleteIfDrained(drained) = %v, %v, %v; want a summary", summary, completed, err)
+	}
+	if got := ts.campaigns.campaigns[drained.ID].Status; got != domain.CampaignStatusCompleted {
+		t.Fatalf("drained campaign status = %s, want completed", got)
+	}
+
+	if _, completed, err := ts.CompleteIfDrained(ctx, busy.ID); err != nil || completed {
+		t.Fatalf("CompleteIfDrained(busy) = %v, %v; want not completed", completed, err)
+	}
+}
+
+func TestCompleteIfDrainedLosesToPause(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestService()
+	campaign := ts.addCampaign(domain.Campaign{Status: domain.CampaignStatusInProgress}, nil)
+
+	// The campaign is paused after it was read as in progress but before it is completed.
+	ts.stats.onGet = func() { ts.campaigns.campaigns[campaign.ID].Status = domain.CampaignStatusPaused }
+
+	if _, completed, err := ts.CompleteIfDrained(ctx, campaign.ID); err != nil || completed {
+		t.Fatalf("CompleteIfDrained = %v, %v; want not completed", completed, err)
+	}
+	if got := ts.campaigns.campaigns[campaign.ID].Status; got != domain.CampaignStatusPaused {
+		t.Fatalf("status = %s, want paused", got)
+	}
+	if len(ts.events.events) != 0 {
+		t.Fatalf("recorded %d events for a completion that did not happen", len(ts.events.events))
+	}
+}
@@ -0,0 +1,154 @@
+package campaign
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// editableTargetStates lists the states in which a target's payload may be changed.
+var editableTargetStates = []string{domain.TargetStatePending, domain.TargetStateStaged, domain.TargetStateDeferred}
+
+// removableTargetStates lists the states in which a target may be deleted; targets with a call
+// in flight are left alone.
+var removableTargetStates = []string{
+	domain.TargetStatePending,
+	domain.TargetStateStaged,
+	domain.TargetStateDeferred,
+	domain.TargetStateCompleted,
+	domain.TargetStateFailed,
+	domain.TargetStateCancelled,
+	domain.TargetStateExpired,
+	domain.TargetStateSuppressed,
+}
+
+// TargetQuery filters a target listing. PageToken is the NextPageToken of the previous page.
+type TargetQuery struct {
+	State       string
+	PhoneNumber string
+	PageToken   string
+	Limit       int
+}
+
+// TargetPage is a page of targets with the token for the next one, empty on the last page.
+type TargetPage struct {
+	Targets       []repository.CampaignTargetRecord
+	NextPageToken string
+}
+
+// ListTargets pages through a campaign's targets in the order they were added.
+func (s *Service) ListTargets(ctx context.Context, campaignID uuid.UUID, query TargetQuery) (*TargetPage, error) {
+	if query.State != "" && !slices.Contains(domain.TargetStates, query.State) {
+		return nil, fmt.Errorf("%w: unknown target state %q", apperrors.ErrValidation, query.State)
+	}
+	cursor, err := decodeTargetCursor(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Get(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	filter := repository.TargetFilter{
+		CampaignID:  campaignID,
+		State:       query.State,
+		PhoneNumber: strings.TrimSpace(query.PhoneNumber),
+	}
+	targets, err := s.targetRepo.Query(ctx, filter, cursor, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("campaign service: list targets: %w", err)
+	}
+
+	page := &TargetPage{Targets: targets}
+	if query.Limit > 0 && len(targets) == query.Limit {
+		last := targets[len(targets)-1]
+		page.NextPageToken = encodeTargetCursor(repository.TargetCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// UpdateTargetPayload replaces the payload of a target that has not been scheduled yet and
+// resolves its time zone again.
+func (s *Service) UpdateTargetPayload(ctx context.Context, campaignID, targetID uuid.UUID, payload map[string]any) (*repository.CampaignTargetRecord, error) {
+	target, err := s.targetRepo.Get(ctx, campaignID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	before := snapshotTarget(target)
+
+	zone, err := targetTimeZone(target.PhoneNumber, payload)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.targetRepo.UpdatePayload(ctx, campaignID, targetID, payload, zone, editableTargetStates)
+	if err != nil {
+		return nil, fmt.Errorf("campaign service: update target: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: only targets that have not been dispatched can be edited", apperrors.ErrConflict)
+	}
+
+	target.Payload = payload
+	target.TimeZone = zone
+	s.recordEvent(ctx, campaignID, domain.CampaignEventTargetUpdated, before, snapshotTarget(target))
+	return target, nil
+}
+
+// DeleteTarget removes a target that has no call in flight.
+func (s *Service) DeleteTarget(ctx context.Context, campaignID, targetID uuid.UUID) error {
+	target, err := s.targetRepo.Get(ctx, campaignID, targetID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.targetRepo.Delete(ctx, campaignID, targetID, removableTargetStates)
+	if err != nil {
+		return fmt.Errorf("campaign service: delete target: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: target has a call in progress", apperrors.ErrConflict)
+	}
+
+	s.recordEvent(ctx, campaignID, domain.CampaignEventTargetRemoved, snapshotTarget(target), nil)
+	return nil
+}
+
+// encodeTargetCursor renders the keyset position as an opaque page token.
+func encodeTargetCursor(c repository.TargetCursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID.String()
+	return common.EncodeBase64([]byte(raw))
+}
+
+func decodeTargetCursor(token string) (*repository.TargetCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	invalid := fmt.Errorf("%w: invalid page token", apperrors.ErrValidation)
+	raw, err := common.DecodeBase64(token)
+	if err != nil {
+		return nil, invalid
+	}
+	nanos, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, invalid
+	}
+	ns, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, invalid
+	}
+	return &repository.TargetCursor{CreatedAt: time.Unix(0, ns).UTC(), ID: id}, nil
+}
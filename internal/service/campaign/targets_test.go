@@ -0,0 +1,36 @@
+package campaign
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+func TestTargetCursorRoundTrip(t *testing.T) {
+	want := repository.TargetCursor{
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	got, err := decodeTargetCursor(encodeTargetCursor(want))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("got %+v, want %+v", *got, want)
+	}
+
+	if cursor, err := decodeTargetCursor(""); err != nil || cursor != nil {
+		t.Fatalf("expected empty token to mean first page, got %v, %v", cursor, err)
+	}
+	for _, token := range []string{"!!", "bm9jb2xvbg", "MTIzOm5vdC1hLXV1aWQ"} {
+		if _, err := decodeTargetCursor(token); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("expected validation error for token %q, got %v", token, err)
+		}
+	}
+}
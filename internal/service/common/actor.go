@@ -0,0 +1,33 @@
+package common
+
+import "context"
+
+// Well-known actors recorded when no caller identity is available.
+const (
+	ActorSystem    = "system"
+	ActorScheduler = "scheduler"
+	ActorAPI       = "api"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the identity responsible for subsequent changes.
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// UnauthenticatedActor labels an identity the caller asserted without it being verified.
+func UnauthenticatedActor(claimed string) string {
+	return "unauthenticated:" + claimed
+}
+
+// ActorFromContext returns the actor stored on ctx, defaulting to ActorSystem.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
@@ -0,0 +1,82 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// backlogAddScript adds a member and only ever pushes the key's expiry later, so idle
+// campaigns do not leave keys behind but a short retry never expires a longer one.
+var backlogAddScript = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+local ttl = redis.call('PTTL', KEYS[1])
+local now = redis.call('TIME')
+local expireIn = tonumber(ARGV[3]) - (tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000))
+if expireIn > ttl then
+  redis.call('PEXPIRE', KEYS[1], math.max(expireIn, 1))
+end
+return 1
+`)
+
+// RetryBacklog tracks, per campaign, the calls that have a retry scheduled but not yet
+// re-dispatched. Entries are keyed by call id so redelivered messages are counted once.
+// Entries whose retry is overdue by more than the grace period are treated as lost and dropped.
+type RetryBacklog struct {
+	client *redis.Client
+	grace  time.Duration
+}
+
+// NewRetryBacklog constructs a retry backlog tracker.
+func NewRetryBacklog(client *redis.Client, grace time.Duration) *RetryBacklog {
+	if grace <= 0 {
+		grace = 10 * time.Minute
+	}
+	return &RetryBacklog{client: client, grace: grace}
+}
+
+// Add records that callID will be retried at nextAttempt.
+func (b *RetryBacklog) Add(ctx context.Context, campaignID, callID uuid.UUID, nextAttempt time.Time) error {
+	if campaignID == uuid.Nil {
+		return nil
+	}
+	due := nextAttempt.UnixMilli()
+	expireAt := nextAttempt.Add(b.grace).UnixMilli()
+	if err := backlogAddScript.Run(ctx, b.client, []string{b.key(campaignID)}, due, callID.String(), expireAt).Err(); err != nil {
+		return fmt.Errorf("retry backlog add: %w", err)
+	}
+	return nil
+}
+
+// Remove drops callID once its retry has been dispatched or abandoned.
+func (b *RetryBacklog) Remove(ctx context.Context, campaignID, callID uuid.UUID) error {
+	if campaignID == uuid.Nil {
+		return nil
+	}
+	if err := b.client.ZRem(ctx, b.key(campaignID), callID.String()).Err(); err != nil {
+		return fmt.Errorf("retry backlog remove: %w", err)
+	}
+	return nil
+}
+
+// Outstanding returns how many retries are waiting for the campaign.
+func (b *RetryBacklog) Outstanding(ctx context.Context, campaignID uuid.UUID) (int, error) {
+	key := b.key(campaignID)
+	cutoff := time.Now().Add(-b.grace).UnixMilli()
+
+	pipe := b.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("retry backlog outstanding: %w", err)
+	}
+	return int(count.Val()), nil
+}
+
+func (b *RetryBacklog) key(campaignID uuid.UUID) string {
+	return fmt.Sprintf("outbound:campaign:%s:retry_backlog", campaignID.String())
+}
@@ -0,0 +1,102 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+func testRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func TestRetryBacklogAddRemove(t *testing.T) {
+	ctx := context.Background()
+	_, client := testRedis(t)
+	backlog := NewRetryBacklog(client, time.Minute)
+	campaignID, callA, callB := uuid.New(), uuid.New(), uuid.New()
+	next := time.Now().Add(30 * time.Second)
+
+	for _, call := range []uuid.UUID{callA, callB, callA} {
+		if err := backlog.Add(ctx, campaignID, call, next); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if n, err := backlog.Outstanding(ctx, campaignID); err != nil || n != 2 {
+		t.Fatalf("Outstanding after redelivered Add = %d, %v; want 2", n, err)
+	}
+
+	if err := backlog.Remove(ctx, campaignID, callA); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if n, err := backlog.Outstanding(ctx, campaignID); err != nil || n != 1 {
+		t.Fatalf("Outstanding after Remove = %d, %v; want 1", n, err)
+	}
+	if n, err := backlog.Outstanding(ctx, uuid.New()); err != nil || n != 0 {
+		t.Fatalf("Outstanding for another campaign = %d, %v; want 0", n, err)
+	}
+}
+
+func TestRetryBacklogDropsOverdueEntries(t *testing.T) {
+	ctx := context.Background()
+	_, client := testRedis(t)
+	backlog := NewRetryBacklog(client, time.Minute)
+	campaignID := uuid.New()
+
+	if err := backlog.Add(ctx, campaignID, uuid.New(), time.Now().Add(-2*time.Minute)); err != nil {
+		t.Fatalf("Add lost: %v", err)
+	}
+	if err := backlog.Add(ctx, campaignID, uuid.New(), time.Now().Add(-30*time.Second)); err != nil {
+		t.Fatalf("Add late: %v", err)
+	}
+	if n, err := backlog.Outstanding(ctx, campaignID); err != nil || n != 1 {
+		t.Fatalf("Outstanding = %d, %v; want only the entry within grace", n, err)
+	}
+}
+
+func TestRetryBacklogExpiryOnlyMovesLater(t *testing.T) {
+	ctx := context.Background()
+	server, client := testRedis(t)
+	backlog := NewRetryBacklog(client, time.Minute)
+	campaignID := uuid.New()
+	key := backlog.key(campaignID)
+
+	if err := backlog.Add(ctx, campaignID, uuid.New(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	long := server.TTL(key)
+	if long < 55*time.Minute {
+		t.Fatalf("TTL after hour-long retry = %v, want about 61m", long)
+	}
+
+	if err := backlog.Add(ctx, campaignID, uuid.New(), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if ttl := server.TTL(key); ttl < long-time.Second {
+		t.Fatalf("short retry shortened TTL from %v to %v", long, ttl)
+	}
+}
+
+func TestRetryBacklogIgnoresNilCampaign(t *testing.T) {
+	ctx := context.Background()
+	server, client := testRedis(t)
+	backlog := NewRetryBacklog(client, time.Minute)
+
+	if err := backlog.Add(ctx, uuid.Nil, uuid.New(), time.Now()); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := backlog.Remove(ctx, uuid.Nil, uuid.New()); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Fatalf("nil campaign wrote keys %v", keys)
+	}
+}
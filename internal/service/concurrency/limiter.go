@@ -2,7 +2,9 @@ package concurrency
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -59,6 +61,22 @@ return 0
 	return res == 1, nil
 }
 
+// Available returns how many slots the campaign has free under limit. A campaign without a
+// limit reports math.MaxInt.
+func (l *Limiter) Available(ctx context.Context, campaignID uuid.UUID, limit int) (int, error) {
+	if limit <= 0 {
+		limit = l.defaultLimit
+	}
+	if campaignID == uuid.Nil || limit <= 0 {
+		return math.MaxInt, nil
+	}
+	current, err := l.client.Get(ctx, l.key(campaignID)).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("concurrency available: %w", err)
+	}
+	return max(limit-current, 0), nil
+}
+
 // Release frees a previously acquired slot.
 func (l *Limiter) Release(ctx context.Context, campaignID uuid.UUID) error {
 	if campaignID == uuid.Nil {
@@ -0,0 +1,63 @@
+package concurrency
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLimiterAvailable(t *testing.T) {
+	ctx := context.Background()
+	server, client := testRedis(t)
+	limiter := NewLimiter(client, 4, time.Minute)
+	campaignID := uuid.New()
+
+	if free, err := limiter.Available(ctx, campaignID, 3); err != nil || free != 3 {
+		t.Fatalf("Available before any call = %d, %v; want 3", free, err)
+	}
+	for i := 0; i < 2; i++ {
+		if ok, err := limiter.Acquire(ctx, campaignID, 3); err != nil || !ok {
+			t.Fatalf("Acquire %d = %v, %v; want a slot", i, ok, err)
+		}
+	}
+	if free, err := limiter.Available(ctx, campaignID, 3); err != nil || free != 1 {
+		t.Fatalf("Available with 2 of 3 in use = %d, %v; want 1", free, err)
+	}
+	// Unset limits fall back to the default.
+	if free, err := limiter.Available(ctx, campaignID, 0); err != nil || free != 2 {
+		t.Fatalf("Available under the default limit = %d, %v; want 2", free, err)
+	}
+	// A lowered limit leaves no free slots rather than a negative count.
+	if free, err := limiter.Available(ctx, campaignID, 1); err != nil || free != 0 {
+		t.Fatalf("Available under a lowered limit = %d, %v; want 0", free, err)
+	}
+
+	if err := limiter.Release(ctx, campaignID); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if free, err := limiter.Available(ctx, campaignID, 3); err != nil || free != 2 {
+		t.Fatalf("Available after a release = %d, %v; want 2", free, err)
+	}
+
+	server.Close()
+	if _, err := limiter.Available(ctx, campaignID, 3); err == nil {
+		t.Fatal("Available with Redis down succeeded")
+	}
+}
+
+func TestLimiterAvailableWithoutLimit(t *testing.T) {
+	ctx := context.Background()
+	_, client := testRedis(t)
+
+	unlimited := NewLimiter(client, 0, time.Minute)
+	if free, err := unlimited.Available(ctx, uuid.New(), 0); err != nil || free != math.MaxInt {
+		t.Fatalf("Available without any limit = %d, %v; want math.MaxInt", free, err)
+	}
+	limited := NewLimiter(client, 4, time.Minute)
+	if free, err := limited.Available(ctx, uuid.Nil, 2); err != nil || free != math.MaxInt {
+		t.Fatalf("Available for no campaign = %d, %v; want math.MaxInt", free, err)
+	}
+}
@@ -0,0 +1,182 @@
+package dnc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/internal/service/common"
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+	"github.com/acme/outbound-call-campaign/pkg/phone"
+)
+
+// DefaultReason is recorded when an entry is added without an explicit reason.
+const DefaultReason = "do-not-call"
+
+// MaxImportSize bounds the number of entries accepted by a single import.
+const MaxImportSize = 50000
+
+// Service manages the do-not-call suppression list.
+type Service struct {
+	repo      repository.SuppressionRepository
+	campaigns repository.CampaignRepository
+}
+
+// NewService constructs the suppression service.
+func NewService(repo repository.SuppressionRepository, campaigns repository.CampaignRepository) *Service {
+	return &Service{repo: repo, campaigns: campaigns}
+}
+
+// EntryInput describes a number to suppress. A nil CampaignID suppresses it for every campaign.
+type EntryInput struct {
+	CampaignID  *uuid.UUID
+	PhoneNumber string
+	Reason      string
+}
+
+// ImportResult summarises a bulk import.
+type ImportResult struct {
+	Received int   `json:"received"`
+	Inserted int64 `json:"inserted"`
+	Skipped  int64 `json:"skipped"`
+}
+
+// Add suppresses a single number.
+func (s *Service) Add(ctx context.Context, input EntryInput) (*domain.SuppressedNumber, error) {
+	region, err := s.campaignRegion(ctx, input.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.toEntry(ctx, input, region)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Add(ctx, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Import suppresses many numbers at once. Numbers already suppressed in the same scope are skipped.
+func (s *Service) Import(ctx context.Context, inputs []EntryInput) (*ImportResult, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%w: no entries to import", apperrors.ErrValidation)
+	}
+	if len(inputs) > MaxImportSize {
+		return nil, fmt.Errorf("%w: import exceeds %d entries", apperrors.ErrValidation, MaxImportSize)
+	}
+
+	entries := make([]domain.SuppressedNumber, 0, len(inputs))
+	regions := make(map[uuid.UUID]string)
+	for i, in := range inputs {
+		var region string
+		if in.CampaignID != nil {
+			var ok bool
+			if region, ok = regions[*in.CampaignID]; !ok {
+				r, err := s.campaignRegion(ctx, in.CampaignID)
+				if err != nil {
+					return nil, fmt.Errorf("entries[%d]: %w", i, err)
+				}
+				region = r
+				regions[*in.CampaignID] = r
+			}
+		}
+		entry, err := s.toEntry(ctx, in, region)
+		if err != nil {
+			return nil, fmt.Errorf("entries[%d]: %w", i, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	inserted, err := s.repo.BulkAdd(ctx, entries)
+	if err != nil {
+		return nil, fmt.Errorf("dnc service: import: %w", err)
+	}
+	return &ImportResult{
+		Received: len(entries),
+		Inserted: inserted,
+		Skipped:  int64(len(entries)) - inserted,
+	}, nil
+}
+
+// Delete removes an entry.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List returns entries matching filter, paging by id.
+func (s *Service) List(ctx context.Context, filter repository.SuppressionFilter, afterID *int64, limit int) ([]domain.SuppressedNumber, error) {
+	filter.PhoneNumber = strings.TrimSpace(filter.PhoneNumber)
+	if normalized, err := phone.Normalize(filter.PhoneNumber, ""); err == nil {
+		filter.PhoneNumber = normalized
+	}
+	return s.repo.List(ctx, filter, afterID, limit)
+}
+
+// Check reports whether number may not be dialled for the campaign and why.
+func (s *Service) Check(ctx context.Context, campaignID uuid.UUID, number string) (string, bool, error) {
+	region, err := s.campaignRegion(ctx, &campaignID)
+	if err != nil {
+		return "", false, err
+	}
+	normalized, err := phone.Normalize(number, region)
+	if err != nil {
+		return "", false, err
+	}
+	matches, err := s.Filter(ctx, campaignID, []string{normalized})
+	if err != nil {
+		return "", false, err
+	}
+	reason, ok := matches[normalized]
+	return reason, ok, nil
+}
+
+// Filter returns the suppression reason for each of phones that may not be dialled for the campaign.
+// phones must already be in E.164 form.
+func (s *Service) Filter(ctx context.Context, campaignID uuid.UUID, phones []string) (map[string]string, error) {
+	matches, err := s.repo.Match(ctx, campaignID, phones)
+	if err != nil {
+		return nil, fmt.Errorf("dnc service: match: %w", err)
+	}
+	for phone, reason := range matches {
+		if reason == "" {
+			matches[phone] = DefaultReason
+		}
+	}
+	return matches, nil
+}
+
+// toEntry normalises the number using region, which is empty for global entries so that
+// those must be supplied in international format.
+func (s *Service) toEntry(ctx context.Context, input EntryInput, region string) (domain.SuppressedNumber, error) {
+	number, err := phone.Normalize(input.PhoneNumber, region)
+	if err != nil {
+		return domain.SuppressedNumber{}, err
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		reason = DefaultReason
+	}
+	return domain.SuppressedNumber{
+		CampaignID:  input.CampaignID,
+		PhoneNumber: number,
+		Reason:      reason,
+		CreatedBy:   common.ActorFromContext(ctx),
+	}, nil
+}
+
+// campaignRegion returns the default country of the campaign, or "" when id is nil.
+func (s *Service) campaignRegion(ctx context.Context, id *uuid.UUID) (string, error) {
+	if id == nil {
+		return "", nil
+	}
+	campaign, err := s.campaigns.Get(ctx, *id)
+	if err != nil {
+		return "", fmt.Errorf("dnc service: lookup campaign: %w", err)
+	}
+	return campaign.DefaultCountry, nil
+}
@@ -0,0 +1,88 @@
+package dnc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+type fakeSuppression struct {
+	repository.SuppressionRepository
+	reasons map[string]string
+	asked   []string
+	err     error
+}
+
+func (f *fakeSuppression) Match(_ context.Context, _ uuid.UUID, phones []string) (map[string]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.asked = append(f.asked, phones...)
+	matches := make(map[string]string)
+	for _, phone := range phones {
+		if reason, ok := f.reasons[phone]; ok {
+			matches[phone] = reason
+		}
+	}
+	return matches, nil
+}
+
+type fakeCampaigns struct {
+	repository.CampaignRepository
+	campaign domain.Campaign
+}
+
+func (f *fakeCampaigns) Get(_ context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	if id != f.campaign.ID {
+		return nil, repository.ErrNotFound
+	}
+	campaign := f.campaign
+	return &campaign, nil
+}
+
+func TestFilter(t *testing.T) {
+	repo := &fakeSuppression{reasons: map[string]string{"+14155550100": "litigator", "+14155550101": ""}}
+	svc := NewService(repo, nil)
+
+	matches, err := svc.Filter(context.Background(), uuid.New(), []string{"+14155550100", "+14155550101", "+14155550102"})
+	if err != nil {
+		t.Fatalf("Filter: %v", err)
+	}
+	want := map[string]string{"+14155550100": "litigator", "+14155550101": DefaultReason}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("Filter = %v, want %v", matches, want)
+	}
+
+	repo.err = errors.New("database unavailable")
+	if _, err := svc.Filter(context.Background(), uuid.New(), []string{"+14155550100"}); err == nil {
+		t.Fatal("Filter succeeded with the suppression list unavailable")
+	}
+}
+
+func TestCheckNormalisesInCampaignRegion(t *testing.T) {
+	ctx := context.Background()
+	campaign := domain.Campaign{ID: uuid.New(), DefaultCountry: "GB"}
+	repo := &fakeSuppression{reasons: map[string]string{"+447700900123": "opted out"}}
+	svc := NewService(repo, &fakeCampaigns{campaign: campaign})
+
+	reason, suppressed, err := svc.Check(ctx, campaign.ID, "07700 900123")
+	if err != nil || !suppressed || reason != "opted out" {
+		t.Fatalf("Check = %q, %v, %v; want opted out", reason, suppressed, err)
+	}
+	if want := []string{"+447700900123"}; !reflect.DeepEqual(repo.asked, want) {
+		t.Fatalf("matched %v, want %v", repo.asked, want)
+	}
+
+	if _, suppressed, err := svc.Check(ctx, campaign.ID, "07700 900124"); err != nil || suppressed {
+		t.Fatalf("Check of a clear number = %v, %v; want not suppressed", suppressed, err)
+	}
+	if _, _, err := svc.Check(ctx, uuid.New(), "07700 900123"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Check for an unknown campaign = %v, want ErrNotFound", err)
+	}
+}
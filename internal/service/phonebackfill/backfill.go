@@ -0,0 +1,201 @@
+// Package phonebackfill rewrites phone numbers stored before numbers were normalised on input,
+// so that targets and suppression entries compare equal in E.164 form.
+package phonebackfill
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+	"github.com/acme/outbound-call-campaign/pkg/phone"
+)
+
+// Reasons recorded on targets the backfill suppresses instead of renumbering.
+const (
+	ReasonInvalidNumber   = "invalid_phone_number"
+	ReasonDuplicateNumber = "duplicate_phone_number"
+)
+
+const defaultBatchSize = 500
+
+// Report counts what a run did. Unresolved rows could neither be normalised nor flagged and are
+// logged with their ids for manual review.
+type Report struct {
+	TargetsScanned       int
+	TargetsNormalized    int
+	TargetsFlagged       int
+	TargetsUnresolved    int
+	SuppressedScanned    int
+	SuppressedNormalized int
+	SuppressedUnresolved int
+}
+
+// Unresolved reports whether any row was left for manual review.
+func (r Report) Unresolved() bool {
+	return r.TargetsUnresolved > 0 || r.SuppressedUnresolved > 0
+}
+
+// Job normalises stored target and suppression numbers.
+type Job struct {
+	repo      repository.PhoneNumberRepository
+	logger    *logger.Logger
+	batchSize int
+	dryRun    bool
+}
+
+// New builds a job. In a dry run nothing is written and renumbering conflicts are not detected.
+func New(repo repository.PhoneNumberRepository, lg *logger.Logger, batchSize int, dryRun bool) *Job {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Job{repo: repo, logger: lg, batchSize: batchSize, dryRun: dryRun}
+}
+
+// Run walks every target and suppression entry once. Targets whose number cannot be normalised,
+// or whose normalised number is already listed in the campaign, are suppressed if they have not
+// been dialled yet; everything else that cannot be fixed is logged and counted as unresolved.
+func (j *Job) Run(ctx context.Context) (Report, error) {
+	var report Report
+	if err := j.targets(ctx, &report); err != nil {
+		return report, err
+	}
+	if err := j.suppressed(ctx, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (j *Job) targets(ctx context.Context, report *Report) error {
+	after := uuid.Nil
+	for {
+		rows, err := j.repo.TargetNumbers(ctx, after, j.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			report.TargetsScanned++
+			if err := j.target(ctx, row, report); err != nil {
+				return err
+			}
+		}
+		if len(rows) < j.batchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].ID
+	}
+}
+
+func (j *Job) target(ctx context.Context, row repository.StoredTargetNumber, report *Report) error {
+	fields := []zap.Field{
+		zap.String("target_id", row.ID.String()),
+		zap.String("campaign_id", row.CampaignID.String()),
+		zap.String("phone_number", row.PhoneNumber),
+	}
+
+	number, err := phone.Normalize(row.PhoneNumber, row.Region)
+	if err != nil {
+		return j.flag(ctx, row, ReasonInvalidNumber, report, append(fields, zap.Error(err)))
+	}
+	if number == row.PhoneNumber {
+		return nil
+	}
+	if j.dryRun {
+		report.TargetsNormalized++
+		return nil
+	}
+
+	err = j.repo.RenumberTarget(ctx, row.ID, number)
+	switch {
+	case err == nil:
+		report.TargetsNormalized++
+		return nil
+	case errors.Is(err, repository.ErrConflict):
+		return j.flag(ctx, row, ReasonDuplicateNumber, report, append(fields, zap.String("normalized", number)))
+	default:
+		return err
+	}
+}
+
+// flag suppresses a target that cannot be renumbered. Targets already dialled keep their state
+// and history and are left for review.
+func (j *Job) flag(ctx context.Context, row repository.StoredTargetNumber, reason string, report *Report, fields []zap.Field) error {
+	fields = append(fields, zap.String("reason", reason), zap.String("state", row.State))
+	if j.dryRun {
+		report.TargetsFlagged++
+		j.logger.Warn("phone backfill: would flag target", fields...)
+		return nil
+	}
+
+	flagged, err := j.repo.FlagTarget(ctx, row.ID, reason)
+	if err != nil {
+		return err
+	}
+	if !flagged {
+		report.TargetsUnresolved++
+		j.logger.Warn("phone backfill: target left for review", fields...)
+		return nil
+	}
+	report.TargetsFlagged++
+	j.logger.Info("phone backfill: flagged target", fields...)
+	return nil
+}
+
+func (j *Job) suppressed(ctx context.Context, report *Report) error {
+	var after int64
+	for {
+		rows, err := j.repo.SuppressedNumbers(ctx, after, j.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			report.SuppressedScanned++
+			if err := j.suppressedEntry(ctx, row, report); err != nil {
+				return err
+			}
+		}
+		if len(rows) < j.batchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].ID
+	}
+}
+
+// suppressedEntry renumbers one suppression entry. An entry that cannot be normalised, or whose
+// normalised number is already suppressed in the same scope, is kept as it is and reported.
+func (j *Job) suppressedEntry(ctx context.Context, row repository.StoredSuppressedNumber, report *Report) error {
+	fields := []zap.Field{zap.Int64("suppression_id", row.ID), zap.String("phone_number", row.PhoneNumber)}
+	if row.CampaignID != nil {
+		fields = append(fields, zap.String("campaign_id", row.CampaignID.String()))
+	}
+
+	number, err := phone.Normalize(row.PhoneNumber, row.Region)
+	if err != nil {
+		report.SuppressedUnresolved++
+		j.logger.Warn("phone backfill: suppression entry left for review", append(fields, zap.Error(err))...)
+		return nil
+	}
+	if number == row.PhoneNumber {
+		return nil
+	}
+	if j.dryRun {
+		report.SuppressedNormalized++
+		return nil
+	}
+
+	err = j.repo.RenumberSuppressed(ctx, row.ID, number)
+	switch {
+	case err == nil:
+		report.SuppressedNormalized++
+		return nil
+	case errors.Is(err, repository.ErrConflict):
+		report.SuppressedUnresolved++
+		j.logger.Warn("phone backfill: suppression entry duplicates a normalised one", append(fields, zap.String("normalized", number))...)
+		return nil
+	default:
+		return err
+	}
+}
@@ -0,0 +1,184 @@
+package phonebackfill
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+type fakeRepo struct {
+	targets    []repository.StoredTargetNumber
+	suppressed []repository.StoredSuppressedNumber
+	flagged    map[uuid.UUID]string
+}
+
+func (f *fakeRepo) TargetNumbers(_ context.Context, afterID uuid.UUID, limit int) ([]repository.StoredTargetNumber, error) {
+	var out []repository.StoredTargetNumber
+	for _, t := range f.targets {
+		if t.ID.String() > afterID.String() && len(out) < limit {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) RenumberTarget(_ context.Context, id uuid.UUID, number string) error {
+	var target *repository.StoredTargetNumber
+	for i := range f.targets {
+		if f.targets[i].ID == id {
+			target = &f.targets[i]
+		}
+	}
+	for _, t := range f.targets {
+		if t.ID != id && t.CampaignID == target.CampaignID && t.PhoneNumber == number {
+			return fmt.Errorf("%w: duplicate", repository.ErrConflict)
+		}
+	}
+	target.PhoneNumber = number
+	return nil
+}
+
+func (f *fakeRepo) FlagTarget(_ context.Context, id uuid.UUID, reason string) (bool, error) {
+	for _, t := range f.targets {
+		if t.ID == id && t.State == domain.TargetStatePending {
+			f.flagged[id] = reason
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRepo) SuppressedNumbers(_ context.Context, afterID int64, limit int) ([]repository.StoredSuppressedNumber, error) {
+	var out []repository.StoredSuppressedNumber
+	for _, s := range f.suppressed {
+		if s.ID > afterID && len(out) < limit {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) RenumberSuppressed(_ context.Context, id int64, number string) error {
+	var entry *repository.StoredSuppressedNumber
+	for i := range f.suppressed {
+		if f.suppressed[i].ID == id {
+			entry = &f.suppressed[i]
+		}
+	}
+	for _, s := range f.suppressed {
+		if s.ID != id && s.CampaignID == entry.CampaignID && s.PhoneNumber == number {
+			return fmt.Errorf("%w: duplicate", repository.ErrConflict)
+		}
+	}
+	entry.PhoneNumber = number
+	return nil
+}
+
+// sequentialIDs returns n uuids in ascending string order so the fake pages like Postgres.
+func sequentialIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1))
+	}
+	return ids
+}
+
+func TestRunNormalizesAndFlags(t *testing.T) {
+	campaign := uuid.New()
+	ids := sequentialIDs(6)
+	repo := &fakeRepo{
+		targets: []repository.StoredTargetNumber{
+			{ID: ids[0], CampaignID: campaign, PhoneNumber: "+14155550100", State: domain.TargetStatePending, Region: "US"},
+			{ID: ids[1], CampaignID: campaign, PhoneNumber: "(415) 555-0101", State: domain.TargetStatePending, Region: "US"},
+			{ID: ids[2], CampaignID: campaign, PhoneNumber: "415-555-0100", State: domain.TargetStatePending, Region: "US"},
+			{ID: ids[3], CampaignID: campaign, PhoneNumber: "not a number", State: domain.TargetStatePending, Region: "US"},
+			{ID: ids[4], CampaignID: campaign, PhoneNumber: "12", State: domain.TargetStateCompleted, Region: "US"},
+			{ID: ids[5], CampaignID: campaign, PhoneNumber: "415 555 0102", State: domain.TargetStateCompleted, Region: "US"},
+		},
+		suppressed: []repository.StoredSuppressedNumber{
+			{ID: 1, PhoneNumber: "+1 415 555 0197"},
+			{ID: 2, PhoneNumber: "4155550199"},
+			{ID: 3, CampaignID: &campaign, PhoneNumber: "415.555.0198", Region: "US"},
+			{ID: 4, PhoneNumber: "+14155550199"},
+			{ID: 5, PhoneNumber: "+1 415 555 0199"},
+		},
+		flagged: map[uuid.UUID]string{},
+	}
+
+	job := New(repo, &logger.Logger{Logger: zap.NewNop()}, 2, false)
+	report, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := Report{
+		TargetsScanned:       6,
+		TargetsNormalized:    2,
+		TargetsFlagged:       2,
+		TargetsUnresolved:    1,
+		SuppressedScanned:    5,
+		SuppressedNormalized: 2,
+		SuppressedUnresolved: 2,
+	}
+	if report != want {
+		t.Fatalf("report = %+v, want %+v", report, want)
+	}
+	if !report.Unresolved() {
+		t.Fatal("Unresolved() = false with rows left for review")
+	}
+
+	if got := repo.targets[1].PhoneNumber; got != "+14155550101" {
+		t.Fatalf("target 1 number = %q, want +14155550101", got)
+	}
+	if got := repo.targets[5].PhoneNumber; got != "+14155550102" {
+		t.Fatalf("finished target number = %q, want +14155550102", got)
+	}
+	if got := repo.targets[2].PhoneNumber; got != "415-555-0100" {
+		t.Fatalf("duplicate target renumbered to %q", got)
+	}
+	if repo.flagged[ids[2]] != ReasonDuplicateNumber || repo.flagged[ids[3]] != ReasonInvalidNumber {
+		t.Fatalf("flagged = %v", repo.flagged)
+	}
+	if _, ok := repo.flagged[ids[4]]; ok {
+		t.Fatal("flagged a target that was already dialled")
+	}
+	if got := repo.suppressed[0].PhoneNumber; got != "+14155550197" {
+		t.Fatalf("global entry number = %q", got)
+	}
+	if got := repo.suppressed[2].PhoneNumber; got != "+14155550198" {
+		t.Fatalf("campaign entry number = %q", got)
+	}
+	if got := repo.suppressed[4].PhoneNumber; got != "+1 415 555 0199" {
+		t.Fatalf("duplicate entry renumbered to %q", got)
+	}
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	campaign := uuid.New()
+	ids := sequentialIDs(2)
+	repo := &fakeRepo{
+		targets: []repository.StoredTargetNumber{
+			{ID: ids[0], CampaignID: campaign, PhoneNumber: "415 555 0100", State: domain.TargetStatePending, Region: "US"},
+			{ID: ids[1], CampaignID: campaign, PhoneNumber: "bogus", State: domain.TargetStatePending, Region: "US"},
+		},
+		flagged: map[uuid.UUID]string{},
+	}
+
+	report, err := New(repo, &logger.Logger{Logger: zap.NewNop()}, 0, true).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.TargetsNormalized != 1 || report.TargetsFlagged != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if repo.targets[0].PhoneNumber != "415 555 0100" || len(repo.flagged) != 0 {
+		t.Fatal("dry run wrote changes")
+	}
+}
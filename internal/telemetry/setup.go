@@ -2,34 +2,27 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
-	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/acme/outbound-call-campaign/internal/config"
 )
 
-// Setup configures OpenTelemetry tracing and returns a shutdown function.
+// Setup configures OpenTelemetry tracing and metrics and returns a shutdown function.
 func Setup(ctx context.Context, cfg config.TelemetryConfig, serviceName string) (func(context.Context) error, error) {
-	if !cfg.TracingEnabled {
+	if !cfg.TracingEnabled && !cfg.MetricsEnabled {
 		return func(context.Context) error { return nil }, nil
 	}
 
-	ratio := cfg.SampleRatio
-	if ratio <= 0 {
-		ratio = 1.0
-	}
-
-	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithInsecure())
-	if err != nil {
-		return nil, fmt.Errorf("otel exporter: %w", err)
-	}
-
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(serviceName),
@@ -40,14 +33,71 @@ func Setup(ctx context.Context, cfg config.TelemetryConfig, serviceName string)
 		return nil, fmt.Errorf("otel resource: %w", err)
 	}
 
+	var shutdowns []func(context.Context) error
+	shutdown := func(ctx context.Context) error {
+		var errs []error
+		for _, fn := range shutdowns {
+			errs = append(errs, fn(ctx))
+		}
+		return errors.Join(errs...)
+	}
+
+	if cfg.TracingEnabled {
+		tp, err := tracerProvider(ctx, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+		shutdowns = append(shutdowns, tp.Shutdown)
+	}
+
+	if cfg.MetricsEnabled {
+		mp, err := meterProvider(ctx, cfg, res)
+		if err != nil {
+			_ = shutdown(ctx)
+			return nil, err
+		}
+		otel.SetMeterProvider(mp)
+		shutdowns = append(shutdowns, mp.Shutdown)
+	}
+
+	return shutdown, nil
+}
+
+func tracerProvider(ctx context.Context, cfg config.TelemetryConfig, res *resource.Resource) (*trace.TracerProvider, error) {
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1.0
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("otel exporter: %w", err)
+	}
+
 	sampler := trace.ParentBased(trace.TraceIDRatioBased(ratio))
-	tp := trace.NewTracerProvider(
+	return trace.NewTracerProvider(
 		trace.WithSampler(sampler),
 		trace.WithBatcher(exporter),
 		trace.WithResource(res),
-	)
+	), nil
+}
 
-	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
-	return tp.Shutdown, nil
+// meterProvider pushes every instrument registered on the global meter to the collector once per
+// MetricsInterval.
+func meterProvider(ctx context.Context, cfg config.TelemetryConfig, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpoint(cfg.Endpoint), otlpmetrichttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("otel metric exporter: %w", err)
+	}
+
+	var opts []sdkmetric.PeriodicReaderOption
+	if cfg.MetricsInterval > 0 {
+		opts = append(opts, sdkmetric.WithInterval(cfg.MetricsInterval))
+	}
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, opts...)),
+		sdkmetric.WithResource(res),
+	), nil
 }
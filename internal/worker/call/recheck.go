@@ -0,0 +1,154 @@
+package call
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+)
+
+// Reasons recorded on dispatches that went stale before dialing.
+const (
+	reasonCampaignNotFound  = "campaign_not_found"
+	reasonCampaignPending   = "campaign_pending"
+	reasonCampaignPaused    = "campaign_paused"
+	reasonCampaignCompleted = "campaign_completed"
+	reasonCampaignFailed    = "campaign_failed"
+	reasonCampaignInactive  = "campaign_inactive"
+	reasonCampaignEnded     = "campaign_ended"
+	reasonOutsideWindow     = "outside_calling_window"
+	reasonSuperseded        = "attempt_superseded"
+)
+
+// verdictAction is what the worker does with a dispatch after re-checking its campaign.
+type verdictAction int
+
+const (
+	actionDial verdictAction = iota
+	// actionCancel reports the call cancelled, as the campaign was.
+	actionCancel
+	// actionDefer hands the target back to the scheduler until the window opens again.
+	actionDefer
+	// actionSkip drops the attempt and hands the target back to the scheduler.
+	actionSkip
+	// actionDrop discards a dispatch whose attempt has already been settled, reporting nothing.
+	actionDrop
+)
+
+type verdict struct {
+	action verdictAction
+	until  time.Time
+	reason string
+}
+
+// dialCheck decides whether a dispatch for campaign may still be dialed at now. Campaigns that
+// are not in progress or have ended are skipped; a closed calling window defers the attempt to
+// the next opening, or skips it when none comes before the campaign ends. Recipient-local-time
+// campaigns are evaluated in zone, using window when they have no business hours. A schedule
+// that cannot be evaluated is let through, as the scheduler does.
+func dialCheck(campaign *domain.Campaign, zone string, window domain.DailyWindow, now time.Time) verdict {
+	switch campaign.Status {
+	case domain.CampaignStatusInProgress:
+	case domain.CampaignStatusCancelled:
+		return verdict{action: actionCancel, reason: queue.ReasonCampaignCancelled}
+	default:
+		return verdict{action: actionSkip, reason: inactiveReason(campaign.Status)}
+	}
+	if campaign.EndAt != nil && !now.Before(*campaign.EndAt) {
+		return verdict{action: actionSkip, reason: reasonCampaignEnded}
+	}
+
+	schedule := campaign
+	if campaign.RecipientLocalTime {
+		schedule = campaign.ForRecipient(zone, window)
+	}
+	open, err := schedule.IsOpenAt(now)
+	if err != nil || open {
+		return verdict{action: actionDial}
+	}
+	next, ok, err := schedule.NextOpen(now)
+	if err != nil || !ok || (campaign.EndAt != nil && !next.Before(*campaign.EndAt)) {
+		return verdict{action: actionSkip, reason: reasonOutsideWindow}
+	}
+	return verdict{action: actionDefer, until: next, reason: reasonOutsideWindow}
+}
+
+// inactiveReason names why a dispatch of a campaign that is not running is skipped.
+func inactiveReason(status domain.CampaignStatus) string {
+	switch status {
+	case domain.CampaignStatusPending:
+		return reasonCampaignPending
+	case domain.CampaignStatusPaused:
+		return reasonCampaignPaused
+	case domain.CampaignStatusCompleted:
+		return reasonCampaignCompleted
+	case domain.CampaignStatusFailed:
+		return reasonCampaignFailed
+	default:
+		return reasonCampaignInactive
+	}
+}
+
+// superseded reports whether the stored call shows that attempt is no longer its next one: the
+// call has ended or was handed back to the scheduler, or a later attempt is already on record,
+// as when the reconciler failed the attempt while its dispatch was still on the way.
+func superseded(call *domain.Call, attempt int) bool {
+	switch call.Status {
+	case domain.CallStatusCompleted, domain.CallStatusCancelled, domain.CallStatusSkipped, domain.CallStatusDeferred:
+		return true
+	}
+	return call.AttemptCount >= attempt
+}
+
+// campaignCache keeps campaigns, schedule included, for a short time so every dispatch can be
+// re-checked without reloading them.
+type campaignCache struct {
+	load func(ctx context.Context, id uuid.UUID) (*domain.Campaign, error)
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedCampaign
+}
+
+type cachedCampaign struct {
+	campaign  *domain.Campaign
+	expiresAt time.Time
+}
+
+func newCampaignCache(load func(ctx context.Context, id uuid.UUID) (*domain.Campaign, error), ttl time.Duration) *campaignCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+	return &campaignCache{load: load, ttl: ttl, entries: make(map[uuid.UUID]cachedCampaign)}
+}
+
+// get returns the campaign, loading it when the cached copy is missing or expired. The returned
+// campaign is shared and must not be modified.
+func (c *campaignCache) get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.campaign, nil
+	}
+
+	campaign, err := c.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[id] = cachedCampaign{campaign: campaign, expiresAt: now.Add(c.ttl)}
+	return campaign, nil
+}
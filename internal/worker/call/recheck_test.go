@@ -0,0 +1,85 @@
+package call
+
+import (
+	"testing"
+	"time"
+
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+)
+
+func clock(h, m int) time.Time {
+	return time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+}
+
+func TestDialCheck(t *testing.T) {
+	// Tuesday 4 June 2024, 14:00 UTC is 10:00 in New York and 07:00 in Los Angeles.
+	now := time.Date(2024, 6, 4, 14, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Minute)
+	endsSoon := now.Add(time.Hour)
+	weekdays := []domain.BusinessHourWindow{{DayOfWeek: time.Tuesday, Start: clock(12, 0), End: clock(17, 0)}}
+	window := domain.DailyWindow{Start: clock(8, 0), End: clock(21, 0)}
+
+	cases := []struct {
+		name     string
+		campaign domain.Campaign
+		zone     string
+		want     verdictAction
+		reason   string
+		until    time.Time
+	}{
+		{name: "open", campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, TimeZone: "America/New_York"}, want: actionDial},
+		{name: "paused", campaign: domain.Campaign{Status: domain.CampaignStatusPaused, TimeZone: "UTC"}, want: actionSkip, reason: reasonCampaignPaused},
+		{name: "completed", campaign: domain.Campaign{Status: domain.CampaignStatusCompleted, TimeZone: "UTC"}, want: actionSkip, reason: reasonCampaignCompleted},
+		{name: "cancelled", campaign: domain.Campaign{Status: domain.CampaignStatusCancelled, TimeZone: "UTC"}, want: actionCancel, reason: queue.ReasonCampaignCancelled},
+		{name: "ended", campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, TimeZone: "UTC", EndAt: &ended}, want: actionSkip, reason: reasonCampaignEnded},
+		{
+			name:     "window closed",
+			campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, TimeZone: "America/New_York", BusinessHours: weekdays},
+			want:     actionDefer, reason: reasonOutsideWindow, until: time.Date(2024, 6, 4, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "window opens after end",
+			campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, TimeZone: "America/New_York", BusinessHours: weekdays, EndAt: &endsSoon},
+			want:     actionSkip, reason: reasonOutsideWindow,
+		},
+		{
+			name:     "recipient zone closed",
+			campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, TimeZone: "America/New_York", RecipientLocalTime: true},
+			zone:     "America/Los_Angeles",
+			want:     actionDefer, reason: reasonOutsideWindow, until: time.Date(2024, 6, 4, 15, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		got := dialCheck(&tc.campaign, tc.zone, window, now)
+		if got.action != tc.want || got.reason != tc.reason || !got.until.Equal(tc.until) {
+			t.Errorf("%s: got %+v, want action %d reason %q until %v", tc.name, got, tc.want, tc.reason, tc.until)
+		}
+	}
+}
+
+func TestSuperseded(t *testing.T) {
+	cases := []struct {
+		name    string
+		call    domain.Call
+		attempt int
+		want    bool
+	}{
+		{name: "first attempt queued", call: domain.Call{Status: domain.CallStatusQueued}, attempt: 1, want: false},
+		{name: "retry after failed attempt", call: domain.Call{Status: domain.CallStatusFailed, AttemptCount: 1}, attempt: 2, want: false},
+		// The reconciler records the stuck attempt as failed before the dispatch arrives.
+		{name: "attempt failed by reconciler", call: domain.Call{Status: domain.CallStatusFailed, AttemptCount: 1}, attempt: 1, want: true},
+		{name: "redelivered after dialing", call: domain.Call{Status: domain.CallStatusDialing, AttemptCount: 2}, attempt: 2, want: true},
+		{name: "completed", call: domain.Call{Status: domain.CallStatusCompleted, AttemptCount: 1}, attempt: 2, want: true},
+		{name: "cancelled", call: domain.Call{Status: domain.CallStatusCancelled}, attempt: 1, want: true},
+		{name: "skipped", call: domain.Call{Status: domain.CallStatusSkipped}, attempt: 1, want: true},
+		{name: "deferred", call: domain.Call{Status: domain.CallStatusDeferred}, attempt: 1, want: true},
+	}
+
+	for _, tc := range cases {
+		if got := superseded(&tc.call, tc.attempt); got != tc.want {
+			t.Errorf("%s: superseded = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package call
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -17,8 +18,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/compliance"
 	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 	"github.com/acme/outbound-call-campaign/internal/service/concurrency"
 )
 
@@ -27,14 +30,24 @@ type Worker struct {
 	container *app.Container
 	rng       *rand.Rand
 	limiter   *concurrency.Limiter
+	campaigns *campaignCache
+	// recipientWindow is the scheduler's fallback window for recipient-local-time campaigns.
+	recipientWindow domain.DailyWindow
 }
 
 // New creates a new call worker instance.
 func New(container *app.Container) *Worker {
+	cfg := container.Config
+	window, err := domain.ParseDailyWindow(cfg.Scheduler.RecipientWindowStart, cfg.Scheduler.RecipientWindowEnd)
+	if err != nil {
+		container.Logger.Warn("call worker: invalid recipient window, ignoring", zapError(err))
+	}
 	return &Worker{
-		container: container,
-		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
-		limiter:   container.Limiters().Concurrency,
+		container:       container,
+		rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		limiter:         container.Limiters().Concurrency,
+		campaigns:       newCampaignCache(container.Services().Campaign.Get, cfg.CallBridge.CampaignCacheTTL),
+		recipientWindow: window,
 	}
 }
 
@@ -78,7 +91,14 @@ func (w *Worker) processMessage(ctx context.Context, reader *kafka.Reader, m kaf
 	))
 	defer span.End()
 
-	release, err := w.waitForSlot(sctx, dispatch)
+	// Re-check before waiting for a concurrency slot and again once one is held, as the wait
+	// can take minutes.
+	if v := w.recheck(sctx, dispatch); v.action != actionDial {
+		return w.settle(sctx, reader, m, dispatch, v)
+	}
+
+	settings := w.settings(sctx, dispatch)
+	release, err := w.waitForSlot(sctx, dispatch, settings.MaxConcurrentCalls)
 	if err != nil {
 		span.RecordError(err)
 		return err
@@ -86,6 +106,9 @@ func (w *Worker) processMessage(ctx context.Context, reader *kafka.Reader, m kaf
 	if release != nil {
 		defer release()
 	}
+	if v := w.recheck(sctx, dispatch); v.action != actionDial {
+		return w.settle(sctx, reader, m, dispatch, v)
+	}
 
 	cfg := w.container.Config
 	provider := w.container.Providers().Telephony
@@ -96,25 +119,30 @@ func (w *Worker) processMessage(ctx context.Context, reader *kafka.Reader, m kaf
 		timeout = 10 * time.Second
 	}
 
+	w.publishDialing(sctx, dispatch)
+
 	callCtx, cancel := context.WithTimeout(sctx, timeout)
 	result, callErr := provider.PlaceCall(callCtx, dispatch)
 	cancel()
 
+	// Decide on a retry with the settings current after the call, which may have changed meanwhile.
+	settings = w.settings(sctx, dispatch)
+	policy := settings.RetryPolicy
+
 	statusMsg := queue.StatusMessage{
-		CallID:           dispatch.CallID,
-		CampaignID:       dispatch.CampaignID,
-		PhoneNumber:      dispatch.PhoneNumber,
-		Status:           string(result.Status),
-		Attempt:          dispatch.Attempt,
-		MaxAttempts:      dispatch.MaxAttempts,
-		Retryable:        result.Retryable && dispatch.Attempt < dispatch.MaxAttempts,
-		RetryBaseMs:      dispatch.RetryBaseMs,
-		RetryMaxMs:       dispatch.RetryMaxMs,
-		RetryJitter:      dispatch.RetryJitter,
-		ConcurrencyLimit: dispatch.ConcurrencyLimit,
-		Error:            result.Error,
-		OccurredAt:       time.Now().UTC(),
-		Metadata:         dispatch.Metadata,
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		TimeZone:      dispatch.TimeZone,
+		Status:        string(result.Status),
+		Attempt:       dispatch.Attempt,
+		Retryable:     result.Retryable && dispatch.Attempt < policy.MaxAttempts,
+		ConfigVersion: settings.Version,
+		Error:         result.Error,
+		OccurredAt:    time.Now().UTC(),
+		EndAt:         dispatch.EndAt,
+		Metadata:      dispatch.Metadata,
 	}
 
 	if result.Duration > 0 {
@@ -123,13 +151,13 @@ func (w *Worker) processMessage(ctx context.Context, reader *kafka.Reader, m kaf
 
 	if callErr != nil && statusMsg.Error == "" {
 		statusMsg.Error = callErr.Error()
-		statusMsg.Retryable = dispatch.Attempt < dispatch.MaxAttempts
+		statusMsg.Retryable = dispatch.Attempt < policy.MaxAttempts
 		statusMsg.Status = string(domain.CallStatusFailed)
 		span.RecordError(callErr)
 	}
 
 	if statusMsg.Retryable {
-		next := w.computeNextAttempt(dispatch)
+		next := w.computeNextAttempt(dispatch.Attempt, policy)
 		statusMsg.NextAttempt = &next
 	}
 
@@ -145,17 +173,161 @@ func (w *Worker) processMessage(ctx context.Context, reader *kafka.Reader, m kaf
 	return nil
 }
 
-func (w *Worker) waitForSlot(ctx context.Context, dispatch queue.DispatchMessage) (func(), error) {
-	limiter := w.limiter
-	if limiter == nil || dispatch.CampaignID == uuid.Nil {
-		return nil, nil
+// recheck decides, just before dialing, whether the dispatch is still due: the stored call must
+// still be waiting for this attempt, the campaign's end carried on the dispatch must not have
+// passed, its campaign is looked up through the cache and checked by dialCheck, then the
+// recipient is checked against regulatory quiet hours. Call and campaign lookup failures let the
+// call through, as the scheduler checked both when it dispatched; a recipient whose quiet hours
+// cannot be checked is deferred rather than dialed.
+func (w *Worker) recheck(ctx context.Context, dispatch queue.DispatchMessage) verdict {
+	logger := w.container.Logger
+	now := time.Now().UTC()
+	call, err := w.container.Repositories().CallStore.GetCall(ctx, dispatch.CallID)
+	switch {
+	case err != nil:
+		trace.SpanFromContext(ctx).RecordError(err)
+		logger.Warn("call worker: call lookup", zapError(err), zap.String("call_id", dispatch.CallID.String()))
+	case superseded(call, dispatch.Attempt):
+		return verdict{action: actionDrop, reason: reasonSuperseded}
+	}
+	if dispatch.EndAt != nil && !now.Before(*dispatch.EndAt) {
+		return verdict{action: actionSkip, reason: reasonCampaignEnded}
+	}
+
+	if dispatch.CampaignID != uuid.Nil {
+		campaign, err := w.campaigns.get(ctx, dispatch.CampaignID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return verdict{action: actionSkip, reason: reasonCampaignNotFound}
+		case err != nil:
+			trace.SpanFromContext(ctx).RecordError(err)
+			logger.Warn("call worker: campaign lookup", zapError(err))
+		default:
+			if v := dialCheck(campaign, dispatch.TimeZone, w.recipientWindow, now); v.action != actionDial {
+				return v
+			}
+		}
+	}
+
+	decision, err := w.container.Compliance.Check(dispatch.PhoneNumber, dispatch.TimeZone, now)
+	if err != nil {
+		logger.Warn("call worker: quiet hours check", zapError(err), zap.String("call_id", dispatch.CallID.String()))
+		return verdict{action: actionDefer, until: now.Add(compliance.UnverifiedDelay), reason: compliance.ReasonUnverified}
+	}
+	if !decision.Allowed {
+		logger.Info("call worker: recipient in quiet hours",
+			zap.String("call_id", dispatch.CallID.String()),
+			zap.String("rule", decision.Rule),
+			zap.String("time_zone", decision.TimeZone),
+			zap.Time("next_allowed", decision.NextAllowed))
+		return verdict{action: actionDefer, until: decision.NextAllowed, reason: compliance.ReasonQuietHours}
 	}
+	return verdict{action: actionDial}
+}
+
+// settle carries out a verdict other than dialing and commits the message.
+func (w *Worker) settle(ctx context.Context, reader *kafka.Reader, m kafka.Message, dispatch queue.DispatchMessage, v verdict) error {
+	span := trace.SpanFromContext(ctx)
+	switch v.action {
+	case actionCancel:
+		span.SetAttributes(attribute.Bool("campaign.cancelled", true))
+		if err := w.container.Dispatchers().StatusPublisher.PublishCancelled(ctx, dispatch); err != nil {
+			w.container.Logger.Error("call worker: publish cancelled status", zapError(err))
+		}
+	case actionDefer:
+		w.publishDeferred(ctx, dispatch, v.until, v.reason)
+	case actionSkip:
+		w.publishSkipped(ctx, dispatch, v.reason)
+	case actionDrop:
+		// The attempt was already settled elsewhere; reporting it again would undo that.
+	}
+	span.SetAttributes(attribute.String("dispatch.held_reason", v.reason))
+	w.container.Logger.Info("call worker: dispatch not dialed",
+		zap.String("call_id", dispatch.CallID.String()),
+		zap.String("campaign_id", dispatch.CampaignID.String()),
+		zap.String("reason", v.reason))
 
-	limit := dispatch.ConcurrencyLimit
-	if limit <= 0 {
-		limit = w.container.Config.Throttle.DefaultPerCampaign
+	if err := reader.CommitMessages(ctx, m); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("commit message: %w", err)
 	}
-	if limit <= 0 {
+	return nil
+}
+
+// publishDeferred reports the attempt as deferred for reason without using it up. The status
+// worker hands the target back to the scheduler, which claims it for a new call at until.
+func (w *Worker) publishDeferred(ctx context.Context, dispatch queue.DispatchMessage, until time.Time, reason string) {
+	statusMsg := queue.StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		TimeZone:      dispatch.TimeZone,
+		Status:        string(domain.CallStatusDeferred),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		Error:         reason,
+		OccurredAt:    time.Now().UTC(),
+		NextAttempt:   &until,
+		Metadata:      dispatch.Metadata,
+	}
+	if err := w.container.Dispatchers().StatusPublisher.PublishStatus(ctx, statusMsg); err != nil {
+		w.container.Logger.Error("call worker: publish deferred status", zapError(err))
+	}
+}
+
+// publishSkipped reports the call as skipped for reason without dialing it.
+func (w *Worker) publishSkipped(ctx context.Context, dispatch queue.DispatchMessage, reason string) {
+	statusMsg := queue.StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		TimeZone:      dispatch.TimeZone,
+		Status:        string(domain.CallStatusSkipped),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		Error:         reason,
+		OccurredAt:    time.Now().UTC(),
+		Metadata:      dispatch.Metadata,
+	}
+	if err := w.container.Dispatchers().StatusPublisher.PublishStatus(ctx, statusMsg); err != nil {
+		w.container.Logger.Error("call worker: publish skipped status", zapError(err))
+	}
+}
+
+// publishDialing reports that the attempt is about to be placed.
+func (w *Worker) publishDialing(ctx context.Context, dispatch queue.DispatchMessage) {
+	statusMsg := queue.StatusMessage{
+		CallID:        dispatch.CallID,
+		CampaignID:    dispatch.CampaignID,
+		TargetID:      dispatch.TargetID,
+		PhoneNumber:   dispatch.PhoneNumber,
+		Status:        string(domain.CallStatusDialing),
+		Attempt:       dispatch.Attempt,
+		ConfigVersion: dispatch.ConfigVersion,
+		OccurredAt:    time.Now().UTC(),
+		Metadata:      dispatch.Metadata,
+	}
+	if err := w.container.Dispatchers().StatusPublisher.PublishStatus(ctx, statusMsg); err != nil {
+		w.container.Logger.Error("call worker: publish dialing status", zapError(err))
+	}
+}
+
+// settings resolves the campaign's current limits, falling back to the configured defaults when
+// they cannot be loaded.
+func (w *Worker) settings(ctx context.Context, dispatch queue.DispatchMessage) domain.CampaignSettings {
+	settings, err := w.container.Services().Call.Settings(ctx, dispatch.CampaignID)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		w.container.Logger.Warn("call worker: resolve campaign settings", zapError(err), zap.String("campaign_id", dispatch.CampaignID.String()))
+	}
+	return settings
+}
+
+func (w *Worker) waitForSlot(ctx context.Context, dispatch queue.DispatchMessage, limit int) (func(), error) {
+	limiter := w.limiter
+	if limiter == nil || dispatch.CampaignID == uuid.Nil || limit <= 0 {
 		return nil, nil
 	}
 
@@ -185,24 +357,24 @@ func (w *Worker) waitForSlot(ctx context.Context, dispatch queue.DispatchMessage
 	}
 }
 
-func (w *Worker) computeNextAttempt(msg queue.DispatchMessage) time.Time {
-	base := time.Duration(msg.RetryBaseMs) * time.Millisecond
+func (w *Worker) computeNextAttempt(attempt int, policy domain.RetryPolicy) time.Time {
+	base := policy.BaseDelay
 	if base <= 0 {
 		base = 2 * time.Second
 	}
-	maxDelay := time.Duration(msg.RetryMaxMs) * time.Millisecond
+	maxDelay := policy.MaxDelay
 	if maxDelay <= 0 {
 		maxDelay = 2 * time.Minute
 	}
 
-	exponent := math.Pow(2, float64(msg.Attempt-1))
+	exponent := math.Pow(2, float64(attempt-1))
 	delay := time.Duration(exponent) * base
 	if delay > maxDelay {
 		delay = maxDelay
 	}
 
-	if msg.RetryJitter > 0 {
-		jitterFraction := w.rng.Float64()*msg.RetryJitter - (msg.RetryJitter / 2)
+	if policy.Jitter > 0 {
+		jitterFraction := w.rng.Float64()*policy.Jitter - (policy.Jitter / 2)
 		jitter := time.Duration(float64(delay) * jitterFraction)
 		delay += jitter
 		if delay < base {
@@ -0,0 +1,276 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+)
+
+// purgeInterval is how often sent messages older than the retention are deleted.
+const purgeInterval = time.Minute
+
+// defaultMaxAttempts bounds how often a message is claimed when outbox.max_attempts is unset.
+const defaultMaxAttempts = 10
+
+// messageWriter publishes to a single topic; *kafka.Writer implements it.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// undeliveredReporter reports calls whose dispatch was given up on; *queue.StatusPublisher
+// implements it.
+type undeliveredReporter interface {
+	PublishUndelivered(ctx context.Context, dispatch queue.DispatchMessage, reason string) error
+}
+
+// Worker relays outbox messages to Kafka. A message is marked sent only after Kafka has
+// acknowledged it, so a relay that dies in between leaves it to be published again once its
+// claim expires: delivery is at-least-once.
+type Worker struct {
+	container *app.Container
+	outbox    repository.OutboxRepository
+	status    undeliveredReporter
+	owner     string
+	newWriter func(topic string) messageWriter
+	writers   map[string]messageWriter
+}
+
+// New creates an outbox relay.
+func New(container *app.Container) *Worker {
+	return &Worker{
+		container: container,
+		outbox:    container.Repositories().Outbox,
+		status:    container.Dispatchers().StatusPublisher,
+		owner:     relayIdentity(),
+		newWriter: func(topic string) messageWriter { return container.Kafka.NewWriter(topic) },
+		writers:   make(map[string]messageWriter),
+	}
+}
+
+// Run polls the outbox until the context is cancelled.
+func (w *Worker) Run(ctx context.Context) error {
+	cfg := w.container.Config.Outbox
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+	defer w.close()
+
+	w.container.Logger.Info("outbox relay: starting", zap.String("owner", w.owner))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastPurge time.Time
+	for {
+		w.drain(ctx)
+		if cfg.Retention > 0 && time.Since(lastPurge) >= purgeInterval {
+			w.purge(ctx, cfg.Retention)
+			lastPurge = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain relays batches until the outbox has no more claimable messages or a batch fails.
+func (w *Worker) drain(ctx context.Context) {
+	batchSize := w.container.Config.Outbox.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	for ctx.Err() == nil {
+		n, err := w.relayBatch(ctx, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.container.Logger.Error("outbox relay: relay batch", zap.Error(err))
+			}
+			return
+		}
+		if n < batchSize {
+			return
+		}
+	}
+}
+
+// relayBatch claims up to limit messages, publishes them grouped by topic and marks the
+// published ones sent. Messages that could not be published are released for the next poll. It
+// reports how many messages were claimed.
+func (w *Worker) relayBatch(ctx context.Context, limit int) (int, error) {
+	cfg := w.container.Config.Outbox
+	ttl := cfg.ClaimTTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	tracer := otel.Tracer("outbound.outboxrelay")
+	sctx, span := tracer.Start(ctx, "outbox.relay")
+	defer span.End()
+
+	messages, err := w.outbox.Claim(sctx, w.owner, limit, maxAttempts, ttl)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int("outbox.claimed", len(messages)))
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	var topics []string
+	byTopic := make(map[string][]repository.OutboxMessage)
+	for _, msg := range messages {
+		if _, ok := byTopic[msg.Topic]; !ok {
+			topics = append(topics, msg.Topic)
+		}
+		byTopic[msg.Topic] = append(byTopic[msg.Topic], msg)
+	}
+
+	var failed error
+	for _, topic := range topics {
+		batch := byTopic[topic]
+		ids := make([]int64, 0, len(batch))
+		records := make([]kafka.Message, 0, len(batch))
+		for _, msg := range batch {
+			ids = append(ids, msg.ID)
+			records = append(records, kafka.Message{Key: msg.Key, Value: msg.Payload, Time: time.Now().UTC()})
+		}
+
+		if err := w.writer(topic).WriteMessages(sctx, records...); err != nil {
+			span.RecordError(err)
+			failed = fmt.Errorf("publish to %s: %w", topic, err)
+			ids = w.releaseUnsent(sctx, topic, batch, err, maxAttempts)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		// A message published but not marked sent is published again once its claim expires.
+		if err := w.outbox.MarkSent(sctx, ids); err != nil {
+			span.RecordError(err)
+			return len(messages), err
+		}
+	}
+	return len(messages), failed
+}
+
+// releaseUnsent releases the messages of batch that a failed write did not publish and returns
+// the ids of those it did. Kafka reports per-message results as kafka.WriteErrors; any other
+// error fails the whole batch. A message released after its last attempt is no longer claimed
+// and stays in the outbox, unsent, with its error; its call is reported failed.
+func (w *Worker) releaseUnsent(ctx context.Context, topic string, batch []repository.OutboxMessage, err error, maxAttempts int) []int64 {
+	var results kafka.WriteErrors
+	if !errors.As(err, &results) || len(results) != len(batch) {
+		ids := make([]int64, 0, len(batch))
+		for _, msg := range batch {
+			ids = append(ids, msg.ID)
+			w.checkExhausted(ctx, msg, topic, err, maxAttempts)
+		}
+		w.release(ctx, topic, ids, err)
+		return nil
+	}
+
+	var sent []int64
+	for i, msg := range batch {
+		if results[i] == nil {
+			sent = append(sent, msg.ID)
+			continue
+		}
+		w.checkExhausted(ctx, msg, topic, results[i], maxAttempts)
+		w.release(ctx, topic, []int64{msg.ID}, results[i])
+	}
+	return sent
+}
+
+func (w *Worker) release(ctx context.Context, topic string, ids []int64, cause error) {
+	if err := w.outbox.Release(ctx, ids, cause.Error()); err != nil {
+		w.container.Logger.Warn("outbox relay: release", zap.Error(err), zap.String("topic", topic))
+	}
+}
+
+// checkExhausted gives up on a message that has used its last attempt. A dispatch given up on
+// would leave its call queued and its target waiting on it, holding the campaign's pending call
+// count, so the call is reported failed through the status topic, which settles all three.
+func (w *Worker) checkExhausted(ctx context.Context, msg repository.OutboxMessage, topic string, cause error, maxAttempts int) {
+	if msg.Attempts < maxAttempts {
+		return
+	}
+	logger := w.container.Logger
+	logger.Error("outbox relay: giving up on message",
+		zap.Error(cause),
+		zap.Int64("outbox_id", msg.ID),
+		zap.String("topic", topic),
+		zap.Int("attempts", msg.Attempts))
+
+	if topic != w.container.Config.Kafka.CallTopic {
+		return
+	}
+	var dispatch queue.DispatchMessage
+	if err := json.Unmarshal(msg.Payload, &dispatch); err != nil {
+		logger.Error("outbox relay: decode abandoned dispatch", zap.Error(err), zap.Int64("outbox_id", msg.ID))
+		return
+	}
+	reason := fmt.Sprintf("outbox: dispatch not published after %d attempts: %v", msg.Attempts, cause)
+	if err := w.status.PublishUndelivered(ctx, dispatch, reason); err != nil {
+		// The reconciler fails the call once its target has been queued for too long.
+		logger.Error("outbox relay: report abandoned call", zap.Error(err), zap.String("call_id", dispatch.CallID.String()))
+	}
+}
+
+func (w *Worker) purge(ctx context.Context, retention time.Duration) {
+	n, err := w.outbox.PurgeSent(ctx, time.Now().Add(-retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			w.container.Logger.Warn("outbox relay: purge sent", zap.Error(err))
+		}
+		return
+	}
+	if n > 0 {
+		w.container.Logger.Debug("outbox relay: purged sent messages", zap.Int64("count", n))
+	}
+}
+
+func (w *Worker) writer(topic string) messageWriter {
+	writer, ok := w.writers[topic]
+	if !ok {
+		writer = w.newWriter(topic)
+		w.writers[topic] = writer
+	}
+	return writer
+}
+
+func (w *Worker) close() {
+	for topic, writer := range w.writers {
+		if err := writer.Close(); err != nil {
+			w.container.Logger.Warn("outbox relay: close writer", zap.Error(err), zap.String("topic", topic))
+		}
+	}
+}
+
+func relayIdentity() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "outbox-relay"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
@@ -0,0 +1,205 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/config"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+type fakeOutbox struct {
+	repository.OutboxRepository
+	pending     []repository.OutboxMessage
+	maxAttempts int
+	sent        []int64
+	released    map[int64]string
+}
+
+func (f *fakeOutbox) Claim(_ context.Context, _ string, limit, maxAttempts int, _ time.Duration) ([]repository.OutboxMessage, error) {
+	f.maxAttempts = maxAttempts
+	n := min(limit, len(f.pending))
+	claimed := f.pending[:n]
+	f.pending = f.pending[n:]
+	return claimed, nil
+}
+
+func (f *fakeOutbox) MarkSent(_ context.Context, ids []int64) error {
+	f.sent = append(f.sent, ids...)
+	return nil
+}
+
+func (f *fakeOutbox) Release(_ context.Context, ids []int64, reason string) error {
+	for _, id := range ids {
+		f.released[id] = reason
+	}
+	return nil
+}
+
+type fakeWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (f *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	f.written = append(f.written, msgs...)
+	return f.err
+}
+
+func (f *fakeWriter) Close() error { return nil }
+
+type undelivered struct {
+	dispatch queue.DispatchMessage
+	reason   string
+}
+
+type fakeReporter struct {
+	reported []undelivered
+}
+
+func (f *fakeReporter) PublishUndelivered(_ context.Context, dispatch queue.DispatchMessage, reason string) error {
+	f.reported = append(f.reported, undelivered{dispatch: dispatch, reason: reason})
+	return nil
+}
+
+func testWorker(messages ...repository.OutboxMessage) (*Worker, *fakeOutbox, map[string]*fakeWriter) {
+	outbox := &fakeOutbox{pending: messages, released: make(map[int64]string)}
+	writers := make(map[string]*fakeWriter)
+	cfg := &config.Config{}
+	cfg.Kafka.CallTopic = "calls"
+	container := &app.Container{Config: cfg, Logger: &logger.Logger{Logger: zap.NewNop()}}
+	w := &Worker{
+		container: container,
+		outbox:    outbox,
+		status:    &fakeReporter{},
+		owner:     "test",
+		newWriter: func(topic string) messageWriter {
+			writer, ok := writers[topic]
+			if !ok {
+				writer = &fakeWriter{}
+				writers[topic] = writer
+			}
+			return writer
+		},
+		writers: make(map[string]messageWriter),
+	}
+	return w, outbox, writers
+}
+
+func message(id int64, topic string) repository.OutboxMessage {
+	return repository.OutboxMessage{ID: id, Topic: topic, Key: []byte{byte(id)}, Payload: []byte(`{}`), Attempts: 1}
+}
+
+func TestRelayBatchMarksPublishedSent(t *testing.T) {
+	w, outbox, writers := testWorker(message(1, "calls"), message(2, "status"), message(3, "calls"))
+
+	n, err := w.relayBatch(context.Background(), 10)
+	if err != nil || n != 3 {
+		t.Fatalf("relayBatch = %d, %v; want 3, nil", n, err)
+	}
+	if got := len(writers["calls"].written); got != 2 {
+		t.Errorf("calls topic got %d messages, want 2", got)
+	}
+	if got := len(writers["status"].written); got != 1 {
+		t.Errorf("status topic got %d messages, want 1", got)
+	}
+	if want := []int64{1, 3, 2}; !reflect.DeepEqual(outbox.sent, want) {
+		t.Errorf("sent = %v, want %v", outbox.sent, want)
+	}
+	if outbox.maxAttempts != defaultMaxAttempts {
+		t.Errorf("claimed with max attempts %d, want %d", outbox.maxAttempts, defaultMaxAttempts)
+	}
+}
+
+func TestRelayBatchReleasesFailedTopic(t *testing.T) {
+	w, outbox, writers := testWorker(message(1, "calls"), message(2, "status"))
+	writers["calls"] = &fakeWriter{err: errors.New("broker down")}
+
+	n, err := w.relayBatch(context.Background(), 10)
+	if err == nil || n != 2 {
+		t.Fatalf("relayBatch = %d, %v; want 2 and an error", n, err)
+	}
+	if want := []int64{2}; !reflect.DeepEqual(outbox.sent, want) {
+		t.Errorf("sent = %v, want %v", outbox.sent, want)
+	}
+	if reason := outbox.released[1]; reason != "broker down" {
+		t.Errorf("message 1 released with %q, want broker down", reason)
+	}
+	if _, ok := outbox.released[2]; ok {
+		t.Error("published message released")
+	}
+}
+
+func TestRelayBatchReleasesOnlyRejectedMessages(t *testing.T) {
+	w, outbox, writers := testWorker(message(1, "calls"), message(2, "calls"), message(3, "calls"))
+	writers["calls"] = &fakeWriter{err: kafka.WriteErrors{nil, errors.New("message too large"), nil}}
+
+	if _, err := w.relayBatch(context.Background(), 10); err == nil {
+		t.Fatal("relayBatch succeeded with a rejected message")
+	}
+	if want := []int64{1, 3}; !reflect.DeepEqual(outbox.sent, want) {
+		t.Errorf("sent = %v, want %v", outbox.sent, want)
+	}
+	if want := map[int64]string{2: "message too large"}; !reflect.DeepEqual(outbox.released, want) {
+		t.Errorf("released = %v, want %v", outbox.released, want)
+	}
+}
+
+func TestRelayBatchRespectsLimit(t *testing.T) {
+	w, outbox, _ := testWorker(message(1, "calls"), message(2, "calls"), message(3, "calls"))
+	w.container.Config.Outbox.MaxAttempts = 3
+
+	n, err := w.relayBatch(context.Background(), 2)
+	if err != nil || n != 2 {
+		t.Fatalf("relayBatch = %d, %v; want 2, nil", n, err)
+	}
+	if len(outbox.pending) != 1 {
+		t.Errorf("%d messages left unclaimed, want 1", len(outbox.pending))
+	}
+	if outbox.maxAttempts != 3 {
+		t.Errorf("claimed with max attempts %d, want 3", outbox.maxAttempts)
+	}
+}
+
+func TestRelayBatchReportsExhaustedDispatchFailed(t *testing.T) {
+	dispatch := queue.DispatchMessage{CallID: uuid.New(), CampaignID: uuid.New(), TargetID: uuid.New(), Attempt: 1}
+	payload, err := json.Marshal(dispatch)
+	if err != nil {
+		t.Fatalf("marshal dispatch: %v", err)
+	}
+	exhausted := message(1, "calls")
+	exhausted.Payload, exhausted.Attempts = payload, 3
+	early := message(2, "calls")
+	early.Attempts = 2
+
+	w, outbox, writers := testWorker(exhausted, early)
+	w.container.Config.Outbox.MaxAttempts = 3
+	writers["calls"] = &fakeWriter{err: errors.New("broker down")}
+	reporter := w.status.(*fakeReporter)
+
+	if _, err := w.relayBatch(context.Background(), 10); err == nil {
+		t.Fatal("relayBatch succeeded with the broker down")
+	}
+	if len(outbox.released) != 2 {
+		t.Errorf("released %v, want both messages", outbox.released)
+	}
+	if len(reporter.reported) != 1 {
+		t.Fatalf("reported %d calls, want only the exhausted dispatch", len(reporter.reported))
+	}
+	got := reporter.reported[0]
+	if got.dispatch.CallID != dispatch.CallID || got.dispatch.TargetID != dispatch.TargetID || !strings.Contains(got.reason, "broker down") {
+		t.Errorf("reported %+v, want the exhausted call with the broker error", got)
+	}
+}
@@ -7,23 +7,69 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/domain"
 	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
 )
 
+// Reasons recorded on retries dropped because their campaign is no longer running.
+const (
+	reasonCampaignCompleted = "campaign_completed"
+	reasonCampaignFailed    = "campaign_failed"
+	reasonCampaignEnded     = "campaign_ended"
+)
+
+// dispatchAttempts bounds how often a due retry is offered to the call topic before its call is
+// reported failed.
+const dispatchAttempts = 5
+
+// callDispatcher publishes dispatches; *queue.CallDispatcher implements it.
+type callDispatcher interface {
+	DispatchCall(ctx context.Context, msg queue.DispatchMessage) error
+}
+
+// statusReporter reports retries that are not dispatched; *queue.StatusPublisher implements it.
+type statusReporter interface {
+	PublishCancelled(ctx context.Context, dispatch queue.DispatchMessage) error
+	PublishSkipped(ctx context.Context, dispatch queue.DispatchMessage, reason string) error
+	PublishUndelivered(ctx context.Context, dispatch queue.DispatchMessage, reason string) error
+}
+
+// retryBacklog tracks outstanding retries per campaign; *concurrency.RetryBacklog implements it.
+type retryBacklog interface {
+	Remove(ctx context.Context, campaignID, callID uuid.UUID) error
+}
+
 // Worker handles retry scheduling for failed calls.
 type Worker struct {
-	container *app.Container
+	container       *app.Container
+	campaigns       repository.CampaignRepository
+	settings        func(ctx context.Context, campaignID uuid.UUID) (domain.CampaignSettings, error)
+	dispatcher      callDispatcher
+	status          statusReporter
+	backlog         retryBacklog
+	dispatchBackoff time.Duration
 }
 
 // New creates a retry worker instance.
 func New(container *app.Container) *Worker {
-	return &Worker{container: container}
+	return &Worker{
+		container:       container,
+		campaigns:       container.Repositories().Campaign,
+		settings:        container.Services().Call.Settings,
+		dispatcher:      container.Dispatchers().CallDispatcher,
+		status:          container.Dispatchers().StatusPublisher,
+		backlog:         container.Limiters().RetryBacklog,
+		dispatchBackoff: 500 * time.Millisecond,
+	}
 }
 
 // Run waits for cancellation. Logic will be implemented later.
@@ -74,7 +120,6 @@ func (w *Worker) consumeTopic(ctx context.Context, topic string, attemptIndex in
 	reader := w.container.Kafka.NewReader(topic, groupID)
 	defer reader.Close()
 
-	dispatcher := w.container.Dispatchers().CallDispatcher
 	logger := w.container.Logger
 
 	for {
@@ -87,41 +132,153 @@ func (w *Worker) consumeTopic(ctx context.Context, topic string, attemptIndex in
 			continue
 		}
 
-		var retryMsg queue.RetryMessage
-		if err := json.Unmarshal(msg.Value, &retryMsg); err != nil {
-			logger.Error("retry worker: unmarshal", zap.Error(err))
-			_ = reader.CommitMessages(ctx, msg)
-			continue
+		// Left uncommitted, the retry is delivered again once the worker restarts.
+		if err := w.handle(ctx, msg); err != nil {
+			return err
 		}
-
-		tracer := otel.Tracer("outbound.retryworker")
-		sctx, span := tracer.Start(ctx, "retry.dispatch", trace.WithAttributes(
-			attribute.String("call.id", retryMsg.CallID.String()),
-			attribute.String("campaign.id", retryMsg.CampaignID.String()),
-			attribute.Int("attempt", retryMsg.DispatchMessage.Attempt),
-		))
-		defer span.End()
-
-		if sleepErr := w.sleepUntil(sctx, retryMsg.NextAttempt); sleepErr != nil {
-			span.RecordError(sleepErr)
-			logger.Error("retry worker: wait", zap.Error(sleepErr))
-			_ = reader.CommitMessages(sctx, msg)
-			continue
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			logger.Error("retry worker: commit", zap.Error(err))
 		}
+	}
+}
 
-		dispatch := retryMsg.DispatchMessage
-		dispatch.EnqueuedAt = time.Now().UTC()
+// handle waits until the retry is due and dispatches it, unless its campaign has stopped
+// running meanwhile. Every retry leaves the campaign's backlog once it is settled, so an error
+// is only returned when ctx is cancelled before that.
+func (w *Worker) handle(ctx context.Context, msg kafka.Message) error {
+	logger := w.container.Logger
 
-		if err := dispatcher.DispatchCall(sctx, dispatch); err != nil {
-			span.RecordError(err)
-			logger.Error("retry worker: dispatch", zap.Error(err))
-			continue
+	var retryMsg queue.RetryMessage
+	if err := json.Unmarshal(msg.Value, &retryMsg); err != nil {
+		logger.Error("retry worker: unmarshal", zap.Error(err))
+		return nil
+	}
+
+	tracer := otel.Tracer("outbound.retryworker")
+	sctx, span := tracer.Start(ctx, "retry.dispatch", trace.WithAttributes(
+		attribute.String("call.id", retryMsg.CallID.String()),
+		attribute.String("campaign.id", retryMsg.CampaignID.String()),
+		attribute.Int("attempt", retryMsg.DispatchMessage.Attempt),
+	))
+	defer span.End()
+
+	if err := w.sleepUntil(sctx, retryMsg.NextAttempt); err != nil {
+		return err
+	}
+
+	// The campaign is checked once the retry is due, as it may have ended while it waited.
+	dispatch := retryMsg.DispatchMessage
+	reason, err := w.campaignStopped(sctx, dispatch, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		logger.Warn("retry worker: campaign lookup", zap.Error(err))
+	}
+	if reason != "" {
+		span.SetAttributes(attribute.String("retry.dropped_reason", reason))
+		w.drop(sctx, dispatch, reason)
+		w.untrack(sctx, dispatch)
+		return nil
+	}
+
+	dispatch.EnqueuedAt = time.Now().UTC()
+	dispatch.ConfigVersion = w.configVersion(sctx, dispatch)
+
+	if err := w.redispatch(sctx, dispatch); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		span.RecordError(err)
+		logger.Error("retry worker: giving up on dispatch", zap.Error(err), zap.String("call_id", dispatch.CallID.String()))
+		// The call would otherwise stay queued with its target waiting on it.
+		reason := fmt.Sprintf("retry: dispatch not published after %d attempts: %v", dispatchAttempts, err)
+		if err := w.status.PublishUndelivered(sctx, dispatch, reason); err != nil {
+			logger.Error("retry worker: report undelivered call", zap.Error(err), zap.String("call_id", dispatch.CallID.String()))
 		}
+	}
+	w.untrack(sctx, dispatch)
+	return nil
+}
 
-		if err := reader.CommitMessages(sctx, msg); err != nil {
-			span.RecordError(err)
-			logger.Error("retry worker: commit", zap.Error(err))
+// redispatch offers the retry to the call topic up to dispatchAttempts times, backing off between
+// attempts.
+func (w *Worker) redispatch(ctx context.Context, dispatch queue.DispatchMessage) error {
+	backoff := w.dispatchBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = w.dispatcher.DispatchCall(ctx, dispatch); err == nil {
+			return nil
 		}
+		if attempt == dispatchAttempts {
+			return err
+		}
+		w.container.Logger.Warn("retry worker: dispatch", zap.Error(err),
+			zap.String("call_id", dispatch.CallID.String()), zap.Int("attempt", attempt))
+		if sleepErr := w.sleepUntil(ctx, time.Now().Add(backoff)); sleepErr != nil {
+			return sleepErr
+		}
+		backoff *= 2
+	}
+}
+
+// campaignStopped returns why the retry must not be dialed at now: its campaign was cancelled,
+// reached another terminal status or passed its end. It returns "" while the campaign runs, and
+// also when the campaign cannot be loaded, leaving the call worker to re-check it.
+func (w *Worker) campaignStopped(ctx context.Context, dispatch queue.DispatchMessage, now time.Time) (string, error) {
+	if dispatch.CampaignID == uuid.Nil {
+		return "", nil
+	}
+	campaign, err := w.campaigns.Get(ctx, dispatch.CampaignID)
+	if err != nil {
+		return "", fmt.Errorf("lookup campaign %s: %w", dispatch.CampaignID, err)
+	}
+	switch {
+	case campaign.Status == domain.CampaignStatusCancelled:
+		return queue.ReasonCampaignCancelled, nil
+	case campaign.Status == domain.CampaignStatusCompleted:
+		return reasonCampaignCompleted, nil
+	case campaign.Status.IsTerminal():
+		return reasonCampaignFailed, nil
+	case campaign.EndAt != nil && !now.Before(*campaign.EndAt):
+		return reasonCampaignEnded, nil
+	}
+	return "", nil
+}
+
+// drop reports a retry of a stopped campaign: cancelled along with a cancelled campaign,
+// skipped otherwise.
+func (w *Worker) drop(ctx context.Context, dispatch queue.DispatchMessage, reason string) {
+	var err error
+	if reason == queue.ReasonCampaignCancelled {
+		err = w.status.PublishCancelled(ctx, dispatch)
+	} else {
+		err = w.status.PublishSkipped(ctx, dispatch, reason)
+	}
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		w.container.Logger.Error("retry worker: publish dropped retry", zap.Error(err),
+			zap.String("call_id", dispatch.CallID.String()), zap.String("reason", reason))
+	}
+}
+
+// configVersion returns the campaign's current settings version so the re-dispatched attempt
+// records the settings it will run under, keeping the message's version when the lookup fails.
+func (w *Worker) configVersion(ctx context.Context, dispatch queue.DispatchMessage) int64 {
+	if dispatch.CampaignID == uuid.Nil {
+		return dispatch.ConfigVersion
+	}
+	settings, err := w.settings(ctx, dispatch.CampaignID)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		w.container.Logger.Warn("retry worker: resolve campaign settings", zap.Error(err), zap.String("campaign_id", dispatch.CampaignID.String()))
+		return dispatch.ConfigVersion
+	}
+	return settings.Version
+}
+
+// untrack removes the retry from its campaign's backlog so the scheduler stops holding back new calls for it.
+func (w *Worker) untrack(ctx context.Context, dispatch queue.DispatchMessage) {
+	if err := w.backlog.Remove(ctx, dispatch.CampaignID, dispatch.CallID); err != nil {
+		w.container.Logger.Warn("retry worker: untrack retry backlog", zap.Error(err), zap.String("call_id", dispatch.CallID.String()))
 	}
 }
 
@@ -0,0 +1,210 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/acme/outbound-call-campaign/internal/app"
+	"github.com/acme/outbound-call-campaign/internal/domain"
+	"github.com/acme/outbound-call-campaign/internal/queue"
+	"github.com/acme/outbound-call-campaign/internal/repository"
+	"github.com/acme/outbound-call-campaign/pkg/logger"
+)
+
+type fakeCampaigns struct {
+	repository.CampaignRepository
+	campaigns map[uuid.UUID]*domain.Campaign
+}
+
+func (f *fakeCampaigns) Get(_ context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	c, ok := f.campaigns[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+type fakeDispatcher struct {
+	dispatched []queue.DispatchMessage
+	err        error
+}
+
+func (f *fakeDispatcher) DispatchCall(_ context.Context, msg queue.DispatchMessage) error {
+	f.dispatched = append(f.dispatched, msg)
+	return f.err
+}
+
+// fakeStatus records the status reported for each call, with its reason.
+type fakeStatus struct {
+	reported map[uuid.UUID]string
+}
+
+func (f *fakeStatus) PublishCancelled(_ context.Context, dispatch queue.DispatchMessage) error {
+	f.reported[dispatch.CallID] = string(domain.CallStatusCancelled)
+	return nil
+}
+
+func (f *fakeStatus) PublishSkipped(_ context.Context, dispatch queue.DispatchMessage, reason string) error {
+	f.reported[dispatch.CallID] = string(domain.CallStatusSkipped) + ": " + reason
+	return nil
+}
+
+func (f *fakeStatus) PublishUndelivered(_ context.Context, dispatch queue.DispatchMessage, reason string) error {
+	f.reported[dispatch.CallID] = string(domain.CallStatusFailed) + ": " + reason
+	return nil
+}
+
+type fakeBacklog struct {
+	removed []uuid.UUID
+}
+
+func (f *fakeBacklog) Remove(_ context.Context, _, callID uuid.UUID) error {
+	f.removed = append(f.removed, callID)
+	return nil
+}
+
+type testWorker struct {
+	*Worker
+	campaigns  *fakeCampaigns
+	dispatcher *fakeDispatcher
+	status     *fakeStatus
+	backlog    *fakeBacklog
+}
+
+func newTestWorker() *testWorker {
+	tw := &testWorker{
+		campaigns:  &fakeCampaigns{campaigns: make(map[uuid.UUID]*domain.Campaign)},
+		dispatcher: &fakeDispatcher{},
+		status:     &fakeStatus{reported: make(map[uuid.UUID]string)},
+		backlog:    &fakeBacklog{},
+	}
+	tw.Worker = &Worker{
+		container: &app.Container{Logger: &logger.Logger{Logger: zap.NewNop()}},
+		campaigns: tw.campaigns,
+		settings: func(_ context.Context, id uuid.UUID) (domain.CampaignSettings, error) {
+			return domain.CampaignSettings{CampaignID: id, Version: 7}, nil
+		},
+		dispatcher:      tw.dispatcher,
+		status:          tw.status,
+		backlog:         tw.backlog,
+		dispatchBackoff: time.Millisecond,
+	}
+	return tw
+}
+
+// retryOf stores campaign and returns a due retry of one of its calls.
+func (tw *testWorker) retryOf(t *testing.T, campaign domain.Campaign) (kafka.Message, uuid.UUID) {
+	t.Helper()
+	campaign.ID = uuid.New()
+	tw.campaigns.campaigns[campaign.ID] = &campaign
+	callID := uuid.New()
+	value, err := json.Marshal(queue.RetryMessage{
+		DispatchMessage: queue.DispatchMessage{CallID: callID, CampaignID: campaign.ID, Attempt: 2, ConfigVersion: 3},
+		NextAttempt:     time.Now().Add(-time.Second),
+	})
+	if err != nil {
+		t.Fatalf("marshal retry: %v", err)
+	}
+	return kafka.Message{Value: value}, callID
+}
+
+func TestHandleDispatchesDueRetry(t *testing.T) {
+	tw := newTestWorker()
+	msg, callID := tw.retryOf(t, domain.Campaign{Status: domain.CampaignStatusInProgress})
+
+	if err := tw.handle(context.Background(), msg); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(tw.dispatcher.dispatched) != 1 || tw.dispatcher.dispatched[0].CallID != callID {
+		t.Fatalf("dispatched %+v, want the retry of call %s", tw.dispatcher.dispatched, callID)
+	}
+	if got := tw.dispatcher.dispatched[0].ConfigVersion; got != 7 {
+		t.Errorf("dispatched under config version %d, want the current 7", got)
+	}
+	if len(tw.backlog.removed) != 1 || tw.backlog.removed[0] != callID {
+		t.Errorf("untracked %v, want call %s", tw.backlog.removed, callID)
+	}
+	if len(tw.status.reported) != 0 {
+		t.Errorf("reported %v for a dispatched retry, want nothing", tw.status.reported)
+	}
+}
+
+func TestHandleDropsRetriesOfStoppedCampaigns(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	cases := []struct {
+		name     string
+		campaign domain.Campaign
+		want     string
+	}{
+		{name: "cancelled", campaign: domain.Campaign{Status: domain.CampaignStatusCancelled}, want: string(domain.CallStatusCancelled)},
+		{name: "completed", campaign: domain.Campaign{Status: domain.CampaignStatusCompleted}, want: "skipped: " + reasonCampaignCompleted},
+		{name: "failed", campaign: domain.Campaign{Status: domain.CampaignStatusFailed}, want: "skipped: " + reasonCampaignFailed},
+		{name: "past its end", campaign: domain.Campaign{Status: domain.CampaignStatusInProgress, EndAt: &past}, want: "skipped: " + reasonCampaignEnded},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tw := newTestWorker()
+			msg, callID := tw.retryOf(t, tc.campaign)
+
+			if err := tw.handle(context.Background(), msg); err != nil {
+				t.Fatalf("handle: %v", err)
+			}
+			if len(tw.dispatcher.dispatched) != 0 {
+				t.Fatalf("dispatched a retry of a %s campaign", tc.name)
+			}
+			if got := tw.status.reported[callID]; got != tc.want {
+				t.Errorf("reported %q, want %q", got, tc.want)
+			}
+			if len(tw.backlog.removed) != 1 {
+				t.Errorf("untracked %v, want the dropped retry", tw.backlog.removed)
+			}
+		})
+	}
+}
+
+func TestHandleReportsRetryThatCannotBeDispatched(t *testing.T) {
+	tw := newTestWorker()
+	tw.dispatcher.err = errors.New("broker unavailable")
+	msg, callID := tw.retryOf(t, domain.Campaign{Status: domain.CampaignStatusInProgress})
+
+	if err := tw.handle(context.Background(), msg); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(tw.dispatcher.dispatched) != dispatchAttempts {
+		t.Errorf("offered the retry %d times, want %d", len(tw.dispatcher.dispatched), dispatchAttempts)
+	}
+	if got := tw.status.reported[callID]; !strings.HasPrefix(got, "failed: ") || !strings.Contains(got, "broker unavailable") {
+		t.Errorf("reported %q, want the call failed with the dispatch error", got)
+	}
+	if len(tw.backlog.removed) != 1 {
+		t.Errorf("untracked %v, want the abandoned retry", tw.backlog.removed)
+	}
+}
+
+func TestHandleLeavesRetryToRedeliveryOnShutdown(t *testing.T) {
+	tw := newTestWorker()
+	msg, _ := tw.retryOf(t, domain.Campaign{Status: domain.CampaignStatusInProgress})
+	var retry queue.RetryMessage
+	_ = json.Unmarshal(msg.Value, &retry)
+	retry.NextAttempt = time.Now().Add(time.Hour)
+	msg.Value, _ = json.Marshal(retry)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tw.handle(ctx, msg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("handle = %v, want context.Canceled so the message is not committed", err)
+	}
+	if len(tw.dispatcher.dispatched) != 0 || len(tw.backlog.removed) != 0 || len(tw.status.reported) != 0 {
+		t.Errorf("settled a retry that is still to be redelivered")
+	}
+}
@@ -37,7 +37,9 @@ func (w *Worker) Run(ctx context.Context) error {
 	repos := w.container.Repositories()
 	store := repos.CallStore
 	statsRepo := repos.Stats
+	targetRepo := repos.Targets
 	retryScheduler := w.container.Dispatchers().RetryScheduler
+	backlog := w.container.Limiters().RetryBacklog
 	logger := w.container.Logger
 
 	for {
@@ -65,12 +67,28 @@ func (w *Worker) Run(ctx context.Context) error {
 		))
 		defer span.End()
 
+		w.applySettings(sctx, &status)
+
 		domainStatus := domain.CallStatus(status.Status)
 		if err := store.UpdateCallStatus(sctx, status.CallID, domainStatus, status.Attempt, optionalString(status.Error)); err != nil {
 			span.RecordError(err)
 			logger.Error("status worker: update call", zap.Error(err))
 		}
 
+		if err := w.syncTarget(sctx, targetRepo, status); err != nil {
+			span.RecordError(err)
+			logger.Error("status worker: sync target", zap.Error(err))
+		}
+
+		// A dialing report only marks the start of an attempt, not its outcome.
+		if domainStatus == domain.CallStatusDialing {
+			if err := reader.CommitMessages(sctx, msg); err != nil {
+				span.RecordError(err)
+				logger.Error("status worker: commit", zap.Error(err))
+			}
+			continue
+		}
+
 		attempt := domain.CallAttempt{
 			ID:         uuid.New(),
 			CallID:     status.CallID,
@@ -87,7 +105,7 @@ func (w *Worker) Run(ctx context.Context) error {
 
 		delta := repository.StatsDelta{}
 		if status.CampaignID != uuid.Nil {
-			if status.Attempt > 1 {
+			if status.Attempt > 1 && placed(domainStatus) {
 				delta.RetriesDelta++
 			}
 			switch domainStatus {
@@ -99,6 +117,8 @@ func (w *Worker) Run(ctx context.Context) error {
 					delta.FailedCallsDelta++
 					delta.PendingCallsDelta--
 				}
+			case domain.CallStatusCancelled, domain.CallStatusSkipped, domain.CallStatusDeferred:
+				delta.PendingCallsDelta--
 			}
 
 			if err := statsRepo.ApplyDelta(sctx, status.CampaignID, delta); err != nil {
@@ -110,24 +130,30 @@ func (w *Worker) Run(ctx context.Context) error {
 		if status.Retryable && status.NextAttempt != nil {
 			retryMsg := queue.RetryMessage{
 				DispatchMessage: queue.DispatchMessage{
-					CallID:           status.CallID,
-					CampaignID:       status.CampaignID,
-					PhoneNumber:      status.PhoneNumber,
-					Attempt:          status.Attempt + 1,
-					MaxAttempts:      status.MaxAttempts,
-					RetryBaseMs:      status.RetryBaseMs,
-					RetryMaxMs:       status.RetryMaxMs,
-					RetryJitter:      status.RetryJitter,
-					ConcurrencyLimit: status.ConcurrencyLimit,
-					Metadata:         status.Metadata,
-					EnqueuedAt:       *status.NextAttempt,
+					CallID:        status.CallID,
+					CampaignID:    status.CampaignID,
+					TargetID:      status.TargetID,
+					PhoneNumber:   status.PhoneNumber,
+					TimeZone:      status.TimeZone,
+					Attempt:       status.Attempt + 1,
+					ConfigVersion: status.ConfigVersion,
+					Metadata:      status.Metadata,
+					EnqueuedAt:    *status.NextAttempt,
+					EndAt:         status.EndAt,
 				},
-				MaxAttempts: status.MaxAttempts,
 				NextAttempt: *status.NextAttempt,
 			}
+			// Track the retry before publishing it so the retry worker can never untrack it first.
+			if err := backlog.Add(sctx, status.CampaignID, status.CallID, *status.NextAttempt); err != nil {
+				span.RecordError(err)
+				logger.Warn("status worker: track retry backlog", zap.Error(err))
+			}
 			if err := retryScheduler.ScheduleRetry(sctx, status.Attempt, retryMsg); err != nil {
 				span.RecordError(err)
 				logger.Error("status worker: schedule retry", zap.Error(err))
+				if err := backlog.Remove(sctx, status.CampaignID, status.CallID); err != nil {
+					logger.Warn("status worker: untrack retry backlog", zap.Error(err))
+				}
 			}
 		}
 
@@ -138,6 +164,81 @@ func (w *Worker) Run(ctx context.Context) error {
 	}
 }
 
+// applySettings re-checks a retryable outcome against the campaign's current attempt limit, so a
+// limit lowered since the call was placed stops the retry. Lookup failures keep the reported
+// decision.
+func (w *Worker) applySettings(ctx context.Context, status *queue.StatusMessage) {
+	if !status.Retryable || status.CampaignID == uuid.Nil {
+		return
+	}
+	settings, err := w.container.Services().Call.Settings(ctx, status.CampaignID)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		w.container.Logger.Warn("status worker: resolve campaign settings", zap.Error(err), zap.String("campaign_id", status.CampaignID.String()))
+		return
+	}
+	status.ConfigVersion = settings.Version
+	if status.Attempt >= settings.RetryPolicy.MaxAttempts {
+		status.Retryable = false
+		status.NextAttempt = nil
+	}
+}
+
+// syncTarget moves the campaign target behind the call to the state implied by the report.
+// Reports for in-flight states never overwrite a target that has already finished.
+func (w *Worker) syncTarget(ctx context.Context, targets repository.CampaignTargetRepository, status queue.StatusMessage) error {
+	if status.CampaignID == uuid.Nil {
+		return nil
+	}
+	domainStatus := domain.CallStatus(status.Status)
+	state := domain.TargetStateForCall(domainStatus, status.Retryable)
+	if state == "" {
+		return nil
+	}
+
+	update := repository.TargetCallUpdate{
+		CampaignID: status.CampaignID,
+		TargetID:   status.TargetID,
+		CallID:     status.CallID,
+		State:      state,
+		Attempt:    status.Attempt,
+		OccurredAt: status.OccurredAt,
+		Dialed:     domainStatus == domain.CallStatusDialing,
+	}
+	// Cancelled, deferred and skipped attempts were never placed; the last two record why and give
+	// up their call so the target is claimed afresh, a deferred one only once its time has come.
+	switch domainStatus {
+	case domain.CallStatusCancelled:
+		update.Attempt = status.Attempt - 1
+	case domain.CallStatusDeferred:
+		update.Attempt = status.Attempt - 1
+		update.Reason = status.Error
+		update.Detach = true
+		update.ScheduledAt = status.NextAttempt
+	case domain.CallStatusSkipped:
+		update.Attempt = status.Attempt - 1
+		update.Reason = status.Error
+		update.Detach = true
+	}
+	if !domain.IsTerminalTargetState(state) {
+		update.OnlyFrom = domain.ActiveTargetStates
+	}
+
+	if _, err := targets.ApplyCallUpdate(ctx, update); err != nil {
+		return err
+	}
+	return nil
+}
+
+// placed reports whether a call that ended in status was actually dialed.
+func placed(status domain.CallStatus) bool {
+	switch status {
+	case domain.CallStatusCancelled, domain.CallStatusSkipped, domain.CallStatusDeferred:
+		return false
+	}
+	return true
+}
+
 func optionalString(value string) *string {
 	if value == "" {
 		return nil
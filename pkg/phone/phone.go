@@ -0,0 +1,157 @@
+// Package phone parses and normalises telephone numbers to E.164.
+package phone
+
+import (
+	"fmt"
+	"strings"
+
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+// ErrInvalid is returned for numbers that cannot be normalised. It wraps apperrors.ErrValidation.
+var ErrInvalid = fmt.Errorf("%w: invalid phone number", apperrors.ErrValidation)
+
+// region describes national numbering for a country.
+type region struct {
+	code string // country calling code
+	min  int    // shortest national significant number
+	max  int    // longest national significant number
+	// trunk is the national dialling prefix dropped when converting to E.164.
+	trunk string
+}
+
+// regions lists the countries for which national-format numbers are understood.
+// Numbers already in international format are accepted for any calling code.
+var regions = map[string]region{
+	"US": {code: "1", min: 10, max: 10, trunk: "1"},
+	"CA": {code: "1", min: 10, max: 10, trunk: "1"},
+	"PR": {code: "1", min: 10, max: 10, trunk: "1"},
+	"GB": {code: "44", min: 9, max: 10, trunk: "0"},
+	"IE": {code: "353", min: 7, max: 9, trunk: "0"},
+	"DE": {code: "49", min: 6, max: 13, trunk: "0"},
+	"FR": {code: "33", min: 9, max: 9, trunk: "0"},
+	"ES": {code: "34", min: 9, max: 9},
+	"IT": {code: "39", min: 6, max: 11},
+	"NL": {code: "31", min: 9, max: 9, trunk: "0"},
+	"SE": {code: "46", min: 7, max: 9, trunk: "0"},
+	"CH": {code: "41", min: 9, max: 9, trunk: "0"},
+	"IN": {code: "91", min: 10, max: 10, trunk: "0"},
+	"PK": {code: "92", min: 9, max: 10, trunk: "0"},
+	"AE": {code: "971", min: 8, max: 9, trunk: "0"},
+	"SG": {code: "65", min: 8, max: 8},
+	"HK": {code: "852", min: 8, max: 8},
+	"PH": {code: "63", min: 10, max: 10, trunk: "0"},
+	"JP": {code: "81", min: 9, max: 10, trunk: "0"},
+	"CN": {code: "86", min: 10, max: 11, trunk: "0"},
+	"AU": {code: "61", min: 9, max: 9, trunk: "0"},
+	"NZ": {code: "64", min: 8, max: 10, trunk: "0"},
+	"BR": {code: "55", min: 10, max: 11, trunk: "0"},
+	"MX": {code: "52", min: 10, max: 10},
+	"ZA": {code: "27", min: 9, max: 9, trunk: "0"},
+	"NG": {code: "234", min: 8, max: 10, trunk: "0"},
+	"KE": {code: "254", min: 9, max: 9, trunk: "0"},
+}
+
+// byCode indexes regions by calling code for validating international numbers.
+var byCode = func() map[string]region {
+	m := make(map[string]region, len(regions))
+	for _, r := range regions {
+		m[r.code] = r
+	}
+	return m
+}()
+
+const (
+	minE164Digits = 8
+	maxE164Digits = 15
+)
+
+// SupportedRegion reports whether national-format numbers can be parsed for the ISO 3166 region.
+func SupportedRegion(regionCode string) bool {
+	_, ok := regions[strings.ToUpper(regionCode)]
+	return ok
+}
+
+// Normalize converts raw to E.164 ("+" followed by digits). Numbers written in international form
+// ("+44 20 7946 0000", "0044 ...") are accepted as is; anything else is read as a national number
+// of defaultRegion, which may be empty when only international input is expected.
+func Normalize(raw, defaultRegion string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", fmt.Errorf("%w: phone number is empty", ErrInvalid)
+	}
+
+	international := false
+	var digits strings.Builder
+	for i, r := range trimmed {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			international = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", fmt.Errorf("%w: %q contains unexpected character %q", ErrInvalid, raw, r)
+		}
+	}
+	number := digits.String()
+
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+
+	if international {
+		return validateInternational(raw, number)
+	}
+
+	if defaultRegion == "" {
+		return "", fmt.Errorf("%w: %q must be in international format (+<country code>...)", ErrInvalid, raw)
+	}
+	reg, ok := regions[strings.ToUpper(defaultRegion)]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported region %q", apperrors.ErrValidation, defaultRegion)
+	}
+
+	// Dialled with the country code but without "+", e.g. 15550100000 or 447946000000.
+	if strings.HasPrefix(number, reg.code) {
+		national := number[len(reg.code):]
+		if len(national) >= reg.min && len(national) <= reg.max {
+			return validateInternational(raw, number)
+		}
+	}
+
+	national := number
+	if reg.trunk != "" && strings.HasPrefix(national, reg.trunk) && len(national)-len(reg.trunk) >= reg.min {
+		national = national[len(reg.trunk):]
+	}
+	if len(national) < reg.min || len(national) > reg.max {
+		return "", fmt.Errorf("%w: %q is not a valid %s number", ErrInvalid, raw, strings.ToUpper(defaultRegion))
+	}
+	return validateInternational(raw, reg.code+national)
+}
+
+func validateInternational(raw, number string) (string, error) {
+	if len(number) < minE164Digits || len(number) > maxE164Digits {
+		return "", fmt.Errorf("%w: %q must have between %d and %d digits", ErrInvalid, raw, minE164Digits, maxE164Digits)
+	}
+	if number[0] == '0' {
+		return "", fmt.Errorf("%w: %q has no valid country code", ErrInvalid, raw)
+	}
+
+	for n := 1; n <= 3 && n < len(number); n++ {
+		reg, ok := byCode[number[:n]]
+		if !ok {
+			continue
+		}
+		national := number[n:]
+		if len(national) < reg.min || len(national) > reg.max {
+			return "", fmt.Errorf("%w: %q has the wrong length for country code +%s", ErrInvalid, raw, reg.code)
+		}
+		if reg.code == "1" && (national[0] < '2' || national[0] > '9') {
+			return "", fmt.Errorf("%w: %q has an invalid area code", ErrInvalid, raw)
+		}
+		break
+	}
+	return "+" + number, nil
+}
@@ -0,0 +1,70 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/acme/outbound-call-campaign/pkg/errors"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		raw    string
+		region string
+		want   string
+	}{
+		{"+1 (555) 010-0000", "", "+15550100000"},
+		{"15550100000", "US", "+15550100000"},
+		{"(555) 010-0000", "US", "+15550100000"},
+		{"555.010.0000", "CA", "+15550100000"},
+		{"1-555-010-0000", "US", "+15550100000"},
+		{"020 7946 0000", "GB", "+442079460000"},
+		{"+44 20 7946 0000", "US", "+442079460000"},
+		{"0044 20 7946 0000", "US", "+442079460000"},
+		{"447946000000", "GB", "+447946000000"},
+		{"09876543210", "IN", "+919876543210"},
+		{"06 12 34 56 78", "FR", "+33612345678"},
+		{"+49 30 123456", "", "+4930123456"},
+		{"+972 3 123 4567", "", "+97231234567"},
+	}
+
+	for _, tc := range cases {
+		got, err := Normalize(tc.raw, tc.region)
+		if err != nil {
+			t.Errorf("Normalize(%q, %q) unexpected error: %v", tc.raw, tc.region, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tc.raw, tc.region, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeRejectsInvalid(t *testing.T) {
+	cases := []struct {
+		raw    string
+		region string
+	}{
+		{"", "US"},
+		{"not a number", "US"},
+		{"555-0100", "US"},
+		{"+1 055 010 0000", ""},
+		{"+1 555 010 00000", ""},
+		{"5550100000", ""},
+		{"5550100000", "ZZ"},
+		{"+0123456789", ""},
+		{"+1234", ""},
+		{"555 010 0000 x12", "US"},
+	}
+
+	for _, tc := range cases {
+		_, err := Normalize(tc.raw, tc.region)
+		if err == nil {
+			t.Errorf("Normalize(%q, %q) expected error", tc.raw, tc.region)
+			continue
+		}
+		if !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Normalize(%q, %q) error %v does not wrap ErrValidation", tc.raw, tc.region, err)
+		}
+	}
+}
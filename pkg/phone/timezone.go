@@ -0,0 +1,51 @@
+package phone
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// timeZoneData maps number prefixes to IANA time zones; see the header of the file.
+//
+//go:embed data/timezones.csv
+var timeZoneData []byte
+
+var (
+	zonesOnce  sync.Once
+	zones      map[string]string
+	longestKey int
+)
+
+func loadZones() {
+	zones = make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(timeZoneData))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "prefix,zone" {
+			continue
+		}
+		prefix, zone, ok := strings.Cut(line, ",")
+		if !ok {
+			continue
+		}
+		zones[prefix] = zone
+		longestKey = max(longestKey, len(prefix))
+	}
+}
+
+// TimeZone returns the IANA time zone of an E.164 number from the bundled prefix dataset. It
+// reports false when the number's prefix is unknown or spans several zones.
+func TimeZone(e164 string) (string, bool) {
+	zonesOnce.Do(loadZones)
+
+	digits := strings.TrimPrefix(e164, "+")
+	for n := min(len(digits), longestKey); n > 0; n-- {
+		if zone, ok := zones[digits[:n]]; ok {
+			return zone, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,45 @@
+package phone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeZone(t *testing.T) {
+	cases := []struct {
+		number string
+		want   string
+	}{
+		{"+12125550100", "America/New_York"},
+		{"+14155550100", "America/Los_Angeles"},
+		{"+13125550100", "America/Chicago"},
+		{"+16025550100", "America/Phoenix"},
+		{"+16045550100", "America/Vancouver"},
+		{"+442079460000", "Europe/London"},
+		{"+61889123456", "Australia/Darwin"},
+		{"+61881234567", "Australia/Adelaide"},
+		{"+34928123456", "Atlantic/Canary"},
+		{"+34912345678", "Europe/Madrid"},
+	}
+	for _, tc := range cases {
+		got, ok := TimeZone(tc.number)
+		if !ok || got != tc.want {
+			t.Errorf("TimeZone(%q) = %q, %v; want %q", tc.number, got, ok, tc.want)
+		}
+	}
+
+	for _, number := range []string{"+18505550100", "+79161234567", "+61412345678"} {
+		if zone, ok := TimeZone(number); ok {
+			t.Errorf("TimeZone(%q) = %q; want no zone for an ambiguous prefix", number, zone)
+		}
+	}
+}
+
+func TestTimeZoneDataLoads(t *testing.T) {
+	zonesOnce.Do(loadZones)
+	for prefix, zone := range zones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			t.Errorf("prefix %s: unknown zone %q: %v", prefix, zone, err)
+		}
+	}
+}
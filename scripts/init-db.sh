@@ -128,10 +128,13 @@ run_psql_superuser "ALTER DATABASE \"$POSTGRES_APP_DB\" OWNER TO \"$POSTGRES_APP
 run_psql_superuser "CREATE EXTENSION IF NOT EXISTS citus;" 2>/dev/null || echo "Note: Citus extension not available (this is normal on macOS local setup)"
 
 echo "Running PostgreSQL migrations..."
-# Extract only the Up migration (stop before Down section)
-sed '/^-- +goose Down$/q' "$PROJECT_ROOT/db/migrations/postgres/0001_init.sql" | \
-PGPASSWORD="$POSTGRES_APP_PASSWORD" psql -h "$POSTGRES_HOST" -p "$POSTGRES_PORT" -U "$POSTGRES_APP_USER" -d "$POSTGRES_APP_DB" \
-    -v ON_ERROR_STOP=1
+# Apply migrations in order, extracting only the Up section (stop before Down section)
+for migration in "$PROJECT_ROOT"/db/migrations/postgres/*.sql; do
+    echo "Applying $(basename "$migration")..."
+    sed '/^-- +goose Down$/q' "$migration" | \
+    PGPASSWORD="$POSTGRES_APP_PASSWORD" psql -h "$POSTGRES_HOST" -p "$POSTGRES_PORT" -U "$POSTGRES_APP_USER" -d "$POSTGRES_APP_DB" \
+        -v ON_ERROR_STOP=1
+done
 
 # Initialize ScyllaDB/Cassandra
 echo ""
@@ -82,6 +82,10 @@ simple_start() {
     CONFIG_FILE=${CONFIG_FILE:-configs/config.yaml} go run ./cmd/retryworker --config "$CONFIG_FILE" >> logs/retryworker.log 2>&1 &
     RETRYWORKER_PID=$!
 
+    echo "Starting outbox relay..."
+    CONFIG_FILE=${CONFIG_FILE:-configs/config.yaml} go run ./cmd/outboxrelay --config "$CONFIG_FILE" >> logs/outboxrelay.log 2>&1 &
+    OUTBOXRELAY_PID=$!
+
     echo "Starting scheduler..."
     CONFIG_FILE=${CONFIG_FILE:-configs/config.yaml} go run ./cmd/scheduler --config "$CONFIG_FILE" >> logs/scheduler.log 2>&1 &
     SCHEDULER_PID=$!
@@ -93,7 +97,7 @@ simple_start() {
     echo ""
 
     # Wait for interrupt signal
-    trap 'echo "Stopping services..."; kill $API_PID $CALLWORKER_PID $STATUSWORKER_PID $RETRYWORKER_PID $SCHEDULER_PID 2>/dev/null; exit 0' INT TERM
+    trap 'echo "Stopping services..."; kill $API_PID $CALLWORKER_PID $STATUSWORKER_PID $RETRYWORKER_PID $OUTBOXRELAY_PID $SCHEDULER_PID 2>/dev/null; exit 0' INT TERM
 
     # Keep running
     wait